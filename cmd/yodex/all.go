@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+
+	cfgpkg "yodex/internal/config"
 )
 
 // yodex all (optional convenience)
@@ -31,42 +33,62 @@ func cmdAll(args []string) error {
 		return err
 	}
 
-	// Share parsed flags to individual steps and log progress.
 	setupLogger(cf.logLevel)
-	slog.Info("running all steps")
-	scriptArgs := []string{}
+	if cf.show != cfgpkg.AllShows {
+		return runAllSteps(cf, cf.show, voice, overwrite, bucket, prefix, region)
+	}
+	if prefix.set {
+		return fmt.Errorf("--prefix cannot be combined with --show=%s; set s3Prefix per show instead", cfgpkg.AllShows)
+	}
+	fileCfg, err := cfgpkg.LoadFile(cf.config)
+	if err != nil {
+		return err
+	}
+	shows := cfgpkg.ShowNames(fileCfg)
+	if len(shows) == 0 {
+		return errors.New("--show=* requires a shows map in the config")
+	}
+	// One failing show should not stop the others; report every failure.
+	var errs []error
+	for _, show := range shows {
+		if err := runAllSteps(cf, show, voice, overwrite, bucket, prefix, region); err != nil {
+			slog.Error("show failed", "show", show, "err", err)
+			errs = append(errs, fmt.Errorf("show %s: %w", show, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runAllSteps runs script, audio, and publish for one show ("" for the
+// top-level settings).
+func runAllSteps(cf commonFlags, show string, voice stringFlag, overwrite boolFlag, bucket, prefix, region stringFlag) error {
+	// Share parsed flags to individual steps and log progress.
+	slog.Info("running all steps", "show", show)
+	common := []string{}
 	if cf.date != "" {
-		scriptArgs = append(scriptArgs, "--date", cf.date)
+		common = append(common, "--date", cf.date)
 	}
 	if cf.config != "" {
-		scriptArgs = append(scriptArgs, "--config", cf.config)
+		common = append(common, "--config", cf.config)
+	}
+	if show != "" {
+		common = append(common, "--show", show)
 	}
+	scriptArgs := append([]string{}, common...)
 	if overwrite.set {
 		scriptArgs = append(scriptArgs, "--overwrite", fmt.Sprint(overwrite.v))
 	}
 	if err := cmdScript(scriptArgs); err != nil {
 		return err
 	}
-	audioArgs := []string{}
-	if cf.date != "" {
-		audioArgs = append(audioArgs, "--date", cf.date)
-	}
-	if cf.config != "" {
-		audioArgs = append(audioArgs, "--config", cf.config)
-	}
+	audioArgs := append([]string{}, common...)
 	if voice.set {
 		audioArgs = append(audioArgs, "--voice", voice.v)
 	}
 	if err := cmdAudio(audioArgs); err != nil {
 		return err
 	}
-	publishArgs := []string{}
-	if cf.date != "" {
-		publishArgs = append(publishArgs, "--date", cf.date)
-	}
-	if cf.config != "" {
-		publishArgs = append(publishArgs, "--config", cf.config)
-	}
+	publishArgs := append([]string{}, common...)
 	if bucket.set {
 		publishArgs = append(publishArgs, "--bucket", bucket.v)
 	}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"yodex/internal/ai"
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/paths"
+)
+
+func TestAllRunsEveryShow(t *testing.T) {
+	fake := &fakeTextClient{responses: append(makeSectionResponses(100), makeSectionResponses(100)...)}
+	cfgPath := setupScriptConfigTest(t, `{
+		"s3Bucket": "b",
+		"shows": {
+			"kids": {"topic": "Bees"},
+			"teens": {"topic": "Black Holes", "voice": "nova", "ageRange": "10–12"}
+		}
+	}`, fake)
+	stubTopicHistory(t)
+
+	origConcat := concatMP3
+	origLongPausePath := longPauseAudioPath
+	origShortPausePath := shortPauseAudioPath
+	origTTS := newTTSClient
+	origUploader := newUploader
+	t.Cleanup(func() {
+		concatMP3 = origConcat
+		longPauseAudioPath = origLongPausePath
+		shortPauseAudioPath = origShortPausePath
+		newTTSClient = origTTS
+		newUploader = origUploader
+	})
+	concatMP3 = concatMP3ByCopy
+	pauseDir := t.TempDir()
+	longPauseAudioPath = filepath.Join(pauseDir, "pause6s.mp3")
+	shortPauseAudioPath = filepath.Join(pauseDir, "pause3s.mp3")
+	for _, p := range []string{longPauseAudioPath, shortPauseAudioPath} {
+		if err := os.WriteFile(p, []byte("pausebytes"), 0o644); err != nil {
+			t.Fatalf("write pause audio: %v", err)
+		}
+	}
+	voices := map[string]bool{}
+	newTTSClient = func(cfg cfgpkg.Config) (ai.TTSClient, error) {
+		voices[cfg.Voice] = true
+		return &fakeTTSClient{}, nil
+	}
+	var prefixes []string
+	newUploader = func(ctx context.Context, bucket, prefix, region string) (uploader, error) {
+		prefixes = append(prefixes, prefix)
+		return &fakeUploader{}, nil
+	}
+
+	if code := run([]string{"all", "--date=2025-09-30", "--show=*", "--config", cfgPath}); code != 0 {
+		t.Fatalf("all returned non-zero: %d", code)
+	}
+
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	for _, show := range []struct{ name, topic string }{{"kids", "Bees"}, {"teens", "Black Holes"}} {
+		builder := paths.New(filepath.Join("out", show.name))
+		if _, err := os.Stat(builder.EpisodeMP3(date)); err != nil {
+			t.Fatalf("missing %s episode.mp3: %v", show.name, err)
+		}
+		data, err := os.ReadFile(builder.EpisodeMeta(date))
+		if err != nil {
+			t.Fatalf("read %s meta.json: %v", show.name, err)
+		}
+		var meta scriptMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			t.Fatalf("parse meta.json: %v", err)
+		}
+		if meta.Show != show.name || meta.Topic != show.topic {
+			t.Fatalf("unexpected %s meta: %+v", show.name, meta)
+		}
+	}
+	if len(prefixes) != 2 || prefixes[0] != "yodex/kids" || prefixes[1] != "yodex/teens" {
+		t.Fatalf("expected per-show prefixes, got %v", prefixes)
+	}
+	if !voices["alloy"] || !voices["nova"] {
+		t.Fatalf("expected per-show voices, got %v", voices)
+	}
+}
+
+func TestAllRejectsSharedPrefixForEveryShow(t *testing.T) {
+	cfgPath := setupScriptConfigTest(t, `{"shows": {"kids": {}}}`, &fakeTextClient{})
+	if code := run([]string{"all", "--show=*", "--prefix=shared", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected --prefix with --show=* to fail")
+	}
+	if code := run([]string{"script", "--show=*", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected --show=* to be rejected outside yodex all")
+	}
+}
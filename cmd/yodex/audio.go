@@ -10,7 +10,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"yodex/internal/ai"
 	cfgpkg "yodex/internal/config"
@@ -18,11 +20,6 @@ import (
 	"yodex/internal/podcast"
 )
 
-const (
-	longPauseTag  = "[long pause]"
-	shortPauseTag = "[short pause]"
-)
-
 var longPauseAudioPath = filepath.Join("assets", "audio", "pause6s.mp3")
 var shortPauseAudioPath = filepath.Join("assets", "audio", "pause3s.mp3")
 
@@ -36,6 +33,8 @@ var newTTSClient = func(cfg cfgpkg.Config) (ai.TTSClient, error) {
 		return ai.New(cfg.OpenAIAPIKey, "")
 	case "elevenlabs":
 		return ai.NewElevenLabs(cfg.ElevenLabsAPIKey)
+	case "command":
+		return ai.NewCommandTTS(cfg.TTSCommand)
 	default:
 		return nil, fmt.Errorf("unsupported tts provider: %s", cfg.TTSProvider)
 	}
@@ -57,11 +56,7 @@ func cmdAudio(args []string) error {
 		return err
 	}
 	setupLogger(cf.logLevel)
-	date, err := resolveDate(cf.date)
-	if err != nil {
-		return err
-	}
-	fileCfg, err := cfgpkg.LoadFile(cf.config)
+	fileCfg, err := loadFileConfig(cf)
 	if err != nil {
 		return err
 	}
@@ -71,24 +66,36 @@ func cmdAudio(args []string) error {
 		flagOv.Voice = &voice.v
 	}
 	cfg := cfgpkg.Merge(fileCfg, envOv, flagOv, apiKey, elevenLabsKey)
+	date, err := resolveDate(cf.date, cfg)
+	if err != nil {
+		return err
+	}
 
 	if err := cfgpkg.ValidateForAudio(cfg); err != nil {
 		return err
 	}
 
+	if cfg, err = localizeTTSModel(cfg); err != nil {
+		return err
+	}
+
 	client, err := newTTSClient(cfg)
 	if err != nil {
 		return err
 	}
 	ctx := context.Background()
 
-	builder := paths.New("")
+	builder := paths.New(cfg.OutDir)
 	mdPath := builder.EpisodeMarkdown(date)
 	mp3Path := builder.EpisodeMP3(date)
 	if err := builder.EnsureOutDir(date); err != nil {
 		return err
 	}
-	sectionIDs := podcast.StandardSectionIDs()
+	show, err := episodeShow(cfg, builder.EpisodeMeta(date))
+	if err != nil {
+		return err
+	}
+	sectionIDs := show.SectionIDs()
 	sectionFiles := make([]string, 0, len(sectionIDs))
 	for _, sectionID := range sectionIDs {
 		sectionFiles = append(sectionFiles, builder.EpisodeSectionMarkdown(date, sectionID))
@@ -96,22 +103,33 @@ func cmdAudio(args []string) error {
 	useSections := allFilesExist(sectionFiles)
 	var mp3Paths []string
 	if useSections {
+		metaPath := builder.EpisodeMeta(date)
+		stale := staleAudioSections(metaPath, builder, date, sectionIDs, cfg.Overwrite)
 		mp3Paths = make([]string, 0, len(sectionIDs)+1)
 		mp3Paths = append(mp3Paths, mp3Path)
 		for _, sectionID := range sectionIDs {
 			mp3Paths = append(mp3Paths, builder.EpisodeSectionMP3(date, sectionID))
 		}
-		if err := paths.CheckOverwrite(mp3Paths, cfg.Overwrite); err != nil {
-			return err
+		if stale == nil {
+			if err := paths.CheckOverwrite(mp3Paths, cfg.Overwrite); err != nil {
+				return err
+			}
+		} else {
+			slog.Info("regenerating stale section audio", "sections", stale)
 		}
-		for _, sectionID := range sectionIDs {
-			sectionPath := builder.EpisodeSectionMarkdown(date, sectionID)
+		for _, section := range show.Sections {
+			if stale != nil && !slices.Contains(stale, section.ID) {
+				continue
+			}
+			sectionPath := builder.EpisodeSectionMarkdown(date, section.ID)
 			text, err := os.ReadFile(sectionPath)
 			if err != nil {
 				return err
 			}
-			outPath := builder.EpisodeSectionMP3(date, sectionID)
-			if err := synthesizeWithPauses(ctx, client, cfg, string(text), outPath); err != nil {
+			sectionCfg := cfg
+			sectionCfg.Voice = voiceForLanguage(cfg, sectionLanguage(cfg, section))
+			outPath := builder.EpisodeSectionMP3(date, section.ID)
+			if err := synthesizeWithPauses(ctx, client, sectionCfg, string(text), outPath); err != nil {
 				return err
 			}
 		}
@@ -122,6 +140,9 @@ func cmdAudio(args []string) error {
 		if err := concatMP3(mp3Path, sectionMP3s); err != nil {
 			return err
 		}
+		if err := clearStaleAudio(metaPath); err != nil {
+			return err
+		}
 	} else {
 		if err := paths.CheckOverwrite([]string{mp3Path}, cfg.Overwrite); err != nil {
 			return err
@@ -146,6 +167,106 @@ func cmdAudio(args []string) error {
 	return nil
 }
 
+// englishOnlyElevenLabsModels cannot voice other languages.
+var englishOnlyElevenLabsModels = map[string]bool{
+	"eleven_monolingual_v1": true,
+	"eleven_turbo_v2":       true,
+	"eleven_flash_v2":       true,
+}
+
+const multilingualElevenLabsModel = "eleven_multilingual_v2"
+
+// localizeTTSModel switches ElevenLabs to its multilingual model when the
+// episode or its game is not in English and the configured model cannot
+// voice it. OpenAI and command voices are used as configured.
+func localizeTTSModel(cfg cfgpkg.Config) (cfgpkg.Config, error) {
+	english := true
+	for _, tag := range []string{cfg.Language, cfg.GameLanguage} {
+		locale, err := podcast.LookupLocale(tag)
+		if err != nil {
+			return cfg, err
+		}
+		english = english && locale.IsEnglish()
+	}
+	provider := strings.ToLower(strings.TrimSpace(cfg.TTSProvider))
+	if english || provider != "elevenlabs" {
+		return cfg, nil
+	}
+	if englishOnlyElevenLabsModels[cfg.TTSModel] || !strings.HasPrefix(cfg.TTSModel, "eleven_") {
+		slog.Warn("using multilingual tts model", "configured", cfg.TTSModel, "model", multilingualElevenLabsModel, "language", cfg.Language, "gameLanguage", cfg.GameLanguage)
+		cfg.TTSModel = multilingualElevenLabsModel
+	}
+	return cfg, nil
+}
+
+// sectionLanguage returns the language a show section is spoken in.
+func sectionLanguage(cfg cfgpkg.Config, section podcast.ShowSection) string {
+	if section.Kind == podcast.SectionKindGame && strings.TrimSpace(cfg.GameLanguage) != "" {
+		return cfg.GameLanguage
+	}
+	return cfg.Language
+}
+
+// voiceForLanguage returns cfg.Voice for the episode language. Sections in
+// another language use cfg.Voices, trying the full tag and then its primary
+// subtag, and fall back to cfg.Voice.
+func voiceForLanguage(cfg cfgpkg.Config, tag string) string {
+	if tag == cfg.Language {
+		return cfg.Voice
+	}
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		tag = "en"
+	}
+	if voice, ok := cfg.Voices[tag]; ok && voice != "" {
+		return voice
+	}
+	if voice, ok := cfg.Voices[strings.ToLower(strings.SplitN(tag, "-", 2)[0])]; ok && voice != "" {
+		return voice
+	}
+	return cfg.Voice
+}
+
+// staleAudioSections returns the sections meta.json lists as rewritten since
+// their audio was made, when every other section's MP3 is still on disk so
+// only those need regenerating. It returns nil to regenerate every section:
+// with --overwrite, without stale sections, or when other MP3s are missing.
+func staleAudioSections(metaPath string, builder *paths.Builder, date time.Time, sectionIDs []string, overwrite bool) []string {
+	if overwrite {
+		return nil
+	}
+	meta, err := readScriptMeta(metaPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("ignoring unreadable meta.json", "path", metaPath, "err", err)
+		}
+		return nil
+	}
+	if len(meta.StaleAudio) == 0 {
+		return nil
+	}
+	for _, sectionID := range sectionIDs {
+		if slices.Contains(meta.StaleAudio, sectionID) {
+			continue
+		}
+		if _, err := os.Stat(builder.EpisodeSectionMP3(date, sectionID)); err != nil {
+			return nil
+		}
+	}
+	return meta.StaleAudio
+}
+
+// clearStaleAudio empties meta.json's stale audio list once the audio has
+// been regenerated.
+func clearStaleAudio(metaPath string) error {
+	meta, err := readScriptMeta(metaPath)
+	if err != nil || len(meta.StaleAudio) == 0 {
+		return nil
+	}
+	meta.StaleAudio = nil
+	return writeScriptMeta(metaPath, meta)
+}
+
 func allFilesExist(paths []string) bool {
 	for _, p := range paths {
 		if _, err := os.Stat(p); err != nil {
@@ -228,7 +349,7 @@ func concatMP3ByCopy(outPath string, inputs []string) error {
 }
 
 func synthesizeWithPauses(ctx context.Context, client ai.TTSClient, cfg cfgpkg.Config, text, outPath string) error {
-	segments := splitOnPauses(text)
+	segments := podcast.SplitOnPauses(text)
 	if len(segments) == 0 {
 		return fmt.Errorf("no text to synthesize")
 	}
@@ -241,16 +362,16 @@ func synthesizeWithPauses(ctx context.Context, client ai.TTSClient, cfg cfgpkg.C
 		return err
 	}
 	pausePaths := map[string]string{
-		longPauseTag:  longPauseAbs,
-		shortPauseTag: shortPauseAbs,
+		podcast.LongPauseTag:  longPauseAbs,
+		podcast.ShortPauseTag: shortPauseAbs,
 	}
 	for _, segment := range segments {
-		if segment.pauseTag == "" {
+		if segment.PauseTag == "" {
 			continue
 		}
-		absPath, ok := pausePaths[segment.pauseTag]
+		absPath, ok := pausePaths[segment.PauseTag]
 		if !ok {
-			return fmt.Errorf("unknown pause audio tag: %s", segment.pauseTag)
+			return fmt.Errorf("unknown pause audio tag: %s", segment.PauseTag)
 		}
 		if _, err := os.Stat(absPath); err != nil {
 			return fmt.Errorf("pause audio missing: %w", err)
@@ -258,7 +379,7 @@ func synthesizeWithPauses(ctx context.Context, client ai.TTSClient, cfg cfgpkg.C
 	}
 	tmpPaths := make([]string, 0, len(segments))
 	for i, segment := range segments {
-		if strings.TrimSpace(segment.text) == "" {
+		if strings.TrimSpace(segment.Text) == "" {
 			continue
 		}
 		tmpPath := fmt.Sprintf("%s.part.%02d.mp3", outPath, i)
@@ -266,7 +387,7 @@ func synthesizeWithPauses(ctx context.Context, client ai.TTSClient, cfg cfgpkg.C
 		if err != nil {
 			return err
 		}
-		if err := client.TTS(ctx, cfg.TTSModel, cfg.Voice, segment.text, out); err != nil {
+		if err := client.TTS(ctx, cfg.TTSModel, cfg.Voice, segment.Text, out); err != nil {
 			_ = out.Close()
 			return err
 		}
@@ -274,9 +395,9 @@ func synthesizeWithPauses(ctx context.Context, client ai.TTSClient, cfg cfgpkg.C
 			return err
 		}
 		tmpPaths = append(tmpPaths, tmpPath)
-		if segment.pauseTag == longPauseTag {
+		if segment.PauseTag == podcast.LongPauseTag {
 			tmpPaths = append(tmpPaths, longPauseAbs)
-		} else if segment.pauseTag == shortPauseTag {
+		} else if segment.PauseTag == podcast.ShortPauseTag {
 			tmpPaths = append(tmpPaths, shortPauseAbs)
 		}
 	}
@@ -293,41 +414,3 @@ func synthesizeWithPauses(ctx context.Context, client ai.TTSClient, cfg cfgpkg.C
 	}
 	return nil
 }
-
-type pauseSegment struct {
-	text     string
-	pauseTag string
-}
-
-func splitOnPauses(text string) []pauseSegment {
-	var segments []pauseSegment
-	for len(text) > 0 {
-		longIndex := strings.Index(text, longPauseTag)
-		shortIndex := strings.Index(text, shortPauseTag)
-		nextIndex, tag := nextPauseTag(longIndex, shortIndex)
-		if nextIndex == -1 {
-			segments = append(segments, pauseSegment{text: text})
-			break
-		}
-		segment := pauseSegment{text: text[:nextIndex], pauseTag: tag}
-		segments = append(segments, segment)
-		text = text[nextIndex+len(tag):]
-	}
-	return segments
-}
-
-func nextPauseTag(longIndex, shortIndex int) (int, string) {
-	if longIndex == -1 && shortIndex == -1 {
-		return -1, ""
-	}
-	if longIndex == -1 {
-		return shortIndex, shortPauseTag
-	}
-	if shortIndex == -1 {
-		return longIndex, longPauseTag
-	}
-	if longIndex <= shortIndex {
-		return longIndex, longPauseTag
-	}
-	return shortIndex, shortPauseTag
-}
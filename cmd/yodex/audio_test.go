@@ -103,3 +103,44 @@ func TestAudioWritesMP3(t *testing.T) {
 		t.Fatalf("unexpected episode.mp3 size: %d", info.Size())
 	}
 }
+
+func TestLocalizeTTSModel(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  cfgpkg.Config
+		want string
+	}{
+		{"english", cfgpkg.Config{TTSProvider: "elevenlabs", TTSModel: "eleven_turbo_v2"}, "eleven_turbo_v2"},
+		{"spanish", cfgpkg.Config{TTSProvider: "elevenlabs", TTSModel: "eleven_turbo_v2", Language: "es"}, "eleven_multilingual_v2"},
+		{"spanish game", cfgpkg.Config{TTSProvider: "elevenlabs", TTSModel: "gpt-4o-mini-tts", GameLanguage: "es"}, "eleven_multilingual_v2"},
+		{"multilingual kept", cfgpkg.Config{TTSProvider: "elevenlabs", TTSModel: "eleven_v3", Language: "es"}, "eleven_v3"},
+		{"openai", cfgpkg.Config{TTSProvider: "openai", TTSModel: "gpt-4o-mini-tts", Language: "es"}, "gpt-4o-mini-tts"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := localizeTTSModel(tc.cfg)
+			if err != nil {
+				t.Fatalf("localizeTTSModel: %v", err)
+			}
+			if cfg.TTSModel != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, cfg.TTSModel)
+			}
+		})
+	}
+	if _, err := localizeTTSModel(cfgpkg.Config{Language: "xx"}); err == nil {
+		t.Fatalf("expected unsupported language error")
+	}
+}
+
+func TestVoiceForLanguage(t *testing.T) {
+	cfg := cfgpkg.Config{Voice: "rachel", Language: "en", Voices: map[string]string{"es": "lucia", "en": "ignored"}}
+	if got := voiceForLanguage(cfg, "en"); got != "rachel" {
+		t.Fatalf("expected episode voice, got %s", got)
+	}
+	if got := voiceForLanguage(cfg, "es-MX"); got != "lucia" {
+		t.Fatalf("expected Spanish voice by base tag, got %s", got)
+	}
+	if got := voiceForLanguage(cfg, "de"); got != "rachel" {
+		t.Fatalf("expected fallback voice, got %s", got)
+	}
+}
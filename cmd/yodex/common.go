@@ -7,6 +7,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	cfgpkg "yodex/internal/config"
 )
 
 // set up slog logger according to level; defaults to info.
@@ -33,19 +35,50 @@ type commonFlags struct {
 	date     string
 	config   string
 	logLevel string
+	show     string
 }
 
 func addCommonFlags(fs *flag.FlagSet, cf *commonFlags) {
-	fs.StringVar(&cf.date, "date", "", "Date in YYYY-MM-DD (UTC); default: today")
+	fs.StringVar(&cf.date, "date", "", "Date in YYYY-MM-DD; default: today in the configured timezone")
+	addConfigFlags(fs, cf)
+}
+
+// addConfigFlags adds the common flags except --date, for commands that do
+// not work on a single episode.
+func addConfigFlags(fs *flag.FlagSet, cf *commonFlags) {
 	fs.StringVar(&cf.config, "config", "config.json", "Path to config file")
 	fs.StringVar(&cf.logLevel, "log-level", "info", "Log level: debug, info, warn, error")
+	fs.StringVar(&cf.show, "show", "", "Show name from the config's shows map; default: top-level settings")
 }
 
-func resolveDate(in string) (time.Time, error) {
+// loadFileConfig reads the config file and applies the selected show's entry.
+func loadFileConfig(cf commonFlags) (cfgpkg.Config, error) {
+	if cf.show == cfgpkg.AllShows {
+		return cfgpkg.Config{}, fmt.Errorf("--show=%s is only supported by yodex all", cfgpkg.AllShows)
+	}
+	fileCfg, err := cfgpkg.LoadFile(cf.config)
+	if err != nil {
+		return fileCfg, err
+	}
+	return cfgpkg.SelectShow(fileCfg, cf.show)
+}
+
+// timeNow is replaced in tests.
+var timeNow = time.Now
+
+// resolveDate returns the episode date: --date, or today in the configured
+// timezone, as midnight in that zone. Paths, storage keys, prompts, and game
+// selection all take the calendar day from the date's own location.
+func resolveDate(in string, cfg cfgpkg.Config) (time.Time, error) {
+	loc, err := cfg.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
 	if in == "" {
-		return time.Now().UTC(), nil
+		y, m, d := timeNow().In(loc).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, loc), nil
 	}
-	t, err := time.Parse("2006-01-02", in)
+	t, err := time.ParseInLocation("2006-01-02", in, loc)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("invalid --date: %w", err)
 	}
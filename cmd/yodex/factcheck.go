@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"yodex/internal/ai"
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/podcast"
+)
+
+// maxFactCheckRegenerations bounds how many times sections with low-confidence
+// claims are rewritten before the run fails.
+const maxFactCheckRegenerations = 2
+
+// factCheckReport is written to factcheck.json next to meta.json.
+type factCheckReport struct {
+	MinConfidence float64                   `json:"minConfidence"`
+	Action        string                    `json:"action"`
+	Failed        bool                      `json:"failed"`
+	Regenerations int                       `json:"regenerations"`
+	Claims        []podcast.ClaimAssessment `json:"claims"`
+}
+
+// runFactCheck extracts and assesses the episode's claims and, when configured,
+// regenerates only the sections with low-confidence claims.
+func runFactCheck(ctx context.Context, cfg cfgpkg.Config, date time.Time, client ai.TextClient, specs []podcast.SectionSpec, system, basePrompt, topic string, episode podcast.Episode) (podcast.Episode, factCheckReport, ai.TokenUsage, error) {
+	action := strings.ToLower(strings.TrimSpace(cfg.FactCheckAction))
+	report := factCheckReport{MinConfidence: cfg.FactCheckMinConfidence, Action: action}
+	var usage ai.TokenUsage
+
+	for {
+		slog.Info("running fact check", "regenerations", report.Regenerations)
+		checked, checkUsage, err := podcast.FactCheckEpisode(ctx, client, cfg.TextModel, topic, episode)
+		usage = usage.Add(checkUsage)
+		if err != nil {
+			return episode, report, usage, fmt.Errorf("fact check: %w", err)
+		}
+		report.Claims = checked.Claims
+		low := checked.LowConfidenceSections(cfg.FactCheckMinConfidence)
+		if len(low) == 0 {
+			slog.Info("fact check passed", "claims", len(checked.Claims))
+			return episode, report, usage, nil
+		}
+		slog.Warn("fact check found low-confidence claims", "sections", low, "claims", len(checked.LowConfidence(cfg.FactCheckMinConfidence)))
+		if action != cfgpkg.FactCheckActionRegenerate || report.Regenerations >= maxFactCheckRegenerations {
+			report.Failed = true
+			return episode, report, usage, nil
+		}
+		for _, sectionID := range low {
+			text, regenUsage, err := regenerateSection(ctx, date, client, cfg.TextModel, specs, system, basePrompt, topic, episode, sectionID, checked.RevisionNotes(sectionID, cfg.FactCheckMinConfidence))
+			if err != nil {
+				return episode, report, usage, err
+			}
+			usage = usage.Add(regenUsage)
+			episode = replaceSectionText(episode, sectionID, text)
+		}
+		report.Regenerations++
+	}
+}
+
+// checkFactCheckAllowsPublish refuses to publish an episode whose
+// factcheck.json reports a failed fact check. Episodes without a report were
+// not fact-checked and may be published.
+func checkFactCheckAllowsPublish(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read fact check: %w", err)
+	}
+	var report factCheckReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return fmt.Errorf("parse fact check %s: %w", path, err)
+	}
+	if report.Failed {
+		return fmt.Errorf("refusing to publish: fact check failed for this episode (see %s)", path)
+	}
+	return nil
+}
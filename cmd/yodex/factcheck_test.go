@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/paths"
+)
+
+func setupFactCheckTest(t *testing.T, action string, fake *fakeTextClient) string {
+	t.Helper()
+	return setupScriptConfigTest(t, `{"factCheck": true, "factCheckMinConfidence": 0.7, "factCheckAction": "`+action+`"}`, fake)
+}
+
+const topicClaims = `{"claims":[{"section_id":"topic","claim":"Lava is as cold as ice."}]}`
+const lowConfidenceAssessment = `{"assessments":[{"index":1,"verdict":"inaccurate","confidence":0.05,"note":"lava is extremely hot"}]}`
+const confidentAssessment = `{"assessments":[{"index":1,"verdict":"accurate","confidence":0.95,"note":""}]}`
+
+func TestScriptFactCheckRegeneratesSection(t *testing.T) {
+	fake := &fakeTextClient{
+		responses:     append(makeSectionResponses(100), "Lava is super hot."),
+		jsonResponses: []string{topicClaims, lowConfidenceAssessment, topicClaims, confidentAssessment},
+	}
+	cfgPath := setupFactCheckTest(t, "regenerate", fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Volcanoes", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if fake.calls != 5 {
+		t.Fatalf("expected 4 section calls plus 1 regeneration, got %d", fake.calls)
+	}
+	if fake.jsonCalls != 4 {
+		t.Fatalf("expected 2 extraction and 2 assessment calls, got %d", fake.jsonCalls)
+	}
+
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	topic, err := os.ReadFile(builder.EpisodeSectionMarkdown(date, "topic"))
+	if err != nil {
+		t.Fatalf("read topic.md: %v", err)
+	}
+	if strings.TrimSpace(string(topic)) != "Lava is super hot." {
+		t.Fatalf("expected regenerated topic, got %q", topic)
+	}
+	var report factCheckReport
+	data, err := os.ReadFile(builder.EpisodeFactCheck(date))
+	if err != nil {
+		t.Fatalf("read factcheck.json: %v", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("parse factcheck.json: %v", err)
+	}
+	if report.Failed || report.Regenerations != 1 || len(report.Claims) != 1 || report.Claims[0].Confidence != 0.95 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestScriptFactCheckFailsRun(t *testing.T) {
+	fake := &fakeTextClient{
+		responses:     makeSectionResponses(100),
+		jsonResponses: []string{topicClaims, lowConfidenceAssessment},
+	}
+	cfgPath := setupFactCheckTest(t, "fail", fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Volcanoes", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected script to fail on low-confidence claims")
+	}
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	data, err := os.ReadFile(paths.New("").EpisodeFactCheck(date))
+	if err != nil {
+		t.Fatalf("expected factcheck.json to be written: %v", err)
+	}
+	if !strings.Contains(string(data), `"failed": true`) {
+		t.Fatalf("expected failed report, got %s", data)
+	}
+	builder := paths.New("")
+	meta, err := readScriptMeta(builder.EpisodeMeta(date))
+	if err != nil || meta.Status != scriptStatusFactCheckFailed {
+		t.Fatalf("expected a fact-check-failed status in meta.json, got %+v, %v", meta, err)
+	}
+	if history, err := os.ReadFile("out/topic-history.json"); err == nil && strings.Contains(string(history), "2025-09-30") {
+		t.Fatalf("expected a failed episode to stay out of the topic history, got %s", history)
+	}
+	if err := os.WriteFile(builder.EpisodeMP3(date), []byte("audio"), 0o644); err != nil {
+		t.Fatalf("write mp3: %v", err)
+	}
+
+	origUploader := newUploader
+	t.Cleanup(func() { newUploader = origUploader })
+	up := &fakeUploader{}
+	newUploader = func(ctx context.Context, bucket, prefix, region string) (uploader, error) {
+		return up, nil
+	}
+	if code := run([]string{"publish", "--date=2025-09-30", "--bucket=b", "--region=us-west-2", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected publish to refuse a failed episode")
+	}
+	if len(up.uploads) != 0 {
+		t.Fatalf("expected no uploads, got %v", up.uploads)
+	}
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/paths"
+	"yodex/internal/podcast"
+)
+
+var openTopicHistory = podcast.OpenTopicHistory
+
+// historyOut is where history listings and exports are written; replaced in tests.
+var historyOut io.Writer = os.Stdout
+
+// recordEpisodeHistory stores what the script step produced in the topic
+// history entry for the episode date, keeping its published flag and, while
+// the topic is unchanged, what topic selection recorded about it.
+func recordEpisodeHistory(ctx context.Context, cfg cfgpkg.Config, date time.Time, meta scriptMeta) error {
+	store, err := openTopicHistory(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return podcast.UpdateTopicHistoryEntry(ctx, store, date, func(entry *podcast.TopicHistoryEntry) {
+		prev := *entry
+		*entry = metaHistoryEntry(meta)
+		entry.Published = prev.Published
+		if prev.Topic == entry.Topic {
+			entry.Category = prev.Category
+			entry.Theme = prev.Theme
+			entry.Series = prev.Series
+			entry.Backlog = prev.Backlog
+			entry.Embedding = prev.Embedding
+			entry.EmbeddingModel = prev.EmbeddingModel
+		}
+	})
+}
+
+// releaseEpisodeHistory removes the topic history entry for an episode that
+// was blocked or failed, including the one topic selection recorded, so the
+// episode does not steer similarity checks, category balancing, or series
+// progression. A topic taken from the backlog is put back for a rerun.
+func releaseEpisodeHistory(ctx context.Context, cfg cfgpkg.Config, date time.Time) error {
+	store, err := openTopicHistory(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	key := date.Format("2006-01-02")
+	var released podcast.TopicHistoryEntry
+	err = store.Update(ctx, func(history *podcast.TopicHistory) error {
+		released = history.Entries[key]
+		delete(history.Entries, key)
+		return nil
+	})
+	if err != nil || released.Backlog == "" {
+		return err
+	}
+	backlog, err := openTopicBacklog(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if err := backlog.Update(ctx, func(b *podcast.TopicBacklog) error {
+		b.Return(key, released.Topic, released.Backlog)
+		return nil
+	}); err != nil {
+		return err
+	}
+	slog.Info("topic returned to backlog", "topic", released.Topic, "backlog", released.Backlog, "location", backlog.Location())
+	return nil
+}
+
+// markEpisodePublished sets the published flag on the episode's history entry.
+func markEpisodePublished(ctx context.Context, cfg cfgpkg.Config, date time.Time) error {
+	store, err := openTopicHistory(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return podcast.UpdateTopicHistoryEntry(ctx, store, date, func(entry *podcast.TopicHistoryEntry) {
+		entry.Published = true
+	})
+}
+
+func metaHistoryEntry(meta scriptMeta) podcast.TopicHistoryEntry {
+	return podcast.TopicHistoryEntry{
+		Topic:     meta.Topic,
+		Title:     meta.Title,
+		Game:      meta.Game,
+		Model:     meta.Model,
+		WordCount: meta.WordCount,
+		Keywords:  podcast.TopicKeywords(meta.Topic, meta.Title),
+	}
+}
+
+const historyUsage = `Usage:
+  yodex history list [flags]
+  yodex history show [flags] DATE
+  yodex history rm [flags] DATE...
+  yodex history import [flags] FILE
+  yodex history export [flags] [FILE]
+  yodex history migrate [flags]
+
+Works on topic-history.json in S3 when a bucket is configured, otherwise on
+the local topicHistoryPath file.
+`
+
+// yodex history
+func cmdHistory(args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(os.Stderr, historyUsage)
+		return nil
+	}
+	action := args[0]
+	switch action {
+	case "list", "show", "rm", "import", "export", "migrate":
+	default:
+		fmt.Fprint(os.Stderr, historyUsage)
+		return fmt.Errorf("unknown history command: %s", action)
+	}
+
+	var cf commonFlags
+	var replace, dryRun bool
+	fs := flag.NewFlagSet("history "+action, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	addConfigFlags(fs, &cf)
+	switch action {
+	case "import":
+		fs.BoolVar(&replace, "replace", false, "Replace the whole history instead of merging into it")
+	case "migrate":
+		fs.BoolVar(&dryRun, "dry-run", false, "Report what would change without saving")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	setupLogger(cf.logLevel)
+
+	fileCfg, err := loadFileConfig(cf)
+	if err != nil {
+		return err
+	}
+	envOv, apiKey, elevenLabsKey := cfgpkg.FromEnv()
+	cfg := cfgpkg.Merge(fileCfg, envOv, cfgpkg.Overrides{}, apiKey, elevenLabsKey)
+	if cfg.S3Bucket == "" && strings.TrimSpace(cfg.TopicHistoryPath) == "" {
+		return errors.New("no topic history configured: set AWS_S3_BUCKET or topicHistoryPath")
+	}
+
+	ctx := context.Background()
+	store, err := openTopicHistory(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	rest := fs.Args()
+	switch action {
+	case "list":
+		if len(rest) != 0 {
+			return errors.New("history list takes no arguments")
+		}
+		history, err := store.Load(ctx)
+		if err != nil {
+			return err
+		}
+		return writeHistoryList(historyOut, history)
+	case "show":
+		if len(rest) != 1 {
+			return errors.New("history show takes one DATE")
+		}
+		key, err := historyDateKey(rest[0])
+		if err != nil {
+			return err
+		}
+		history, err := store.Load(ctx)
+		if err != nil {
+			return err
+		}
+		entry, ok := history.Entries[key]
+		if !ok {
+			return fmt.Errorf("no history entry for %s", key)
+		}
+		data, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(historyOut, "%s\n", data)
+		return err
+	case "rm":
+		if len(rest) == 0 {
+			return errors.New("history rm takes at least one DATE")
+		}
+		for _, arg := range rest {
+			if _, err := historyDateKey(arg); err != nil {
+				return err
+			}
+		}
+		err := store.Update(ctx, func(history *podcast.TopicHistory) error {
+			for _, key := range rest {
+				if _, ok := history.Entries[key]; !ok {
+					return fmt.Errorf("no history entry for %s", key)
+				}
+				delete(history.Entries, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("history entries removed", "count", len(rest), "location", store.Location())
+		return nil
+	case "import":
+		if len(rest) != 1 {
+			return errors.New("history import takes one FILE")
+		}
+		data, err := os.ReadFile(rest[0])
+		if err != nil {
+			return err
+		}
+		imported, err := podcast.ParseTopicHistory(data)
+		if err != nil {
+			return err
+		}
+		for key := range imported.Entries {
+			if _, err := historyDateKey(key); err != nil {
+				return err
+			}
+		}
+		err = store.Update(ctx, func(history *podcast.TopicHistory) error {
+			if replace {
+				*history = imported
+				return nil
+			}
+			for key, entry := range imported.Entries {
+				history.Entries[key] = entry
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("history imported", "entries", len(imported.Entries), "replace", replace, "location", store.Location())
+		return nil
+	case "export":
+		if len(rest) > 1 {
+			return errors.New("history export takes at most one FILE")
+		}
+		history, err := store.Load(ctx)
+		if err != nil {
+			return err
+		}
+		data, err := podcast.MarshalTopicHistory(history)
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		if len(rest) == 1 {
+			return os.WriteFile(rest[0], data, 0o644)
+		}
+		_, err = historyOut.Write(data)
+		return err
+	default: // migrate
+		if len(rest) != 0 {
+			return errors.New("history migrate takes no arguments")
+		}
+		details := localMetaDetails(cfg)
+		if dryRun {
+			history, err := store.Load(ctx)
+			if err != nil {
+				return err
+			}
+			changed := podcast.MigrateTopicHistory(&history, details)
+			slog.Info("history migration preview", "changed", changed, "entries", len(history.Entries), "location", store.Location())
+			return nil
+		}
+		var changed, entries int
+		err := store.Update(ctx, func(history *podcast.TopicHistory) error {
+			changed = podcast.MigrateTopicHistory(history, details)
+			entries = len(history.Entries)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("history migrated", "changed", changed, "entries", entries, "location", store.Location())
+		return nil
+	}
+}
+
+// historyDateKey validates a YYYY-MM-DD history key.
+func historyDateKey(s string) (string, error) {
+	if _, err := time.Parse("2006-01-02", s); err != nil {
+		return "", fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", s)
+	}
+	return s, nil
+}
+
+func writeHistoryList(w io.Writer, history podcast.TopicHistory) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATE\tTOPIC\tCATEGORY\tGAME\tWORDS\tPUBLISHED")
+	for _, key := range history.Dates() {
+		entry := history.Entries[key]
+		words := ""
+		if entry.WordCount > 0 {
+			words = fmt.Sprint(entry.WordCount)
+		}
+		published := "no"
+		if entry.Published {
+			published = "yes"
+		}
+		category := entry.Category
+		if category == "" {
+			category = entry.Theme
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", key, entry.Topic, category, entry.Game, words, published)
+	}
+	return tw.Flush()
+}
+
+// localMetaDetails looks up an episode's meta.json under the configured out
+// directory so migration can fill in the title, game, model, and word count.
+func localMetaDetails(cfg cfgpkg.Config) func(string) (podcast.TopicHistoryEntry, bool) {
+	builder := paths.New(cfg.OutDir)
+	return func(key string) (podcast.TopicHistoryEntry, bool) {
+		date, err := time.Parse("2006-01-02", key)
+		if err != nil {
+			return podcast.TopicHistoryEntry{}, false
+		}
+		data, err := os.ReadFile(builder.EpisodeMeta(date))
+		if err != nil {
+			return podcast.TopicHistoryEntry{}, false
+		}
+		var meta scriptMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			slog.Warn("skipping unreadable meta.json", "date", key, "err", err)
+			return podcast.TopicHistoryEntry{}, false
+		}
+		return metaHistoryEntry(meta), true
+	}
+}
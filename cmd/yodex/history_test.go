@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/paths"
+	"yodex/internal/podcast"
+)
+
+// memoryTopicHistory is an in-memory topic history store.
+type memoryTopicHistory struct {
+	history podcast.TopicHistory
+}
+
+func (m *memoryTopicHistory) Load(ctx context.Context) (podcast.TopicHistory, error) {
+	entries := make(map[string]podcast.TopicHistoryEntry, len(m.history.Entries))
+	for key, entry := range m.history.Entries {
+		entries[key] = entry
+	}
+	return podcast.TopicHistory{Version: m.history.Version, Entries: entries}, nil
+}
+
+func (m *memoryTopicHistory) Update(ctx context.Context, fn func(*podcast.TopicHistory) error) error {
+	history, err := m.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(&history); err != nil {
+		return err
+	}
+	m.history = history
+	return nil
+}
+
+func (m *memoryTopicHistory) Location() string { return "memory" }
+
+// stubTopicHistory replaces the topic history store for the test so commands
+// configured with an S3 bucket do not reach AWS.
+func stubTopicHistory(t *testing.T) *memoryTopicHistory {
+	t.Helper()
+	store := &memoryTopicHistory{}
+	orig := openTopicHistory
+	t.Cleanup(func() { openTopicHistory = orig })
+	openTopicHistory = func(ctx context.Context, cfg cfgpkg.Config) (podcast.TopicHistoryStore, error) {
+		return store, nil
+	}
+	return store
+}
+
+func captureHistoryOut(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	orig := historyOut
+	t.Cleanup(func() { historyOut = orig })
+	historyOut = &buf
+	return &buf
+}
+
+func TestHistoryCommandsOnFileBackend(t *testing.T) {
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	historyPath := filepath.Join("out", "topic-history.json")
+	if err := os.MkdirAll("out", 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	old := `{"entries": {"2025-09-29": {"topic": "Ocean Tides"}, "2025-09-30": "Honey Bees"}}`
+	if err := os.WriteFile(historyPath, []byte(old), 0o644); err != nil {
+		t.Fatalf("write history: %v", err)
+	}
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	if err := builder.EnsureOutDir(date); err != nil {
+		t.Fatalf("EnsureOutDir: %v", err)
+	}
+	meta := `{"date":"2025-09-30","topic":"Honey Bees","title":"Busy Honey Bees","game":"fact-or-fib","model":"gpt-test","wordCount":812}`
+	if err := os.WriteFile(builder.EpisodeMeta(date), []byte(meta), 0o644); err != nil {
+		t.Fatalf("write meta: %v", err)
+	}
+
+	if code := run([]string{"history", "migrate"}); code != 0 {
+		t.Fatalf("history migrate returned %d", code)
+	}
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		t.Fatalf("read history: %v", err)
+	}
+	history, err := podcast.ParseTopicHistory(data)
+	if err != nil {
+		t.Fatalf("parse history: %v", err)
+	}
+	if history.Version != podcast.TopicHistoryVersion {
+		t.Fatalf("expected version %d, got %d", podcast.TopicHistoryVersion, history.Version)
+	}
+	bees := history.Entries["2025-09-30"]
+	if bees.Title != "Busy Honey Bees" || bees.Game != "fact-or-fib" || bees.Model != "gpt-test" || bees.WordCount != 812 {
+		t.Fatalf("expected entry filled from meta.json, got %+v", bees)
+	}
+	if strings.Join(history.Entries["2025-09-29"].Keywords, ",") != "ocean,tides" {
+		t.Fatalf("expected keywords from topic, got %v", history.Entries["2025-09-29"].Keywords)
+	}
+
+	out := captureHistoryOut(t)
+	if code := run([]string{"history", "list"}); code != 0 {
+		t.Fatalf("history list returned %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "2025-09-29") || !strings.Contains(lines[2], "fact-or-fib") {
+		t.Fatalf("unexpected list output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := run([]string{"history", "show", "2025-09-30"}); code != 0 {
+		t.Fatalf("history show returned %d", code)
+	}
+	if !strings.Contains(out.String(), `"title": "Busy Honey Bees"`) {
+		t.Fatalf("unexpected show output:\n%s", out.String())
+	}
+
+	exportPath := filepath.Join(tmp, "export.json")
+	if code := run([]string{"history", "export", exportPath}); code != 0 {
+		t.Fatalf("history export returned %d", code)
+	}
+	if code := run([]string{"history", "rm", "2025-09-29", "2025-09-30"}); code != 0 {
+		t.Fatalf("history rm returned %d", code)
+	}
+	if code := run([]string{"history", "show", "2025-09-30"}); code == 0 {
+		t.Fatalf("expected show of a removed entry to fail")
+	}
+	if code := run([]string{"history", "import", exportPath}); code != 0 {
+		t.Fatalf("history import returned %d", code)
+	}
+	data, err = os.ReadFile(historyPath)
+	if err != nil {
+		t.Fatalf("read history: %v", err)
+	}
+	restored, err := podcast.ParseTopicHistory(data)
+	if err != nil {
+		t.Fatalf("parse history: %v", err)
+	}
+	if len(restored.Entries) != 2 || restored.Entries["2025-09-30"].Title != "Busy Honey Bees" {
+		t.Fatalf("expected export to round-trip, got %+v", restored.Entries)
+	}
+}
+
+func TestHistoryCommandsUseS3Backend(t *testing.T) {
+	store := stubTopicHistory(t)
+	store.history = podcast.TopicHistory{Entries: map[string]podcast.TopicHistoryEntry{
+		"2025-09-30": {Topic: "Volcanoes"},
+	}}
+	t.Setenv("AWS_S3_BUCKET", "b")
+	out := captureHistoryOut(t)
+	if code := run([]string{"history", "list", "--config", filepath.Join(t.TempDir(), "missing.json")}); code != 0 {
+		t.Fatalf("history list returned %d", code)
+	}
+	if !strings.Contains(out.String(), "Volcanoes") {
+		t.Fatalf("expected S3 history in list output:\n%s", out.String())
+	}
+	if code := run([]string{"history", "rm", "--config", filepath.Join(t.TempDir(), "missing.json"), "2025-09-30"}); code != 0 {
+		t.Fatalf("history rm returned %d", code)
+	}
+	if len(store.history.Entries) != 0 {
+		t.Fatalf("expected entry removed from S3 history, got %+v", store.history.Entries)
+	}
+}
+
+func TestScriptRecordsHistoryAndPublishMarksIt(t *testing.T) {
+	fake := &fakeTextClient{responses: makeSectionResponses(800)}
+	setupScriptConfigTest(t, `{}`, fake)
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Honey Bees"}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	data, err := os.ReadFile(filepath.Join("out", "topic-history.json"))
+	if err != nil {
+		t.Fatalf("read history: %v", err)
+	}
+	var history podcast.TopicHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		t.Fatalf("parse history: %v", err)
+	}
+	entry := history.Entries["2025-09-30"]
+	if entry.Topic != "Honey Bees" || entry.Game == "" || entry.WordCount == 0 || entry.Published {
+		t.Fatalf("unexpected recorded entry: %+v", entry)
+	}
+	if strings.Join(entry.Keywords, ",") != "honey,bees" {
+		t.Fatalf("unexpected keywords: %v", entry.Keywords)
+	}
+
+	store := stubTopicHistory(t)
+	store.history = history
+	origUploader := newUploader
+	t.Cleanup(func() { newUploader = origUploader })
+	newUploader = func(ctx context.Context, bucket, prefix, region string) (uploader, error) {
+		return &fakeUploader{}, nil
+	}
+	if err := os.WriteFile(paths.New("").EpisodeMP3(time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)), []byte("audio"), 0o644); err != nil {
+		t.Fatalf("write mp3: %v", err)
+	}
+	if code := run([]string{"publish", "--date=2025-09-30", "--bucket=b"}); code != 0 {
+		t.Fatalf("publish returned non-zero: %d", code)
+	}
+	published := store.history.Entries["2025-09-30"]
+	if !published.Published || published.Title != entry.Title {
+		t.Fatalf("expected entry marked published with metadata kept, got %+v", published)
+	}
+}
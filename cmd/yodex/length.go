@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"yodex/internal/ai"
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/podcast"
+)
+
+// maxLengthPasses bounds how many expand/tighten rounds run before the episode
+// is accepted as-is.
+const maxLengthPasses = 2
+
+// sectionWordCount is the final spoken word count for one section.
+type sectionWordCount struct {
+	SectionID string `json:"sectionId"`
+	Words     int    `json:"words"`
+	Budget    int    `json:"budget,omitempty"`
+}
+
+// episodeSpecs loads the show definition, or the recap show when prompts
+// carry recap episodes, and renders its section specs for the topic and date,
+// with word budgets scaled to the configured target. Game sections that do
+// not name a game get the one scheduled for the date, avoiding previousGame.
+func episodeSpecs(cfg cfgpkg.Config, prompts podcast.Prompts, topic string, date time.Time, previousGame string) ([]podcast.SectionSpec, error) {
+	show := podcast.RecapShow()
+	if len(prompts.Recap) == 0 {
+		var err error
+		if show, err = podcast.LoadShowDefinition(cfg.ShowPath); err != nil {
+			return nil, err
+		}
+	}
+	specs, err := show.Specs(prompts, topic, date)
+	if err != nil {
+		return nil, err
+	}
+	for i, spec := range specs {
+		if spec.Kind != podcast.SectionKindGame || spec.Game != "" {
+			continue
+		}
+		games, err := podcast.LoadGameRules()
+		if err != nil {
+			return nil, err
+		}
+		game, err := podcast.ChooseGame(date, games, previousGame)
+		if err != nil {
+			return nil, err
+		}
+		specs[i].Game = game.Name
+	}
+	budgets := podcast.ScaleWordBudgets(podcast.WordBudgets(specs), cfg.TargetWordCount)
+	return podcast.ApplyWordBudgets(specs, budgets), nil
+}
+
+// targetSectionLengths expands or tightens sections that fall outside the
+// tolerance of their enforced budget, looping up to maxLengthPasses. Budgets
+// are enforced for every section when a target word count is set, and
+// otherwise only where the show definition sets one. It returns the updated
+// episode and the number of rewrites.
+func targetSectionLengths(ctx context.Context, cfg cfgpkg.Config, date time.Time, client ai.TextClient, specs []podcast.SectionSpec, system, basePrompt, topic string, episode podcast.Episode) (podcast.Episode, int, ai.TokenUsage, error) {
+	budgets := podcast.EnforcedWordBudgets(specs, cfg.TargetWordCount > 0)
+	var usage ai.TokenUsage
+	rewrites := 0
+	for pass := 0; pass < maxLengthPasses; pass++ {
+		var off []string
+		for _, section := range episode.Sections {
+			if !podcast.WithinWordBudget(podcast.SpokenWordCount(section.Text), budgets[section.SectionID], cfg.WordCountTolerance) {
+				off = append(off, section.SectionID)
+			}
+		}
+		if len(off) == 0 {
+			break
+		}
+		for _, sectionID := range off {
+			words := podcast.SpokenWordCount(sectionText(episode, sectionID))
+			slog.Info("adjusting section length", "sectionID", sectionID, "words", words, "budget", budgets[sectionID], "pass", pass+1)
+			text, regenUsage, err := regenerateSection(ctx, date, client, cfg.TextModel, specs, system, basePrompt, topic, episode, sectionID, podcast.LengthRevisionNotes(words, budgets[sectionID]))
+			if err != nil {
+				return episode, rewrites, usage, err
+			}
+			usage = usage.Add(regenUsage)
+			episode = replaceSectionText(episode, sectionID, text)
+			rewrites++
+		}
+	}
+	return episode, rewrites, usage, nil
+}
+
+// sectionWordCounts returns the spoken word count and budget for every section.
+func sectionWordCounts(episode podcast.Episode, specs []podcast.SectionSpec) []sectionWordCount {
+	budgets := podcast.WordBudgets(specs)
+	counts := make([]sectionWordCount, 0, len(episode.Sections))
+	for _, section := range episode.Sections {
+		counts = append(counts, sectionWordCount{
+			SectionID: section.SectionID,
+			Words:     podcast.SpokenWordCount(section.Text),
+			Budget:    budgets[section.SectionID],
+		})
+	}
+	return counts
+}
+
+func sectionText(episode podcast.Episode, sectionID string) string {
+	for _, section := range episode.Sections {
+		if section.SectionID == sectionID {
+			return section.Text
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"yodex/internal/paths"
+)
+
+func TestScriptExpandsShortSections(t *testing.T) {
+	fake := &fakeTextClient{responses: []string{
+		makeWordyText(80),
+		makeWordyText(100),
+		makeWordyText(80) + ". What did you learn?",
+		testGameRound + " " + makeWordyText(170),
+		makeWordyText(460),
+	}}
+	cfgPath := setupScriptConfigTest(t, `{"targetWordCount": 820}`, fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Volcanoes", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if fake.calls != 5 {
+		t.Fatalf("expected 4 section calls plus 1 expansion, got %d", fake.calls)
+	}
+
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	data, err := os.ReadFile(paths.New("").EpisodeMeta(date))
+	if err != nil {
+		t.Fatalf("read meta: %v", err)
+	}
+	var meta scriptMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatalf("parse meta: %v", err)
+	}
+	if meta.WordTarget != 820 || len(meta.SectionWords) != 4 {
+		t.Fatalf("unexpected word targeting meta: %+v", meta)
+	}
+	topic := meta.SectionWords[1]
+	if topic.SectionID != "topic" || topic.Words != 460 || topic.Budget != 460 {
+		t.Fatalf("expected expanded topic to meet its budget, got %+v", topic)
+	}
+}
+
+func TestScriptEnforcesShowBudgetsWithoutTarget(t *testing.T) {
+	fake := &fakeTextClient{responses: []string{
+		makeWordyText(20),
+		makeWordyText(100),
+		makeWordyText(20) + ". What did you learn?",
+		testGameRound,
+		makeWordyText(200),
+	}}
+	cfgPath := setupScriptConfigTest(t, `{"showPath": "show.json"}`, fake)
+	show := `{"sections": [{"id": "intro"}, {"id": "topic", "wordBudget": 200}, {"id": "game"}, {"id": "outro"}]}`
+	if err := os.WriteFile(filepath.Join(filepath.Dir(cfgPath), "show.json"), []byte(show), 0o644); err != nil {
+		t.Fatalf("write show: %v", err)
+	}
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Volcanoes", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if fake.calls != 5 {
+		t.Fatalf("expected 4 section calls plus 1 expansion of the topic only, got %d", fake.calls)
+	}
+	meta, err := readScriptMeta(paths.New("").EpisodeMeta(time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("read meta: %v", err)
+	}
+	if topic := meta.SectionWords[1]; topic.SectionID != "topic" || topic.Words != 200 {
+		t.Fatalf("expected expanded topic to meet its budget, got %+v", topic)
+	}
+}
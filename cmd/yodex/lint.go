@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/paths"
+	"yodex/internal/podcast"
+)
+
+var lintOut io.Writer = os.Stdout
+
+// yodex lint
+func cmdLint(args []string) error {
+	var cf commonFlags
+	var fix boolFlag
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	addCommonFlags(fs, &cf)
+	fs.Var(&fix, "fix", "Rewrite files with the safe fixes applied")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	setupLogger(cf.logLevel)
+
+	files := fs.Args()
+	var episodePath string
+	var sectionIDs []string
+	if len(files) == 0 {
+		fileCfg, err := loadFileConfig(cf)
+		if err != nil {
+			return err
+		}
+		envOv, apiKey, elevenLabsKey := cfgpkg.FromEnv()
+		cfg := cfgpkg.Merge(fileCfg, envOv, cfgpkg.Overrides{}, apiKey, elevenLabsKey)
+		date, err := resolveDate(cf.date, cfg)
+		if err != nil {
+			return err
+		}
+		builder := paths.New(cfg.OutDir)
+		show, err := episodeShow(cfg, builder.EpisodeMeta(date))
+		if err != nil {
+			return err
+		}
+		episodePath = builder.EpisodeMarkdown(date)
+		sectionIDs = show.SectionIDs()
+		for _, sectionID := range sectionIDs {
+			path := builder.EpisodeSectionMarkdown(date, sectionID)
+			if _, err := os.Stat(path); err == nil {
+				files = append(files, path)
+			}
+		}
+		if len(files) == 0 {
+			files = []string{episodePath}
+		}
+	}
+
+	remaining, fixed := 0, 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		text := string(data)
+		if fix.v {
+			if updated := podcast.FixSection(text); updated != text {
+				if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+					return err
+				}
+				slog.Info("lint fixes applied", "path", path)
+				text = updated
+				fixed++
+			}
+		}
+		sectionID := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		for _, issue := range podcast.LintSection(sectionID, text) {
+			fmt.Fprintf(lintOut, "%s:%s\n", path, issue)
+			remaining++
+		}
+	}
+
+	// Fixed section files make episode.md stale; rebuild it when every
+	// section of the show is on disk.
+	if fixed > 0 && episodePath != "" && len(files) == len(sectionIDs) {
+		var episode podcast.Episode
+		for i, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			episode.Sections = append(episode.Sections, podcast.EpisodeSection{SectionID: sectionIDs[i], Text: string(data)})
+		}
+		if err := os.WriteFile(episodePath, []byte(episode.RenderMarkdown()), 0o644); err != nil {
+			return err
+		}
+		slog.Info("episode re-rendered", "path", episodePath)
+	}
+	if remaining > 0 {
+		return fmt.Errorf("%d lint issues in %d files", remaining, len(files))
+	}
+	return nil
+}
+
+// lintEpisode applies the safe lint fixes to every section of a generated
+// episode and returns the issues that remain for the script's meta.json.
+func lintEpisode(episode podcast.Episode) (podcast.Episode, []podcast.LintIssue) {
+	var issues []podcast.LintIssue
+	sections := make([]podcast.EpisodeSection, len(episode.Sections))
+	for i, section := range episode.Sections {
+		text := strings.TrimSpace(section.Text)
+		if fixed := podcast.FixSection(text); fixed != text {
+			slog.Info("lint fixes applied", "sectionID", section.SectionID)
+			text = strings.TrimSpace(fixed)
+		}
+		section.Text = text
+		sections[i] = section
+		for _, issue := range podcast.LintSection(section.SectionID, text) {
+			slog.Warn("lint issue", "sectionID", section.SectionID, "line", issue.Line, "column", issue.Column, "rule", issue.Rule, "message", issue.Message)
+			issues = append(issues, issue)
+		}
+	}
+	episode.Sections = sections
+	return episode, issues
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/paths"
+	"yodex/internal/podcast"
+)
+
+func captureLintOut(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	orig := lintOut
+	t.Cleanup(func() { lintOut = orig })
+	lintOut = &buf
+	return &buf
+}
+
+func TestLintCommandFixesSectionFiles(t *testing.T) {
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	if err := builder.EnsureOutDir(date); err != nil {
+		t.Fatalf("ensure out dir: %v", err)
+	}
+	sections := map[string]string{
+		"intro": "# Welcome\n[excited]Hi friends!\n",
+		"topic": "Do bees sleep? They do.\n",
+		"game":  "Fact or fib? [short pause] [short pause] Fact!\n",
+		"outro": "See you tomorrow!\n",
+	}
+	for id, text := range sections {
+		if err := os.WriteFile(builder.EpisodeSectionMarkdown(date, id), []byte(text), 0o644); err != nil {
+			t.Fatalf("write %s: %v", id, err)
+		}
+	}
+
+	out := captureLintOut(t)
+	if code := run([]string{"lint", "--date=2025-09-30"}); code == 0 {
+		t.Fatalf("expected lint to fail on issues")
+	}
+	for _, want := range []string{
+		builder.EpisodeSectionMarkdown(date, "intro") + ":1:1: markdown:",
+		builder.EpisodeSectionMarkdown(date, "intro") + ":2:1: tag-spacing:",
+		builder.EpisodeSectionMarkdown(date, "topic") + ":1:14: question-pause:",
+		builder.EpisodeSectionMarkdown(date, "game") + ":1:28: stacked-pauses:",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in lint output:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if code := run([]string{"lint", "--date=2025-09-30", "--fix"}); code == 0 {
+		t.Fatalf("expected the question without a pause to remain")
+	}
+	if strings.Count(out.String(), "\n") != 1 || !strings.Contains(out.String(), "question-pause") {
+		t.Fatalf("expected only the unfixable issue, got:\n%s", out.String())
+	}
+	intro, err := os.ReadFile(builder.EpisodeSectionMarkdown(date, "intro"))
+	if err != nil {
+		t.Fatalf("read intro.md: %v", err)
+	}
+	if string(intro) != "Welcome\n[excited] Hi friends!\n" {
+		t.Fatalf("unexpected fixed intro: %q", intro)
+	}
+	md, err := os.ReadFile(builder.EpisodeMarkdown(date))
+	if err != nil {
+		t.Fatalf("expected episode.md to be re-rendered: %v", err)
+	}
+	if !strings.Contains(string(md), "Fact or fib? [short pause] Fact!") || !strings.HasPrefix(string(md), "Welcome") {
+		t.Fatalf("unexpected episode.md: %q", md)
+	}
+}
+
+func TestLintCommandUsesRecapSections(t *testing.T) {
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	date := time.Date(2025, 10, 4, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	if err := builder.EnsureOutDir(date); err != nil {
+		t.Fatalf("ensure out dir: %v", err)
+	}
+	if err := writeScriptMeta(builder.EpisodeMeta(date), scriptMeta{Date: "2025-10-04", Topic: podcast.RecapTopic}); err != nil {
+		t.Fatalf("write meta: %v", err)
+	}
+	for _, id := range []string{"intro", "highlights", "game", "outro"} {
+		if err := os.WriteFile(builder.EpisodeSectionMarkdown(date, id), []byte("**"+id+"** done.\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", id, err)
+		}
+	}
+
+	captureLintOut(t)
+	if code := run([]string{"lint", "--date=2025-10-04", "--fix"}); code != 0 {
+		t.Fatalf("lint returned non-zero: %d", code)
+	}
+	highlights, err := os.ReadFile(builder.EpisodeSectionMarkdown(date, "highlights"))
+	if err != nil || string(highlights) != "highlights done.\n" {
+		t.Fatalf("expected highlights.md to be linted, got %q, %v", highlights, err)
+	}
+	md, err := os.ReadFile(builder.EpisodeMarkdown(date))
+	if err != nil || !strings.Contains(string(md), "highlights done.") {
+		t.Fatalf("expected episode.md re-rendered with the highlights, got %q, %v", md, err)
+	}
+}
+
+func TestScriptFixesLintIssues(t *testing.T) {
+	responses := makeSectionResponses(100)
+	responses[0] = "## Intro\n[excited]Welcome, friends! Ready to learn?"
+	fake := &fakeTextClient{responses: responses}
+	cfgPath := setupScriptConfigTest(t, `{}`, fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Bees", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	intro, err := os.ReadFile(builder.EpisodeSectionMarkdown(date, "intro"))
+	if err != nil {
+		t.Fatalf("read intro.md: %v", err)
+	}
+	if string(intro) != "Intro\n[excited] Welcome, friends! Ready to learn?\n" {
+		t.Fatalf("expected the safe fixes in intro.md, got %q", intro)
+	}
+	metaBytes, err := os.ReadFile(builder.EpisodeMeta(date))
+	if err != nil {
+		t.Fatalf("read meta.json: %v", err)
+	}
+	var meta scriptMeta
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		t.Fatalf("parse meta.json: %v", err)
+	}
+	var introIssues []string
+	for _, issue := range meta.Lint {
+		if issue.SectionID == "intro" {
+			introIssues = append(introIssues, issue.String())
+		}
+	}
+	if len(introIssues) != 1 || !strings.HasPrefix(introIssues[0], "2:43: question-pause:") {
+		t.Fatalf("expected the remaining intro issue in meta.json, got %q", introIssues)
+	}
+}
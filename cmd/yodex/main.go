@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	_ "time/tzdata" // timezone data for hosts without a zoneinfo database
 )
 
 var version = "0.1.0"
@@ -44,6 +45,18 @@ func run(args []string) int {
 			return 1
 		}
 		return 0
+	case "history":
+		if err := cmdHistory(args[1:]); err != nil {
+			slog.Error("history failed", "err", err)
+			return 1
+		}
+		return 0
+	case "lint":
+		if err := cmdLint(args[1:]); err != nil {
+			slog.Error("lint failed", "err", err)
+			return 1
+		}
+		return 0
 	case "all":
 		if err := cmdAll(args[1:]); err != nil {
 			slog.Error("all failed", "err", err)
@@ -70,8 +83,10 @@ Subcommands:
   script   Generate Markdown script for a date
   audio    Generate MP3 audio from a script file/date
   publish  Upload MP3 to S3 and print URL
-  topic    Print today's topic (or generate one)
+  topic    Print today's topic (or generate one), or manage the topic queue
+  lint     Check section files for tag, pause, and markdown problems
   all      (optional) Run script -> audio -> publish
+  history  List, show, remove, import, export, or migrate topic history
   version  Print version
 
 Run "yodex <subcommand> -h" for flags.
@@ -102,6 +102,7 @@ func TestAudioFlagParsing(t *testing.T) {
 func TestPublishFlagParsing(t *testing.T) {
 	origUploader := newUploader
 	t.Cleanup(func() { newUploader = origUploader })
+	stubTopicHistory(t)
 
 	newUploader = func(ctx context.Context, bucket, prefix, region string) (uploader, error) {
 		return &fakeUploader{}, nil
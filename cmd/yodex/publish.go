@@ -52,11 +52,7 @@ func cmdPublish(args []string) error {
 		return err
 	}
 	setupLogger(cf.logLevel)
-	date, err := resolveDate(cf.date)
-	if err != nil {
-		return err
-	}
-	fileCfg, err := cfgpkg.LoadFile(cf.config)
+	fileCfg, err := loadFileConfig(cf)
 	if err != nil {
 		return err
 	}
@@ -72,6 +68,10 @@ func cmdPublish(args []string) error {
 		flagOv.Region = &region.v
 	}
 	cfg := cfgpkg.Merge(fileCfg, envOv, flagOv, apiKey, elevenLabsKey)
+	date, err := resolveDate(cf.date, cfg)
+	if err != nil {
+		return err
+	}
 
 	if err := cfgpkg.ValidateForPublish(cfg); err != nil {
 		return err
@@ -82,11 +82,17 @@ func cmdPublish(args []string) error {
 		return err
 	}
 
-	builder := paths.New("")
+	builder := paths.New(cfg.OutDir)
 	mp3Path := builder.EpisodeMP3(date)
 	mdPath := builder.EpisodeMarkdown(date)
 	metaPath := builder.EpisodeMeta(date)
 
+	if err := checkReviewAllowsPublish(builder.EpisodeReview(date)); err != nil {
+		return err
+	}
+	if err := checkFactCheckAllowsPublish(builder.EpisodeFactCheck(date)); err != nil {
+		return err
+	}
 	if err := uploadAndCopy(context.Background(), up, date, "episode.mp3", mp3Path, mp3ContentType, cacheArchive, cacheLatest); err != nil {
 		return err
 	}
@@ -99,6 +105,10 @@ func cmdPublish(args []string) error {
 		}
 	}
 
+	if err := markEpisodePublished(context.Background(), cfg, date); err != nil {
+		slog.Warn("failed to mark episode published in topic history", "err", err)
+	}
+
 	slog.Info("publish completed", "date", date.Format("2006-01-02"), "bucket", cfg.S3Bucket, "prefix", cfg.S3Prefix, "region", cfg.Region, "includeScript", includeScript.v)
 	return nil
 }
@@ -31,6 +31,7 @@ func (f *fakeUploader) KeyForDate(t time.Time, filename string) string {
 func TestPublishUploadsMP3Only(t *testing.T) {
 	orig := newUploader
 	t.Cleanup(func() { newUploader = orig })
+	stubTopicHistory(t)
 
 	fake := &fakeUploader{}
 	newUploader = func(ctx context.Context, bucket, prefix, region string) (uploader, error) {
@@ -70,6 +71,7 @@ func TestPublishUploadsMP3Only(t *testing.T) {
 func TestPublishUploadsScriptAndMeta(t *testing.T) {
 	orig := newUploader
 	t.Cleanup(func() { newUploader = orig })
+	stubTopicHistory(t)
 
 	fake := &fakeUploader{}
 	newUploader = func(ctx context.Context, bucket, prefix, region string) (uploader, error) {
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"yodex/internal/ai"
+	"yodex/internal/podcast"
+)
+
+// maxSimplifyPasses bounds how many times a section is rewritten to bring it
+// under the reading grade ceiling.
+const maxSimplifyPasses = 2
+
+// simplifySections rewrites generated and game sections whose Flesch-Kincaid
+// grade is above the ceiling. Static sections and sections not in English are
+// left alone. Empty rewrites, rewrites that add, drop, or reorder tags, and
+// game rewrites that fail their game's checks are discarded. It returns the
+// updated episode and the number of rewrites kept.
+func simplifySections(ctx context.Context, client ai.TextClient, model, system, audience string, gradeCeiling float64, date time.Time, specs []podcast.SectionSpec, episode podcast.Episode) (podcast.Episode, int, ai.TokenUsage, error) {
+	byID := make(map[string]podcast.SectionSpec, len(specs))
+	for _, spec := range specs {
+		byID[spec.SectionID] = spec
+	}
+	var usage ai.TokenUsage
+	rewrites := 0
+	for _, section := range episode.Sections {
+		spec, ok := byID[section.SectionID]
+		if !ok || spec.Kind == podcast.SectionKindStatic {
+			continue
+		}
+		locale, err := podcast.LookupLocale(spec.Language)
+		if err != nil {
+			return episode, rewrites, usage, err
+		}
+		if !locale.IsEnglish() {
+			slog.Info("skipping reading grade ceiling for non-English section", "sectionID", section.SectionID, "language", locale.Tag)
+			continue
+		}
+		var game *podcast.GameRules
+		if spec.Kind == podcast.SectionKindGame {
+			rules, _, err := sectionGame(date, spec)
+			if err != nil {
+				return episode, rewrites, usage, err
+			}
+			game = &rules
+		}
+		text := section.Text
+		for pass := 0; pass < maxSimplifyPasses; pass++ {
+			metrics := podcast.AnalyzeReadability(text)
+			if metrics.FleschKincaidGrade <= gradeCeiling {
+				break
+			}
+			slog.Info("simplifying section", "sectionID", section.SectionID, "grade", metrics.FleschKincaidGrade, "ceiling", gradeCeiling, "pass", pass+1)
+			prompt := podcast.BuildSimplifyPrompt(audience, section.SectionID, text, metrics, gradeCeiling)
+			rewritten, callUsage, err := client.GenerateTextWithUsage(ctx, model, system, prompt)
+			if err != nil {
+				return episode, rewrites, usage, err
+			}
+			usage = usage.Add(callUsage)
+			if strings.TrimSpace(rewritten) == "" || !podcast.TagsPreserved(text, rewritten) {
+				slog.Warn("discarding simplified section that changed tags", "sectionID", section.SectionID)
+				continue
+			}
+			if game != nil {
+				if problems := game.ValidateRound(rewritten); len(problems) > 0 {
+					slog.Warn("discarding simplified game round that fails validation", "sectionID", section.SectionID, "problems", problems)
+					continue
+				}
+			}
+			text = rewritten
+			rewrites++
+		}
+		if text != section.Text {
+			episode = replaceSectionText(episode, section.SectionID, text)
+		}
+	}
+	return episode, rewrites, usage, nil
+}
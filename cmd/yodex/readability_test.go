@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/paths"
+	"yodex/internal/podcast"
+)
+
+const hardTopicText = "Photosynthesis transforms electromagnetic radiation into chemical energy, sustaining terrestrial ecosystems through complicated biochemical pathways. [short pause]"
+
+func TestScriptSimplifiesSectionsOverGradeCeiling(t *testing.T) {
+	responses := makeSectionResponses(100)
+	responses[1] = hardTopicText
+	responses[3] = testGameRound
+	responses = append(responses, "Plants use sunlight to make food. [short pause]")
+	fake := &fakeTextClient{responses: responses}
+	cfgPath := setupScriptConfigTest(t, `{"readingGradeCeiling": 6}`, fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Plants", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if fake.calls != 5 {
+		t.Fatalf("expected 4 section calls plus 1 rewrite, got %d", fake.calls)
+	}
+
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	topic, err := os.ReadFile(builder.EpisodeSectionMarkdown(date, "topic"))
+	if err != nil {
+		t.Fatalf("read topic.md: %v", err)
+	}
+	if !strings.HasPrefix(string(topic), "Plants use sunlight") {
+		t.Fatalf("expected simplified topic, got %q", topic)
+	}
+	data, err := os.ReadFile(builder.EpisodeMeta(date))
+	if err != nil {
+		t.Fatalf("read meta: %v", err)
+	}
+	var meta scriptMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatalf("parse meta: %v", err)
+	}
+	if len(meta.Readability) != 4 || meta.Readability[1].SectionID != "topic" {
+		t.Fatalf("expected readability for every section, got %+v", meta.Readability)
+	}
+	if meta.Readability[1].FleschKincaidGrade > 6 {
+		t.Fatalf("expected simplified topic under ceiling, got %v", meta.Readability[1].FleschKincaidGrade)
+	}
+}
+
+func TestSimplifySectionsSkipsStaticAndNonEnglishAndKeepsGameRules(t *testing.T) {
+	t.Setenv("YODEX_GAME_RULES_DIR", filepath.Join("..", "..", "internal", "podcast", "games"))
+	hardGameRound := "Today's challenge: engineer an extraordinarily sophisticated umbrella-hat combination. " +
+		"First question: which unconventional, environmentally considerate materials would you incorporate? [long pause] Magnificent imagination! " +
+		"Second question: which revolutionary, technologically elaborate features would it possess? [long pause] Tremendously inventive."
+	episode := podcast.Episode{Sections: []podcast.EpisodeSection{
+		{SectionID: "topic", Text: hardTopicText},
+		{SectionID: "sponsor", Text: hardTopicText},
+		{SectionID: "spanish", Text: hardTopicText},
+		{SectionID: "game", Text: hardGameRound},
+	}}
+	specs := []podcast.SectionSpec{
+		{SectionID: "topic", Kind: podcast.SectionKindGenerated, Language: "en"},
+		{SectionID: "sponsor", Kind: podcast.SectionKindStatic, Language: "en"},
+		{SectionID: "spanish", Kind: podcast.SectionKindGenerated, Language: "es"},
+		{SectionID: "game", Kind: podcast.SectionKindGame, Language: "en", Game: "build-it-brainstorm"},
+	}
+	fake := &fakeTextClient{responses: []string{
+		"Plants use sunlight to make food. [short pause]",
+		"Make a hat. [long pause] Nice.",
+		"Make a hat. [long pause] Nice.",
+	}}
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	simplified, rewrites, _, err := simplifySections(context.Background(), fake, "model", "system", "kids", 6, date, specs, episode)
+	if err != nil {
+		t.Fatalf("simplifySections: %v", err)
+	}
+	if rewrites != 1 || fake.calls != 3 {
+		t.Fatalf("expected 1 kept rewrite from 3 calls, got %d from %d", rewrites, fake.calls)
+	}
+	want := []string{"Plants use sunlight to make food. [short pause]", hardTopicText, hardTopicText, hardGameRound}
+	for i, section := range simplified.Sections {
+		if section.Text != want[i] {
+			t.Fatalf("section %s: expected %q, got %q", section.SectionID, want[i], section.Text)
+		}
+	}
+}
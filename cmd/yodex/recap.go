@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/paths"
+	"yodex/internal/podcast"
+	"yodex/internal/storage"
+)
+
+// recapDays is how many days before the recap day it reviews.
+const recapDays = 6
+
+type recapStore interface {
+	DownloadBytes(ctx context.Context, key string) ([]byte, error)
+	KeyForDate(t time.Time, filename string) string
+}
+
+var newRecapStore = func(ctx context.Context, cfg cfgpkg.Config) (recapStore, error) {
+	return storage.New(ctx, cfg.S3Bucket, cfg.S3Prefix, cfg.Region)
+}
+
+// datePinned reports whether a backlog topic is pinned to date, or was taken
+// from a pin by an earlier run for date. A pinned topic replaces the weekly
+// recap. Store failures are logged and reported as no pin.
+func datePinned(ctx context.Context, cfg cfgpkg.Config, date time.Time) bool {
+	key := date.Format("2006-01-02")
+	if store, err := openTopicHistory(ctx, cfg); err != nil {
+		slog.Warn("failed to initialize topic history store", "err", err)
+	} else if history, err := store.Load(ctx); err != nil {
+		slog.Warn("failed to load topic history", "location", store.Location(), "err", err)
+	} else if history.Entries[key].Backlog == podcast.BacklogPin {
+		return true
+	}
+	store, err := openTopicBacklog(ctx, cfg)
+	if err != nil {
+		slog.Warn("failed to initialize topic backlog store", "err", err)
+		return false
+	}
+	backlog, err := store.Load(ctx)
+	if err != nil {
+		slog.Warn("failed to load topic backlog", "location", store.Location(), "err", err)
+		return false
+	}
+	return strings.TrimSpace(backlog.Pins[key]) != ""
+}
+
+// loadRecapEpisodes reads the episode.md and meta.json of the recapDays days
+// before date, oldest first. Each day is read from the out directory, or from
+// S3 when it is not there and a bucket is configured. Days without both files
+// are skipped.
+func loadRecapEpisodes(ctx context.Context, cfg cfgpkg.Config, date time.Time) ([]podcast.RecapEpisode, error) {
+	builder := paths.New(cfg.OutDir)
+	var store recapStore
+	if cfg.S3Bucket != "" {
+		var err error
+		if store, err = newRecapStore(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+	var episodes []podcast.RecapEpisode
+	for i := recapDays; i >= 1; i-- {
+		day := date.AddDate(0, 0, -i)
+		script, err := readEpisodeFile(ctx, store, day, builder.EpisodeMarkdown(day), "episode.md")
+		if err != nil {
+			return nil, err
+		}
+		metaData, err := readEpisodeFile(ctx, store, day, builder.EpisodeMeta(day), "meta.json")
+		if err != nil {
+			return nil, err
+		}
+		if script == nil || metaData == nil {
+			slog.Debug("no episode to recap", "date", day.Format("2006-01-02"))
+			continue
+		}
+		var meta scriptMeta
+		if err := json.Unmarshal(metaData, &meta); err != nil {
+			slog.Warn("skipping episode with unreadable meta.json", "date", day.Format("2006-01-02"), "err", err)
+			continue
+		}
+		if meta.Status != "" {
+			slog.Info("skipping unpublishable episode", "date", day.Format("2006-01-02"), "status", meta.Status)
+			continue
+		}
+		episodes = append(episodes, podcast.RecapEpisode{
+			Date:   day.Format("2006-01-02"),
+			Topic:  meta.Topic,
+			Title:  meta.Title,
+			Script: strings.TrimSpace(string(script)),
+		})
+	}
+	return episodes, nil
+}
+
+// readEpisodeFile returns a file from an earlier episode's local out
+// directory, falling back to its S3 copy; nil when neither exists.
+func readEpisodeFile(ctx context.Context, store recapStore, date time.Time, localPath, filename string) ([]byte, error) {
+	data, err := os.ReadFile(localPath)
+	if err == nil {
+		return data, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if store == nil {
+		return nil, nil
+	}
+	key := store.KeyForDate(date, filename)
+	data, err = store.DownloadBytes(ctx, key)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("download %s: %w", key, err)
+	}
+	return data, nil
+}
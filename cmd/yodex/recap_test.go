@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"yodex/internal/ai"
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/paths"
+	"yodex/internal/podcast"
+)
+
+type fakeRecapStore struct {
+	objects map[string]string
+}
+
+func (f fakeRecapStore) DownloadBytes(ctx context.Context, key string) ([]byte, error) {
+	data, ok := f.objects[key]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return []byte(data), nil
+}
+
+func (f fakeRecapStore) KeyForDate(t time.Time, filename string) string {
+	return t.Format("2006/01/02/") + filename
+}
+
+func writeTestEpisode(t *testing.T, outDir string, date time.Time, topic, script string) {
+	t.Helper()
+	builder := paths.New(outDir)
+	if err := builder.EnsureOutDir(date); err != nil {
+		t.Fatalf("ensure out dir: %v", err)
+	}
+	meta, err := json.Marshal(scriptMeta{Date: date.Format("2006-01-02"), Topic: topic, Title: topic})
+	if err != nil {
+		t.Fatalf("marshal meta: %v", err)
+	}
+	if err := os.WriteFile(builder.EpisodeMeta(date), meta, 0o644); err != nil {
+		t.Fatalf("write meta: %v", err)
+	}
+	if err := os.WriteFile(builder.EpisodeMarkdown(date), []byte(script+"\n"), 0o644); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+}
+
+func TestLoadRecapEpisodesFallsBackToStorage(t *testing.T) {
+	orig := newRecapStore
+	t.Cleanup(func() { newRecapStore = orig })
+	newRecapStore = func(ctx context.Context, cfg cfgpkg.Config) (recapStore, error) {
+		return fakeRecapStore{objects: map[string]string{
+			"2026/06/09/episode.md": "Octopuses have three hearts.",
+			"2026/06/09/meta.json":  `{"topic": "Octopuses", "title": "Eight Arms"}`,
+		}}, nil
+	}
+
+	cfg := cfgpkg.Default()
+	cfg.OutDir = t.TempDir()
+	cfg.S3Bucket = "bucket"
+	writeTestEpisode(t, cfg.OutDir, time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC), "Volcanoes", "Lava is molten rock.")
+	// Days in neither place, and blocked episodes, are skipped.
+	episodes, err := loadRecapEpisodes(context.Background(), cfg, time.Date(2026, 6, 13, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []podcast.RecapEpisode{
+		{Date: "2026-06-08", Topic: "Volcanoes", Title: "Volcanoes", Script: "Lava is molten rock."},
+		{Date: "2026-06-09", Topic: "Octopuses", Title: "Eight Arms", Script: "Octopuses have three hearts."},
+	}
+	if len(episodes) != len(want) || episodes[0] != want[0] || episodes[1] != want[1] {
+		t.Fatalf("unexpected episodes: %+v", episodes)
+	}
+}
+
+func TestScriptWritesWeeklyRecap(t *testing.T) {
+	origClient := newTextClient
+	t.Cleanup(func() { newTextClient = origClient })
+	responses := makeSectionResponses(800)
+	var quiz strings.Builder
+	for _, n := range []string{"one", "two", "three", "four", "five"} {
+		quiz.WriteString("Question " + n + ", from our episode about volcanoes: what is lava? [short pause] The answer is... molten rock! ")
+	}
+	responses[3] = quiz.String() + makeWordyText(400)
+	fake := &fakeTextClient{responses: responses}
+	newTextClient = func(apiKey string) (ai.TextClient, error) {
+		return fake, nil
+	}
+
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	repoRoot := filepath.Dir(filepath.Dir(origWD))
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	writeTestEpisode(t, "", time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC), "Volcanoes", "Lava is molten rock.")
+	writeTestEpisode(t, "", time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC), "Honeybees", "Bees dance to share directions.")
+
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	t.Setenv("YODEX_RECAP_DAY", "saturday")
+	t.Setenv("YODEX_GAME_RULES_DIR", filepath.Join(repoRoot, "internal", "podcast", "games"))
+	if code := run([]string{"script", "--date=2026-06-13"}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if fake.calls != 4 {
+		t.Fatalf("expected 4 AI calls and no topic generation, got %d", fake.calls)
+	}
+	if !strings.Contains(fake.prompts[1], "Section ID: highlights") || !strings.Contains(fake.prompts[1], "Bees dance to share directions.") {
+		t.Fatalf("expected the week's scripts in the highlights prompt, got %q", fake.prompts[1])
+	}
+	if !strings.Contains(fake.prompts[3], "Game: Week Quiz") || !strings.Contains(fake.systems[3], "Lava is molten rock.") {
+		t.Fatalf("expected the week quiz over the week's facts, got %q", fake.prompts[3])
+	}
+
+	date := time.Date(2026, 6, 13, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	if _, err := os.Stat(builder.EpisodeSectionMarkdown(date, "highlights")); err != nil {
+		t.Fatalf("missing highlights.md: %v", err)
+	}
+	metaBytes, err := os.ReadFile(builder.EpisodeMeta(date))
+	if err != nil {
+		t.Fatalf("read meta.json: %v", err)
+	}
+	var meta scriptMeta
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		t.Fatalf("parse meta.json: %v", err)
+	}
+	if meta.Topic != podcast.RecapTopic || meta.Game != "week-quiz" {
+		t.Fatalf("unexpected recap meta: %+v", meta)
+	}
+}
+
+func TestScriptPinOverridesWeeklyRecap(t *testing.T) {
+	fake := &fakeTextClient{responses: append(makeSectionResponses(100), makeSectionResponses(100)...)}
+	cfgPath := setupScriptConfigTest(t, `{"recapDay": "saturday"}`, fake)
+	writeTestEpisode(t, "", time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC), "Volcanoes", "Lava is molten rock.")
+	if err := os.MkdirAll("out", 0o755); err != nil {
+		t.Fatalf("mkdir out: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join("out", "topic-backlog.json"), []byte(`{"pins": {"2026-06-13": "The Moon Landing"}}`), 0o644); err != nil {
+		t.Fatalf("write backlog: %v", err)
+	}
+
+	if code := run([]string{"script", "--date=2026-06-13", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	date := time.Date(2026, 6, 13, 0, 0, 0, 0, time.UTC)
+	metaBytes, err := os.ReadFile(paths.New("").EpisodeMeta(date))
+	if err != nil {
+		t.Fatalf("read meta.json: %v", err)
+	}
+	var meta scriptMeta
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		t.Fatalf("parse meta.json: %v", err)
+	}
+	if meta.Topic != "The Moon Landing" {
+		t.Fatalf("expected the pinned topic instead of the recap, got %q", meta.Topic)
+	}
+
+	// A rerun keeps the pinned topic though the pin has been used.
+	if code := run([]string{"script", "--date=2026-06-13", "--overwrite", "--config", cfgPath}); code != 0 {
+		t.Fatalf("rerun returned non-zero: %d", code)
+	}
+	if metaBytes, err = os.ReadFile(paths.New("").EpisodeMeta(date)); err != nil {
+		t.Fatalf("read meta.json: %v", err)
+	}
+	if err := json.Unmarshal(metaBytes, &meta); err != nil || meta.Topic != "The Moon Landing" {
+		t.Fatalf("expected the rerun to keep the pinned topic, got %q (%v)", meta.Topic, err)
+	}
+}
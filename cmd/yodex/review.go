@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"yodex/internal/ai"
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/podcast"
+)
+
+// maxSafetyRegenerations bounds how many times flagged sections are rewritten
+// before the episode is blocked.
+const maxSafetyRegenerations = 2
+
+// reviewReport is written to review.json next to meta.json.
+type reviewReport struct {
+	Threshold     string                  `json:"threshold"`
+	Action        string                  `json:"action"`
+	Blocked       bool                    `json:"blocked"`
+	Regenerations int                     `json:"regenerations"`
+	Findings      []podcast.ReviewFinding `json:"findings"`
+}
+
+// runSafetyReview reviews the episode and, when configured, regenerates only
+// the sections with findings at or above the threshold.
+func runSafetyReview(ctx context.Context, cfg cfgpkg.Config, date time.Time, client ai.TextClient, specs []podcast.SectionSpec, system, basePrompt, topic string, episode podcast.Episode) (podcast.Episode, reviewReport, ai.TokenUsage, error) {
+	threshold, err := podcast.ParseSeverity(cfg.SafetyReviewThreshold)
+	if err != nil {
+		return episode, reviewReport{}, ai.TokenUsage{}, err
+	}
+	action := strings.ToLower(strings.TrimSpace(cfg.SafetyReviewAction))
+	report := reviewReport{Threshold: string(threshold), Action: action}
+	var usage ai.TokenUsage
+
+	for {
+		slog.Info("running safety review", "regenerations", report.Regenerations)
+		review, reviewUsage, err := reviewEpisodeOnce(ctx, client, cfg.TextModel, podcast.IdentityFromConfig(cfg).Audience, topic, episode)
+		if err != nil {
+			return episode, report, usage, err
+		}
+		usage = usage.Add(reviewUsage)
+		report.Findings = review.Findings
+		blocking := review.BlockingSections(threshold)
+		if len(blocking) == 0 {
+			slog.Info("safety review passed", "findings", len(review.Findings))
+			return episode, report, usage, nil
+		}
+		slog.Warn("safety review flagged sections", "sections", blocking, "threshold", string(threshold))
+		if action != cfgpkg.SafetyActionRegenerate || report.Regenerations >= maxSafetyRegenerations {
+			report.Blocked = true
+			return episode, report, usage, nil
+		}
+		for _, sectionID := range blocking {
+			text, regenUsage, err := regenerateSection(ctx, date, client, cfg.TextModel, specs, system, basePrompt, topic, episode, sectionID, review.RevisionNotes(sectionID, threshold))
+			if err != nil {
+				return episode, report, usage, err
+			}
+			usage = usage.Add(regenUsage)
+			episode = replaceSectionText(episode, sectionID, text)
+		}
+		report.Regenerations++
+	}
+}
+
+func reviewEpisodeOnce(ctx context.Context, client ai.TextClient, model, audience, topic string, episode podcast.Episode) (podcast.SafetyReview, ai.TokenUsage, error) {
+	var moderated []podcast.ReviewFinding
+	if mod, ok := client.(podcast.Moderator); ok {
+		findings, err := podcast.ModerateEpisode(ctx, mod, episode)
+		if err != nil {
+			return podcast.SafetyReview{}, ai.TokenUsage{}, fmt.Errorf("moderation: %w", err)
+		}
+		moderated = findings
+	}
+	review, usage, err := podcast.ReviewEpisode(ctx, client, model, audience, topic, episode)
+	if err != nil {
+		return podcast.SafetyReview{}, ai.TokenUsage{}, fmt.Errorf("safety review: %w", err)
+	}
+	review.Findings = append(moderated, review.Findings...)
+	return review, usage, nil
+}
+
+func replaceSectionText(episode podcast.Episode, sectionID, text string) podcast.Episode {
+	sections := make([]podcast.EpisodeSection, len(episode.Sections))
+	copy(sections, episode.Sections)
+	for i := range sections {
+		if sections[i].SectionID == sectionID {
+			sections[i].Text = strings.TrimSpace(text)
+		}
+	}
+	episode.Sections = sections
+	return episode
+}
+
+// checkReviewAllowsPublish refuses to publish an episode whose review.json is blocked.
+func checkReviewAllowsPublish(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read safety review: %w", err)
+	}
+	var report reviewReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return fmt.Errorf("parse safety review %s: %w", path, err)
+	}
+	if report.Blocked {
+		return fmt.Errorf("refusing to publish: safety review blocked this episode (see %s)", path)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/paths"
+)
+
+func setupReviewTest(t *testing.T, action string, fake *fakeTextClient) string {
+	t.Helper()
+	return setupScriptConfigTest(t, `{"safetyReview": true, "safetyReviewThreshold": "medium", "safetyReviewAction": "`+action+`"}`, fake)
+}
+
+const flaggedTopicReview = `{"findings":[{"section_id":"topic","category":"unsafe_instructions","severity":"high","note":"tells kids to touch lava"}]}`
+const cleanReview = `{"findings":[{"section_id":"topic","category":"unsafe_instructions","severity":"none","note":""}]}`
+
+func TestScriptSafetyReviewRegeneratesFlaggedSection(t *testing.T) {
+	fake := &fakeTextClient{
+		responses:     append(makeSectionResponses(100), "Safer topic text."),
+		jsonResponses: []string{flaggedTopicReview, cleanReview},
+	}
+	cfgPath := setupReviewTest(t, "regenerate", fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Volcanoes", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if fake.calls != 5 {
+		t.Fatalf("expected 4 section calls plus 1 regeneration, got %d", fake.calls)
+	}
+	if fake.jsonCalls != 2 {
+		t.Fatalf("expected 2 review calls, got %d", fake.jsonCalls)
+	}
+
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	topic, err := os.ReadFile(builder.EpisodeSectionMarkdown(date, "topic"))
+	if err != nil {
+		t.Fatalf("read topic.md: %v", err)
+	}
+	if strings.TrimSpace(string(topic)) != "Safer topic text." {
+		t.Fatalf("expected regenerated topic, got %q", topic)
+	}
+	intro, err := os.ReadFile(builder.EpisodeSectionMarkdown(date, "intro"))
+	if err != nil {
+		t.Fatalf("read intro.md: %v", err)
+	}
+	if strings.TrimSpace(string(intro)) != "Intro text." {
+		t.Fatalf("expected intro untouched, got %q", intro)
+	}
+	var report reviewReport
+	data, err := os.ReadFile(builder.EpisodeReview(date))
+	if err != nil {
+		t.Fatalf("read review.json: %v", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("parse review.json: %v", err)
+	}
+	if report.Blocked || report.Regenerations != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestScriptBlockedGeneratedTopicLeavesHistory(t *testing.T) {
+	fake := &fakeTextClient{
+		responses:     append([]string{"Volcanoes"}, makeSectionResponses(100)...),
+		jsonResponses: []string{flaggedTopicReview},
+	}
+	cfgPath := setupReviewTest(t, "block", fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected script to fail when review blocks")
+	}
+	meta, err := readScriptMeta(paths.New("").EpisodeMeta(time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)))
+	if err != nil || meta.Topic != "Volcanoes" || meta.Status != scriptStatusBlocked {
+		t.Fatalf("expected a blocked episode about the generated topic, got %+v, %v", meta, err)
+	}
+	history, err := os.ReadFile("out/topic-history.json")
+	if err != nil {
+		t.Fatalf("expected topic selection to have written the history: %v", err)
+	}
+	if strings.Contains(string(history), "2025-09-30") || strings.Contains(string(history), "Volcanoes") {
+		t.Fatalf("expected the selected topic rolled back, got %s", history)
+	}
+}
+
+func TestScriptBlockedEpisodeReturnsPinnedTopic(t *testing.T) {
+	fake := &fakeTextClient{
+		responses:     makeSectionResponses(100),
+		jsonResponses: []string{flaggedTopicReview},
+	}
+	cfgPath := setupReviewTest(t, "block", fake)
+	if err := os.MkdirAll("out", 0o755); err != nil {
+		t.Fatalf("mkdir out: %v", err)
+	}
+	if err := os.WriteFile("out/topic-backlog.json", []byte(`{"pins": {"2025-09-30": "The Moon Landing"}, "queue": []}`), 0o644); err != nil {
+		t.Fatalf("write backlog: %v", err)
+	}
+
+	if code := run([]string{"script", "--date=2025-09-30", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected script to fail when review blocks")
+	}
+	backlog, err := os.ReadFile("out/topic-backlog.json")
+	if err != nil || !strings.Contains(string(backlog), `"2025-09-30": "The Moon Landing"`) {
+		t.Fatalf("expected the pin returned to the backlog, got %s, %v", backlog, err)
+	}
+
+	*fake = fakeTextClient{responses: makeSectionResponses(100), jsonResponses: []string{cleanReview}}
+	if code := run([]string{"script", "--date=2025-09-30", "--overwrite", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	meta, err := readScriptMeta(paths.New("").EpisodeMeta(time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)))
+	if err != nil || meta.Topic != "The Moon Landing" || meta.Status != "" {
+		t.Fatalf("expected the rerun to use the pinned topic, got %+v, %v", meta, err)
+	}
+}
+
+func TestScriptSafetyReviewBlocksPublish(t *testing.T) {
+	fake := &fakeTextClient{
+		responses:     makeSectionResponses(100),
+		jsonResponses: []string{flaggedTopicReview},
+	}
+	cfgPath := setupReviewTest(t, "block", fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Volcanoes", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected script to fail when review blocks")
+	}
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	meta, err := readScriptMeta(builder.EpisodeMeta(date))
+	if err != nil || meta.Status != scriptStatusBlocked {
+		t.Fatalf("expected a blocked status in meta.json, got %+v, %v", meta, err)
+	}
+	if history, err := os.ReadFile("out/topic-history.json"); err == nil && strings.Contains(string(history), "2025-09-30") {
+		t.Fatalf("expected a blocked episode to stay out of the topic history, got %s", history)
+	}
+	if err := os.WriteFile(builder.EpisodeMP3(date), []byte("audio"), 0o644); err != nil {
+		t.Fatalf("write mp3: %v", err)
+	}
+
+	origUploader := newUploader
+	t.Cleanup(func() { newUploader = origUploader })
+	up := &fakeUploader{}
+	newUploader = func(ctx context.Context, bucket, prefix, region string) (uploader, error) {
+		return up, nil
+	}
+	if code := run([]string{"publish", "--date=2025-09-30", "--bucket=b", "--region=us-west-2", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected publish to refuse a blocked episode")
+	}
+	if len(up.uploads) != 0 {
+		t.Fatalf("expected no uploads, got %v", up.uploads)
+	}
+}
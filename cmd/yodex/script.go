@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"strings"
@@ -20,25 +21,89 @@ var newTextClient = func(apiKey string) (ai.TextClient, error) {
 	return ai.New(apiKey, "")
 }
 
+const (
+	scriptModeSectioned  = "sectioned"
+	scriptModeStructured = "structured"
+)
+
 type scriptMeta struct {
-	Date      string `json:"date"`
-	Topic     string `json:"topic"`
-	Title     string `json:"title"`
-	WordCount int    `json:"wordCount"`
-	Model     string `json:"model"`
+	Show      string    `json:"show,omitempty"`
+	Language  string    `json:"language,omitempty"`
+	Date      string    `json:"date"`
+	Timezone  string    `json:"timezone"`
+	Topic     string    `json:"topic"`
+	Title     string    `json:"title"`
+	Game      string    `json:"game,omitempty"`
+	WordCount int       `json:"wordCount"`
+	Model     string    `json:"model"`
+	Mode      string    `json:"mode"`
+	Usage     usageMeta `json:"usage"`
+	// Status is empty for a finished episode, or says why it may not be
+	// published; such episodes are left out of the topic history.
+	Status string `json:"status,omitempty"`
+
+	SafetyHits  []podcast.SafetyHit          `json:"safetyHits,omitempty"`
+	Readability []podcast.SectionReadability `json:"readability,omitempty"`
+	Lint        []podcast.LintIssue          `json:"lint,omitempty"`
+
+	WordTarget   int                `json:"wordTarget,omitempty"`
+	SectionWords []sectionWordCount `json:"sectionWords,omitempty"`
+
+	// StaleAudio lists sections rewritten by script --section since their
+	// MP3 was made; yodex audio regenerates just those.
+	StaleAudio []string `json:"staleAudio,omitempty"`
+}
+
+type usageMeta struct {
+	InputTokens     int64 `json:"inputTokens"`
+	OutputTokens    int64 `json:"outputTokens"`
+	TotalTokens     int64 `json:"totalTokens"`
+	CachedTokens    int64 `json:"cachedTokens"`
+	ReasoningTokens int64 `json:"reasoningTokens"`
+}
+
+func newUsageMeta(u ai.TokenUsage) usageMeta {
+	return usageMeta{
+		InputTokens:     u.InputTokens,
+		OutputTokens:    u.OutputTokens,
+		TotalTokens:     u.TotalTokens,
+		CachedTokens:    u.CachedTokens,
+		ReasoningTokens: u.ReasoningTokens,
+	}
+}
+
+func (u usageMeta) tokenUsage() ai.TokenUsage {
+	return ai.TokenUsage{
+		InputTokens:     u.InputTokens,
+		OutputTokens:    u.OutputTokens,
+		TotalTokens:     u.TotalTokens,
+		CachedTokens:    u.CachedTokens,
+		ReasoningTokens: u.ReasoningTokens,
+	}
 }
 
+// scriptStatusBlocked is the meta.json status of an episode the safety
+// review blocked.
+const scriptStatusBlocked = "blocked"
+
+// scriptStatusFactCheckFailed is the meta.json status of an episode whose
+// fact check failed.
+const scriptStatusFactCheckFailed = "fact-check-failed"
+
 // yodex script
 func cmdScript(args []string) error {
 	var cf commonFlags
 	var topic stringFlag
 	var overwrite boolFlag
+	var mode, section string
 
 	fs := flag.NewFlagSet("script", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 	addCommonFlags(fs, &cf)
 	fs.Var(&topic, "topic", "Explicit topic (overrides config and generation)")
 	fs.Var(&overwrite, "overwrite", "Allow overwriting existing outputs")
+	fs.StringVar(&mode, "mode", scriptModeSectioned, "Generation mode: sectioned (one call per section) or structured (one JSON call)")
+	fs.StringVar(&section, "section", "", "Regenerate only this section of the date's existing episode")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -47,12 +112,12 @@ func cmdScript(args []string) error {
 		return err
 	}
 	setupLogger(cf.logLevel)
-	date, err := resolveDate(cf.date)
-	if err != nil {
-		return err
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode != scriptModeSectioned && mode != scriptModeStructured {
+		return fmt.Errorf("invalid --mode: %s (expected %s or %s)", mode, scriptModeSectioned, scriptModeStructured)
 	}
 	// Load and merge configuration
-	fileCfg, err := cfgpkg.LoadFile(cf.config)
+	fileCfg, err := loadFileConfig(cf)
 	if err != nil {
 		return err
 	}
@@ -65,6 +130,10 @@ func cmdScript(args []string) error {
 		flagOv.Overwrite = &overwrite.v
 	}
 	cfg := cfgpkg.Merge(fileCfg, envOv, flagOv, apiKey, elevenLabsKey)
+	date, err := resolveDate(cf.date, cfg)
+	if err != nil {
+		return err
+	}
 
 	if err := cfgpkg.ValidateForScript(cfg); err != nil {
 		return err
@@ -76,26 +145,146 @@ func cmdScript(args []string) error {
 	}
 	ctx := context.Background()
 
-	slog.Info("script start", "date", date.Format("2006-01-02"), "model", cfg.TextModel)
-	slog.Info("selecting topic")
-	topicText, topicUsage, err := podcast.SelectTopicWithUsage(ctx, date, cfg, client)
+	if section = strings.TrimSpace(section); section != "" {
+		if topic.set {
+			return errors.New("--section reuses the episode's topic; drop --topic")
+		}
+		return scriptSectionOnly(ctx, cfg, date, client, section)
+	}
+
+	slog.Info("script start", "show", cfg.Show, "date", date.Format("2006-01-02"), "timezone", date.Location().String(), "model", cfg.TextModel, "mode", mode)
+	var recap []podcast.RecapEpisode
+	if cfg.RecapOn(date) && strings.TrimSpace(cfg.Topic) == "" {
+		if datePinned(ctx, cfg, date) {
+			slog.Warn("topic pinned to the recap day; generating a regular episode")
+		} else if recap, err = loadRecapEpisodes(ctx, cfg, date); err != nil {
+			return err
+		} else if len(recap) == 0 {
+			slog.Warn("no earlier episodes to recap; generating a regular episode")
+		}
+	}
+	var topicText string
+	var topicUsage ai.TokenUsage
+	if len(recap) > 0 {
+		topicText = podcast.RecapTopic
+		slog.Info("weekly recap", "episodes", len(recap))
+	} else {
+		slog.Info("selecting topic")
+		if topicText, topicUsage, err = podcast.SelectTopicWithUsage(ctx, date, cfg, client); err != nil {
+			return err
+		}
+		slog.Info("topic selected", "topic", topicText)
+	}
+	prompts, err := podcast.PromptsFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	prompts.Series = podcast.EpisodeSeries(ctx, cfg, date, topicText)
+	prompts.Recap = recap
+	system, user, err := prompts.ScriptPrompts(topicText, date)
+	if err != nil {
+		return err
+	}
+	slog.Info("prompts built", "show", prompts.Identity.ShowName)
+
+	specs, err := episodeSpecs(cfg, prompts, topicText, date, podcast.PreviousGame(ctx, cfg, date))
+	if err != nil {
+		return err
+	}
+	gameName, err := episodeGameName(date, specs)
 	if err != nil {
 		return err
 	}
-	slog.Info("topic selected", "topic", topicText)
-	system, user, err := podcast.BuildScriptPrompts(topicText)
+	checks, err := newEpisodeChecks(cfg, topicText, specs)
 	if err != nil {
 		return err
 	}
-	slog.Info("prompts built")
 
-	episode, wordCount, usage, err := generateEpisode(ctx, date, client, cfg.TextModel, system, user, topicText)
+	var (
+		episode podcast.Episode
+		rawJSON string
+		usage   ai.TokenUsage
+	)
+	if mode == scriptModeStructured {
+		episode, rawJSON, usage, err = generateStructuredEpisode(ctx, date, client, cfg.TextModel, specs, system, user, topicText)
+	} else {
+		episode, usage, err = generateEpisode(ctx, date, client, cfg.TextModel, specs, system, user, topicText)
+	}
 	if err != nil {
 		return err
 	}
 	usage = usage.Add(topicUsage)
+	wordCount, safetyHits, err := checks.run(episode)
+	if err != nil {
+		return err
+	}
 
-	builder := paths.New("")
+	adjusted, rewrites, lengthUsage, err := targetSectionLengths(ctx, cfg, date, client, specs, system, user, topicText, episode)
+	usage = usage.Add(lengthUsage)
+	if err != nil {
+		return err
+	}
+	if rewrites > 0 {
+		if wordCount, safetyHits, err = checks.run(adjusted); err != nil {
+			return err
+		}
+	}
+	episode = adjusted
+
+	// Reading-level metrics are tuned for English text; simplifySections
+	// checks each section's own language.
+	english := prompts.Locale.IsEnglish()
+	if cfg.ReadingGradeCeiling > 0 {
+		simplified, rewrites, simplifyUsage, err := simplifySections(ctx, client, cfg.TextModel, system, prompts.Identity.Audience, cfg.ReadingGradeCeiling, date, specs, episode)
+		usage = usage.Add(simplifyUsage)
+		if err != nil {
+			return err
+		}
+		if rewrites > 0 {
+			if wordCount, safetyHits, err = checks.run(simplified); err != nil {
+				return err
+			}
+		}
+		episode = simplified
+	}
+
+	// The fact check runs before the safety review so sections it rewrites
+	// are reviewed too.
+	var factCheck *factCheckReport
+	if cfg.FactCheck {
+		checked, report, checkUsage, err := runFactCheck(ctx, cfg, date, client, specs, system, user, topicText, episode)
+		usage = usage.Add(checkUsage)
+		if err != nil {
+			return err
+		}
+		if report.Regenerations > 0 {
+			if wordCount, safetyHits, err = checks.run(checked); err != nil {
+				return err
+			}
+		}
+		episode = checked
+		factCheck = &report
+	}
+
+	var review *reviewReport
+	if cfg.SafetyReview {
+		reviewed, report, reviewUsage, err := runSafetyReview(ctx, cfg, date, client, specs, system, user, topicText, episode)
+		usage = usage.Add(reviewUsage)
+		if err != nil {
+			return err
+		}
+		if report.Regenerations > 0 {
+			if wordCount, safetyHits, err = checks.run(reviewed); err != nil {
+				return err
+			}
+		}
+		episode = reviewed
+		review = &report
+	}
+
+	episode, lintIssues := lintEpisode(episode)
+
+	builder := paths.New(cfg.OutDir)
 	if err := builder.EnsureOutDir(date); err != nil {
 		return err
 	}
@@ -105,7 +294,19 @@ func cmdScript(args []string) error {
 	for _, section := range episode.Sections {
 		sectionPaths = append(sectionPaths, builder.EpisodeSectionMarkdown(date, section.SectionID))
 	}
+	rawPath := builder.EpisodeRawJSON(date)
 	pathsToCheck := append([]string{mdPath, metaPath}, sectionPaths...)
+	if rawJSON != "" {
+		pathsToCheck = append(pathsToCheck, rawPath)
+	}
+	reviewPath := builder.EpisodeReview(date)
+	if review != nil {
+		pathsToCheck = append(pathsToCheck, reviewPath)
+	}
+	factCheckPath := builder.EpisodeFactCheck(date)
+	if factCheck != nil {
+		pathsToCheck = append(pathsToCheck, factCheckPath)
+	}
 	if err := paths.CheckOverwrite(pathsToCheck, cfg.Overwrite); err != nil {
 		return err
 	}
@@ -120,28 +321,79 @@ func cmdScript(args []string) error {
 			return err
 		}
 	}
+	if rawJSON != "" {
+		if err := os.WriteFile(rawPath, []byte(strings.TrimSpace(rawJSON)+"\n"), 0o644); err != nil {
+			return err
+		}
+	}
+	if err := writeReport(reviewPath, review, review != nil); err != nil {
+		return err
+	}
+	if err := writeReport(factCheckPath, factCheck, factCheck != nil); err != nil {
+		return err
+	}
 
+	var readability []podcast.SectionReadability
+	if english {
+		readability = podcast.AnalyzeEpisodeReadability(episode)
+	}
+	for _, r := range readability {
+		slog.Info("section readability", "sectionID", r.SectionID, "grade", r.FleschKincaidGrade, "avgSentenceLength", r.AvgSentenceLength, "rareWordRatio", r.RareWordRatio)
+	}
+	sectionWords := sectionWordCounts(episode, specs)
+	spokenWords := 0
+	for _, c := range sectionWords {
+		spokenWords += c.Words
+		slog.Info("section word count", "sectionID", c.SectionID, "words", c.Words, "budget", c.Budget)
+	}
 	meta := scriptMeta{
-		Date:      date.Format("2006-01-02"),
-		Topic:     topicText,
-		Title:     episode.Title,
-		WordCount: wordCount,
-		Model:     cfg.TextModel,
+		Show:        cfg.Show,
+		Language:    prompts.Locale.Tag,
+		Date:        date.Format("2006-01-02"),
+		Timezone:    date.Location().String(),
+		Topic:       topicText,
+		Title:       episode.Title,
+		Game:        gameName,
+		WordCount:   wordCount,
+		Model:       cfg.TextModel,
+		Mode:        mode,
+		Usage:       newUsageMeta(usage),
+		SafetyHits:  safetyHits,
+		Readability: readability,
+		Lint:        lintIssues,
+
+		WordTarget:   cfg.TargetWordCount,
+		SectionWords: sectionWords,
 	}
-	metaBytes, err := json.MarshalIndent(meta, "", "  ")
-	if err != nil {
-		return err
+	var failure error
+	if review != nil && review.Blocked {
+		meta.Status = scriptStatusBlocked
+		failure = fmt.Errorf("safety review blocked episode: findings at or above %s severity (see %s)", review.Threshold, reviewPath)
+	} else if factCheck != nil && factCheck.Failed {
+		meta.Status = scriptStatusFactCheckFailed
+		failure = fmt.Errorf("fact check failed: claims below %.2f confidence (see %s)", factCheck.MinConfidence, factCheckPath)
 	}
-	if err := os.WriteFile(metaPath, metaBytes, 0o644); err != nil {
+	if err := writeScriptMeta(metaPath, meta); err != nil {
 		return err
 	}
+	if failure != nil {
+		if err := releaseEpisodeHistory(ctx, cfg, date); err != nil {
+			slog.Warn("failed to remove episode from topic history", "err", err)
+		}
+		return failure
+	}
+	if err := recordEpisodeHistory(ctx, cfg, date, meta); err != nil {
+		slog.Warn("failed to record episode in topic history", "err", err)
+	}
 
 	slog.Info(
 		"script generated",
 		"date", meta.Date,
 		"topic", meta.Topic,
 		"wordCount", meta.WordCount,
+		"spokenWords", spokenWords,
 		"model", meta.Model,
+		"mode", meta.Mode,
 		"inputTokens", usage.InputTokens,
 		"outputTokens", usage.OutputTokens,
 		"totalTokens", usage.TotalTokens,
@@ -151,96 +403,345 @@ func cmdScript(args []string) error {
 	return nil
 }
 
-func generateEpisode(ctx context.Context, date time.Time, client ai.TextClient, model, system, basePrompt, topic string) (podcast.Episode, int, ai.TokenUsage, error) {
-	sections := podcast.StandardSectionSchema(topic, date)
-	episodeSections := make([]podcast.EpisodeSection, 0, len(sections)+1)
+// writeReport writes a post-generation report as JSON, or removes a stale one
+// from a previous run when the check did not run.
+func writeReport(path string, report any, present bool) error {
+	if !present {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// generateEpisode generates every section in show order. Generated sections
+// are written one call at a time, each anchored on the previous generated or
+// static section; the brain game is generated last on its own, and static
+// sections use their rendered text.
+func generateEpisode(ctx context.Context, date time.Time, client ai.TextClient, model string, specs []podcast.SectionSpec, system, basePrompt, topic string) (podcast.Episode, ai.TokenUsage, error) {
+	texts := make(map[string]string, len(specs))
 	var usage ai.TokenUsage
 	var anchor string
 
-	for i, spec := range sections {
-		if i > 0 {
-			spec.ContinuityContext = anchor
+	for _, spec := range specs {
+		switch spec.Kind {
+		case podcast.SectionKindGame:
+			continue
+		case podcast.SectionKindStatic:
+			texts[spec.SectionID] = spec.Text
+			anchor = podcast.BuildContinuityAnchor(spec.Text, spec.SectionID)
+			continue
 		}
+		spec.ContinuityContext = anchor
 		userPrompt := podcast.BuildSectionPrompt(basePrompt, spec)
 		slog.Info("generating episode section", "sectionID", spec.SectionID)
 		callStart := time.Now()
 		text, callUsage, err := client.GenerateTextWithUsage(ctx, model, system, userPrompt)
 		if err != nil {
 			slog.Error("section call failed", "sectionID", spec.SectionID, "elapsed", time.Since(callStart).String(), "err", err)
-			return podcast.Episode{}, 0, ai.TokenUsage{}, err
+			return podcast.Episode{}, ai.TokenUsage{}, err
 		}
 		slog.Info("section received", "sectionID", spec.SectionID, "elapsed", time.Since(callStart).String())
 		usage = usage.Add(callUsage)
 		cleanText := strings.TrimSpace(text)
-		episodeSections = append(episodeSections, podcast.EpisodeSection{
-			SectionID: spec.SectionID,
-			Text:      cleanText,
-		})
+		texts[spec.SectionID] = cleanText
 		anchor = podcast.BuildContinuityAnchor(cleanText, spec.SectionID)
 	}
 
-	gameText, gameUsage, err := generateBrainGame(ctx, date, client, model, topic)
-	if err != nil {
-		return podcast.Episode{}, 0, ai.TokenUsage{}, err
-	}
-	usage = usage.Add(gameUsage)
-	inserted := false
-	ordered := make([]podcast.EpisodeSection, 0, len(episodeSections)+1)
-	for _, section := range episodeSections {
-		if section.SectionID == "outro" && !inserted {
-			ordered = append(ordered, podcast.EpisodeSection{
-				SectionID: "game",
-				Text:      gameText,
-			})
-			inserted = true
+	for _, spec := range specs {
+		if spec.Kind != podcast.SectionKindGame {
+			continue
+		}
+		gameText, gameUsage, err := generateBrainGame(ctx, date, client, model, spec, topic, "")
+		if err != nil {
+			return podcast.Episode{}, ai.TokenUsage{}, err
 		}
-		ordered = append(ordered, section)
+		usage = usage.Add(gameUsage)
+		texts[spec.SectionID] = gameText
 	}
-	if !inserted {
-		ordered = append(ordered, podcast.EpisodeSection{
-			SectionID: "game",
-			Text:      gameText,
+
+	sections := make([]podcast.EpisodeSection, 0, len(specs))
+	for _, spec := range specs {
+		sections = append(sections, podcast.EpisodeSection{
+			SectionID: spec.SectionID,
+			Text:      texts[spec.SectionID],
 		})
 	}
-
 	episode := podcast.Episode{
 		Title:    topic,
-		Sections: ordered,
+		Sections: sections,
+	}
+	return episode, usage, nil
+}
+
+// generateStructuredEpisode generates every generated and game section in one
+// schema-constrained call and returns the episode and its raw JSON. Static
+// sections are inserted from the show definition after parsing, and a game
+// round that fails validation is regenerated on its own.
+func generateStructuredEpisode(ctx context.Context, date time.Time, client ai.TextClient, model string, specs []podcast.SectionSpec, system, basePrompt, topic string) (podcast.Episode, string, ai.TokenUsage, error) {
+	var gamePrompt, gameName string
+	var modelIDs []string
+	for _, spec := range specs {
+		if spec.Kind == podcast.SectionKindStatic {
+			continue
+		}
+		modelIDs = append(modelIDs, spec.SectionID)
+		if spec.Kind != podcast.SectionKindGame {
+			continue
+		}
+		game, locale, err := sectionGame(date, spec)
+		if err != nil {
+			return podcast.Episode{}, "", ai.TokenUsage{}, err
+		}
+		gameSystem, gameUser, err := podcast.BuildGamePrompt(spec.Prompt, locale, topic, date, game)
+		if err != nil {
+			return podcast.Episode{}, "", ai.TokenUsage{}, err
+		}
+		gamePrompt = gameSystem + "\n\n" + gameUser
+		gameName = game.Name
+	}
+	userPrompt := podcast.BuildStructuredEpisodePrompt(basePrompt, specs, gamePrompt)
+
+	slog.Info("generating structured episode", "game", gameName)
+	callStart := time.Now()
+	raw, usage, err := client.GenerateJSONWithUsage(ctx, model, system, userPrompt, "episode", podcast.EpisodeSchemaForSections(modelIDs))
+	if err != nil {
+		slog.Error("structured episode call failed", "elapsed", time.Since(callStart).String(), "err", err)
+		return podcast.Episode{}, "", ai.TokenUsage{}, err
+	}
+	slog.Info("structured episode received", "elapsed", time.Since(callStart).String())
+	episode, err := podcast.ParseEpisodeJSON(raw)
+	if err != nil {
+		return podcast.Episode{}, "", ai.TokenUsage{}, fmt.Errorf("parse structured episode: %w", err)
+	}
+	if err := episode.ValidateSections(modelIDs); err != nil {
+		return podcast.Episode{}, "", ai.TokenUsage{}, fmt.Errorf("structured episode: %w", err)
+	}
+	parsed := make(map[string]podcast.EpisodeSection, len(episode.Sections))
+	for _, section := range episode.Sections {
+		section.Text = strings.TrimSpace(section.Text)
+		parsed[section.SectionID] = section
+	}
+	sections := make([]podcast.EpisodeSection, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Kind == podcast.SectionKindStatic {
+			sections = append(sections, podcast.EpisodeSection{SectionID: spec.SectionID, Text: spec.Text})
+			continue
+		}
+		section, ok := parsed[spec.SectionID]
+		if !ok {
+			continue
+		}
+		if spec.Kind == podcast.SectionKindGame {
+			game, _, err := sectionGame(date, spec)
+			if err != nil {
+				return podcast.Episode{}, "", ai.TokenUsage{}, err
+			}
+			if problems := game.ValidateRound(section.Text); len(problems) > 0 {
+				slog.Warn("structured game failed validation; regenerating", "game", game.Name, "problems", problems)
+				text, gameUsage, err := generateBrainGame(ctx, date, client, model, spec, topic, podcast.GameRevisionNotes(problems, section.Text))
+				if err != nil {
+					return podcast.Episode{}, "", ai.TokenUsage{}, err
+				}
+				usage = usage.Add(gameUsage)
+				if text != "" {
+					section.Text = text
+				}
+			}
+		}
+		sections = append(sections, section)
+	}
+	episode.Sections = sections
+	return episode, raw, usage, nil
+}
+
+// episodeChecks runs local validation and the lexical safety check on a generated episode.
+type episodeChecks struct {
+	topic      string
+	sectionIDs []string
+	safety     *podcast.SafetyChecker
+	threshold  podcast.Severity
+}
+
+func newEpisodeChecks(cfg cfgpkg.Config, topic string, specs []podcast.SectionSpec) (episodeChecks, error) {
+	terms, err := podcast.LoadSafetyTerms(cfg.SafetyTermsPath, cfg.Language, cfg.GameLanguage)
+	if err != nil {
+		return episodeChecks{}, err
+	}
+	checker, err := podcast.NewSafetyChecker(terms)
+	if err != nil {
+		return episodeChecks{}, err
+	}
+	threshold, err := podcast.ParseSeverity(cfg.SafetyTermThreshold)
+	if err != nil {
+		return episodeChecks{}, err
 	}
+	sectionIDs := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		sectionIDs = append(sectionIDs, spec.SectionID)
+	}
+	return episodeChecks{topic: topic, sectionIDs: sectionIDs, safety: checker, threshold: threshold}, nil
+}
+
+// run validates the episode, runs the lexical safety check, and returns the
+// word count and every safety hit. Hits at or above the threshold are an error.
+func (c episodeChecks) run(episode podcast.Episode) (int, []podcast.SafetyHit, error) {
 	slog.Info("validating episode fields")
-	if err := episode.Validate(); err != nil {
-		return podcast.Episode{}, 0, ai.TokenUsage{}, err
+	if err := episode.ValidateSections(c.sectionIDs); err != nil {
+		return 0, nil, err
 	}
 	slog.Info("rendering markdown")
-	markdown := episode.RenderMarkdown()
-	wordCount := podcast.WordCount(markdown)
+	wordCount := podcast.WordCount(episode.RenderMarkdown())
 	slog.Info("running safety check", "wordCount", wordCount)
-	if err := podcast.BasicSafetyCheck(markdown); err != nil {
-		return podcast.Episode{}, 0, ai.TokenUsage{}, err
+	hits := c.safety.CheckEpisode(c.topic, episode)
+	for _, hit := range hits {
+		slog.Warn("safety term matched", "sectionID", hit.SectionID, "term", hit.Term, "match", hit.Match, "offset", hit.Offset, "severity", string(hit.Severity))
+	}
+	if blocking := podcast.BlockingHits(hits, c.threshold); len(blocking) > 0 {
+		return 0, hits, &podcast.SafetyHitsError{Hits: blocking}
+	}
+	return wordCount, hits, nil
+}
+
+// episodeGameName returns the name of the brain game played on date, or ""
+// when the show has no game section.
+func episodeGameName(date time.Time, specs []podcast.SectionSpec) (string, error) {
+	for _, spec := range specs {
+		if spec.Kind != podcast.SectionKindGame {
+			continue
+		}
+		game, err := podcast.SectionGame(date, spec.Game)
+		if err != nil {
+			return "", err
+		}
+		return game.Name, nil
 	}
-	return episode, wordCount, usage, nil
+	return "", nil
 }
 
-func generateBrainGame(ctx context.Context, date time.Time, client ai.TextClient, model, topic string) (string, ai.TokenUsage, error) {
-	games, err := podcast.LoadGameRules()
+// sectionGame returns the rules of the game a game section plays, adjusted
+// for the language it is played in, and that language.
+func sectionGame(date time.Time, spec podcast.SectionSpec) (podcast.GameRules, podcast.Locale, error) {
+	locale, err := podcast.LookupLocale(spec.Language)
 	if err != nil {
-		return "", ai.TokenUsage{}, err
+		return podcast.GameRules{}, podcast.Locale{}, err
 	}
-	game, err := podcast.ChooseGame(date, games)
+	game, err := podcast.SectionGame(date, spec.Game)
+	if err != nil {
+		return podcast.GameRules{}, podcast.Locale{}, err
+	}
+	return game.ForLocale(locale), locale, nil
+}
+
+// gameValidationRetries caps the regenerations of a brain game round that
+// fails its game's ValidateRound checks.
+const gameValidationRetries = 2
+
+// generateBrainGame generates one round of the section's game. A round that
+// fails validation is regenerated with the problems as revision notes; after
+// gameValidationRetries the round with the fewest problems is kept with a
+// warning.
+func generateBrainGame(ctx context.Context, date time.Time, client ai.TextClient, model string, spec podcast.SectionSpec, topic, revision string) (string, ai.TokenUsage, error) {
+	game, locale, err := sectionGame(date, spec)
 	if err != nil {
 		return "", ai.TokenUsage{}, err
 	}
-	system, user, err := podcast.BuildGamePrompt(topic, date, game)
+	system, user, err := podcast.BuildGamePrompt(spec.Prompt, locale, topic, date, game)
 	if err != nil {
 		return "", ai.TokenUsage{}, err
 	}
-	slog.Info("generating brain game", "game", game.Name)
+	revision = strings.TrimSpace(revision)
+	var usage ai.TokenUsage
+	var notes, best string
+	var bestProblems []string
+	for attempt := 1; ; attempt++ {
+		prompt := user
+		if instructions := strings.TrimSpace(revision + "\n\n" + notes); instructions != "" {
+			prompt += "\n\nRevision instructions:\n" + instructions
+		}
+		slog.Info("generating brain game", "game", game.Name, "attempt", attempt)
+		callStart := time.Now()
+		text, callUsage, err := client.GenerateTextWithUsage(ctx, model, system, prompt)
+		if err != nil {
+			slog.Error("brain game call failed", "game", game.Name, "elapsed", time.Since(callStart).String(), "err", err)
+			return "", ai.TokenUsage{}, err
+		}
+		slog.Info("brain game received", "game", game.Name, "elapsed", time.Since(callStart).String())
+		usage = usage.Add(callUsage)
+		text = strings.TrimSpace(text)
+		problems := game.ValidateRound(text)
+		if len(problems) == 0 {
+			return text, usage, nil
+		}
+		if best == "" || (text != "" && len(problems) < len(bestProblems)) {
+			best, bestProblems = text, problems
+		}
+		if attempt > gameValidationRetries {
+			slog.Warn("brain game still fails validation; keeping the closest round", "game", game.Name, "problems", bestProblems)
+			return best, usage, nil
+		}
+		slog.Warn("brain game failed validation; regenerating", "game", game.Name, "problems", problems)
+		notes = podcast.GameRevisionNotes(problems, text)
+	}
+}
+
+// regenerateSection rewrites one section of an existing episode, keeping the
+// continuity anchor from the section before it. The revision notes and the
+// current text are passed to the model so it revises rather than starts over.
+func regenerateSection(ctx context.Context, date time.Time, client ai.TextClient, model string, specs []podcast.SectionSpec, system, basePrompt, topic string, episode podcast.Episode, sectionID, revision string) (string, ai.TokenUsage, error) {
+	index := -1
+	for i, section := range episode.Sections {
+		if section.SectionID == sectionID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return "", ai.TokenUsage{}, fmt.Errorf("section %q not found in episode", sectionID)
+	}
+	var spec podcast.SectionSpec
+	found := false
+	for _, candidate := range specs {
+		if candidate.SectionID == sectionID {
+			spec = candidate
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", ai.TokenUsage{}, fmt.Errorf("no section spec for %q", sectionID)
+	}
+	current := strings.TrimSpace(episode.Sections[index].Text)
+	notes := strings.TrimSpace(revision)
+	if current != "" {
+		notes += "\n\nCurrent version of this section:\n" + current
+	}
+
+	switch spec.Kind {
+	case podcast.SectionKindGame:
+		return generateBrainGame(ctx, date, client, model, spec, topic, notes)
+	case podcast.SectionKindStatic:
+		slog.Warn("static section cannot be regenerated; keeping show text", "sectionID", sectionID)
+		return spec.Text, ai.TokenUsage{}, nil
+	}
+	if index > 0 {
+		prev := episode.Sections[index-1]
+		spec.ContinuityContext = podcast.BuildContinuityAnchor(prev.Text, prev.SectionID)
+	}
+	spec.RevisionInstructions = notes
+	userPrompt := podcast.BuildSectionPrompt(basePrompt, spec)
+	slog.Info("regenerating episode section", "sectionID", sectionID)
 	callStart := time.Now()
-	text, usage, err := client.GenerateTextWithUsage(ctx, model, system, user)
+	text, usage, err := client.GenerateTextWithUsage(ctx, model, system, userPrompt)
 	if err != nil {
-		slog.Error("brain game call failed", "game", game.Name, "elapsed", time.Since(callStart).String(), "err", err)
+		slog.Error("section regeneration failed", "sectionID", sectionID, "elapsed", time.Since(callStart).String(), "err", err)
 		return "", ai.TokenUsage{}, err
 	}
-	slog.Info("brain game received", "game", game.Name, "elapsed", time.Since(callStart).String())
+	slog.Info("section regenerated", "sectionID", sectionID, "elapsed", time.Since(callStart).String())
 	return strings.TrimSpace(text), usage, nil
 }
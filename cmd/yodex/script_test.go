@@ -15,11 +15,18 @@ import (
 )
 
 type fakeTextClient struct {
-	responses []string
-	calls     int
+	responses     []string
+	calls         int
+	jsonResponses []string
+	jsonCalls     int
+	jsonSchema    map[string]any
+	systems       []string
+	prompts       []string
 }
 
 func (f *fakeTextClient) GenerateText(ctx context.Context, model, system, prompt string) (string, error) {
+	f.systems = append(f.systems, system)
+	f.prompts = append(f.prompts, prompt)
 	if f.calls >= len(f.responses) {
 		f.calls++
 		return "", nil
@@ -34,6 +41,17 @@ func (f *fakeTextClient) GenerateTextWithUsage(ctx context.Context, model, syste
 	return text, ai.TokenUsage{}, err
 }
 
+func (f *fakeTextClient) GenerateJSONWithUsage(ctx context.Context, model, system, prompt, schemaName string, schema map[string]any) (string, ai.TokenUsage, error) {
+	f.jsonSchema = schema
+	if f.jsonCalls >= len(f.jsonResponses) {
+		f.jsonCalls++
+		return "", ai.TokenUsage{}, nil
+	}
+	resp := f.jsonResponses[f.jsonCalls]
+	f.jsonCalls++
+	return resp, ai.TokenUsage{InputTokens: 100, OutputTokens: 50, TotalTokens: 150}, nil
+}
+
 func TestScriptWritesOutputs(t *testing.T) {
 	origClient := newTextClient
 	t.Cleanup(func() { newTextClient = origClient })
@@ -137,6 +155,139 @@ func TestScriptAcceptsShorterScripts(t *testing.T) {
 	}
 }
 
+func TestScriptStructuredMode(t *testing.T) {
+	origClient := newTextClient
+	t.Cleanup(func() { newTextClient = origClient })
+
+	raw, err := json.Marshal(podcast.Episode{
+		Title: "Structured Title",
+		Sections: []podcast.EpisodeSection{
+			{SectionID: "intro", Text: "Intro text."},
+			{SectionID: "topic", Text: "Topic text.", KeyFacts: []string{"Bees dance."}, Vocabulary: []string{"pollen"}},
+			{SectionID: "game", Text: testGameRound, Questions: []string{"Fact or fib?"}},
+			{SectionID: "outro", Text: "Recap text. What did you learn?"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal episode: %v", err)
+	}
+	fake := &fakeTextClient{jsonResponses: []string{string(raw)}}
+	newTextClient = func(apiKey string) (ai.TextClient, error) {
+		return fake, nil
+	}
+
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	repoRoot := filepath.Dir(filepath.Dir(origWD))
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	t.Setenv("YODEX_GAME_RULES_DIR", filepath.Join(repoRoot, "internal", "podcast", "games"))
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Bees", "--mode=structured"}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if fake.calls != 0 || fake.jsonCalls != 1 {
+		t.Fatalf("expected 1 JSON call and no text calls, got %d text and %d json", fake.calls, fake.jsonCalls)
+	}
+	if fake.jsonSchema == nil {
+		t.Fatalf("expected episode schema to be sent")
+	}
+
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	if _, err := os.Stat(builder.EpisodeRawJSON(date)); err != nil {
+		t.Fatalf("missing episode.raw.json: %v", err)
+	}
+	game, err := os.ReadFile(builder.EpisodeSectionMarkdown(date, "game"))
+	if err != nil {
+		t.Fatalf("read game.md: %v", err)
+	}
+	if strings.TrimSpace(string(game)) != testGameRound {
+		t.Fatalf("unexpected game.md: %q", game)
+	}
+	metaBytes, err := os.ReadFile(builder.EpisodeMeta(date))
+	if err != nil {
+		t.Fatalf("read meta.json: %v", err)
+	}
+	var meta scriptMeta
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		t.Fatalf("parse meta.json: %v", err)
+	}
+	if meta.Mode != "structured" {
+		t.Fatalf("meta mode mismatch: %s", meta.Mode)
+	}
+	if meta.Usage.TotalTokens != 150 {
+		t.Fatalf("meta usage not recorded: %+v", meta.Usage)
+	}
+}
+
+func TestScriptStructuredModeRejectsDuplicateSections(t *testing.T) {
+	raw, err := json.Marshal(podcast.Episode{
+		Title: "Structured Title",
+		Sections: []podcast.EpisodeSection{
+			{SectionID: "intro", Text: "Intro text."},
+			{SectionID: "topic", Text: "Topic text."},
+			{SectionID: "topic", Text: "A second topic."},
+			{SectionID: "game", Text: testGameRound},
+			{SectionID: "outro", Text: "Recap text. What did you learn?"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal episode: %v", err)
+	}
+	fake := &fakeTextClient{jsonResponses: []string{string(raw)}}
+	cfgPath := setupScriptConfigTest(t, `{}`, fake)
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Bees", "--mode=structured", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected a duplicate section to fail the run")
+	}
+	if _, err := os.Stat(paths.New("").EpisodeMarkdown(time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC))); !os.IsNotExist(err) {
+		t.Fatalf("expected no episode.md, got %v", err)
+	}
+}
+
+func TestScriptRejectsUnknownMode(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Bees", "--mode=bogus"}); code == 0 {
+		t.Fatalf("expected non-zero for unknown mode")
+	}
+}
+
+// setupScriptConfigTest installs the fake text client, moves into a temp dir,
+// and writes cfgJSON as the config file, returning its path.
+func setupScriptConfigTest(t *testing.T, cfgJSON string, fake *fakeTextClient) string {
+	t.Helper()
+	origClient := newTextClient
+	t.Cleanup(func() { newTextClient = origClient })
+	newTextClient = func(apiKey string) (ai.TextClient, error) {
+		return fake, nil
+	}
+
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	repoRoot := filepath.Dir(filepath.Dir(origWD))
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	cfgPath := filepath.Join(tmp, "config.json")
+	if err := os.WriteFile(cfgPath, []byte(cfgJSON), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	t.Setenv("YODEX_GAME_RULES_DIR", filepath.Join(repoRoot, "internal", "podcast", "games"))
+	return cfgPath
+}
+
 func makeSectionResponses(targetWords int) []string {
 	ep := podcast.Episode{
 		Title: "Test Title",
@@ -152,7 +303,7 @@ func makeSectionResponses(targetWords int) []string {
 	if remaining < 1 {
 		remaining = 1
 	}
-	ep.Sections[2].Text = makeWordyText(remaining)
+	ep.Sections[2].Text = testGameRound + " " + makeWordyText(max(remaining-podcast.WordCount(testGameRound), 1))
 	return []string{
 		ep.Sections[0].Text,
 		ep.Sections[1].Text,
@@ -161,6 +312,12 @@ func makeSectionResponses(targetWords int) []string {
 	}
 }
 
+// testGameRound is a Build-It Brainstorm round, the game of the tests'
+// usual date, that passes the game's validation.
+const testGameRound = "Today's challenge: invent a hat that keeps rain away. " +
+	"First question: what would your hat be made of? [long pause] What a great idea! " +
+	"Second question: what button would it have? [long pause] I love that."
+
 func makeWordyText(targetWords int) string {
 	var b strings.Builder
 	for i := 0; i < targetWords; i++ {
@@ -171,3 +328,64 @@ func makeWordyText(targetWords int) string {
 	}
 	return b.String()
 }
+
+func TestScriptUsesConfiguredTimezoneForToday(t *testing.T) {
+	fake := &fakeTextClient{responses: makeSectionResponses(100)}
+	cfgPath := setupScriptConfigTest(t, `{"timezone": "America/Los_Angeles"}`, fake)
+	origNow := timeNow
+	t.Cleanup(func() { timeNow = origNow })
+	// 6pm Pacific on Thursday, January 1 is already Friday in UTC.
+	timeNow = func() time.Time { return time.Date(2026, 1, 2, 2, 0, 0, 0, time.UTC) }
+
+	if code := run([]string{"script", "--topic=Owls", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	metaBytes, err := os.ReadFile(paths.New("").EpisodeMeta(time.Date(2026, 1, 1, 0, 0, 0, 0, loc)))
+	if err != nil {
+		t.Fatalf("expected the episode under the local date: %v", err)
+	}
+	var meta scriptMeta
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		t.Fatalf("parse meta.json: %v", err)
+	}
+	if meta.Date != "2026-01-01" || meta.Timezone != "America/Los_Angeles" {
+		t.Fatalf("unexpected meta date: %s %s", meta.Date, meta.Timezone)
+	}
+	if !strings.Contains(fake.prompts[0], "Thursday, January 1, 2026") {
+		t.Fatalf("expected the local date in the intro prompt: %q", fake.prompts[0])
+	}
+	if !strings.Contains(fake.prompts[3], "Weekday: Thursday") {
+		t.Fatalf("expected the local weekday's game: %q", fake.prompts[3])
+	}
+}
+
+func TestScriptRegeneratesInvalidGameRound(t *testing.T) {
+	responses := makeSectionResponses(100)
+	responses[3] = "Imagine a flying bike! What color is it? [long pause]"
+	responses = append(responses, testGameRound)
+	fake := &fakeTextClient{responses: responses}
+	cfgPath := setupScriptConfigTest(t, `{}`, fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Bikes", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if fake.calls != 5 {
+		t.Fatalf("expected 4 section calls plus 1 game regeneration, got %d", fake.calls)
+	}
+	for _, want := range []string{"Revision instructions:", `Say "Today's challenge:" word for word.`, "The round has 0 items.", "Imagine a flying bike!"} {
+		if !strings.Contains(fake.prompts[4], want) {
+			t.Fatalf("expected %q in the regeneration prompt, got %q", want, fake.prompts[4])
+		}
+	}
+	game, err := os.ReadFile(paths.New("").EpisodeSectionMarkdown(time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC), "game"))
+	if err != nil {
+		t.Fatalf("read game.md: %v", err)
+	}
+	if strings.TrimSpace(string(game)) != testGameRound {
+		t.Fatalf("expected the regenerated round, got %q", game)
+	}
+}
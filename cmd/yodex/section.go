@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"yodex/internal/ai"
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/paths"
+	"yodex/internal/podcast"
+)
+
+// scriptSectionOnly is yodex script --section: it rewrites one section of the
+// date's existing episode using the topic and game recorded in its meta.json,
+// anchored on the section files around it. The fact check and safety review
+// run again when enabled and their reports are rewritten; sections they
+// rewrite are saved along with the requested one. episode.md and meta.json
+// are rewritten, the stale episode.raw.json is removed, the topic history is
+// left alone, and the changed sections' audio is listed in meta.json as stale
+// for yodex audio.
+func scriptSectionOnly(ctx context.Context, cfg cfgpkg.Config, date time.Time, client ai.TextClient, sectionID string) error {
+	builder := paths.New(cfg.OutDir)
+	metaPath := builder.EpisodeMeta(date)
+	meta, err := readScriptMeta(metaPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no episode for %s to regenerate a section of (run yodex script first)", date.Format("2006-01-02"))
+	}
+	if err != nil {
+		return err
+	}
+	topic := strings.TrimSpace(meta.Topic)
+	if topic == "" {
+		return fmt.Errorf("%s has no topic", metaPath)
+	}
+
+	var recap []podcast.RecapEpisode
+	if topic == podcast.RecapTopic {
+		if recap, err = loadRecapEpisodes(ctx, cfg, date); err != nil {
+			return err
+		}
+	}
+	prompts, err := podcast.PromptsFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	prompts.Series = podcast.EpisodeSeries(ctx, cfg, date, topic)
+	prompts.Recap = recap
+	system, user, err := prompts.ScriptPrompts(topic, date)
+	if err != nil {
+		return err
+	}
+	specs, err := episodeSpecs(cfg, prompts, topic, date, podcast.PreviousGame(ctx, cfg, date))
+	if err != nil {
+		return err
+	}
+	for i := range specs {
+		if specs[i].Kind == podcast.SectionKindGame && meta.Game != "" {
+			specs[i].Game = meta.Game
+		}
+	}
+
+	episode := podcast.Episode{Title: meta.Title}
+	index := -1
+	var ids []string
+	for i, spec := range specs {
+		ids = append(ids, spec.SectionID)
+		var text string
+		if spec.SectionID == sectionID {
+			index = i
+		} else {
+			data, err := os.ReadFile(builder.EpisodeSectionMarkdown(date, spec.SectionID))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			text = strings.TrimSpace(string(data))
+		}
+		episode.Sections = append(episode.Sections, podcast.EpisodeSection{SectionID: spec.SectionID, Text: text})
+	}
+	if index < 0 {
+		return fmt.Errorf("unknown section %q (expected one of %s)", sectionID, strings.Join(ids, ", "))
+	}
+	spec := specs[index]
+	if spec.Kind == podcast.SectionKindStatic {
+		return fmt.Errorf("section %q is static; change its text in the show definition", sectionID)
+	}
+
+	notes := "Write a fresh version of this section for the existing episode."
+	if spec.Kind == podcast.SectionKindGenerated && index+1 < len(episode.Sections) {
+		next := episode.Sections[index+1]
+		if anchor := podcast.BuildLeadInAnchor(next.Text, next.SectionID); anchor != "" {
+			notes += "\n" + anchor
+		}
+	}
+	slog.Info("regenerating one section", "date", date.Format("2006-01-02"), "sectionID", sectionID, "topic", topic)
+	text, usage, err := regenerateSection(ctx, date, client, cfg.TextModel, specs, system, user, topic, episode, sectionID, notes)
+	if err != nil {
+		return err
+	}
+	original := make(map[string]string, len(episode.Sections))
+	for _, section := range episode.Sections {
+		original[section.SectionID] = section.Text
+	}
+	episode = replaceSectionText(episode, sectionID, text)
+
+	var factCheck *factCheckReport
+	if cfg.FactCheck {
+		checked, report, checkUsage, err := runFactCheck(ctx, cfg, date, client, specs, system, user, topic, episode)
+		usage = usage.Add(checkUsage)
+		if err != nil {
+			return err
+		}
+		episode = checked
+		factCheck = &report
+	}
+	var review *reviewReport
+	if cfg.SafetyReview {
+		reviewed, report, reviewUsage, err := runSafetyReview(ctx, cfg, date, client, specs, system, user, topic, episode)
+		usage = usage.Add(reviewUsage)
+		if err != nil {
+			return err
+		}
+		episode = reviewed
+		review = &report
+	}
+
+	// The fact check and safety review may rewrite other sections too; every
+	// changed section is linted, written, and has its audio marked stale.
+	var changed []string
+	var lintIssues []podcast.LintIssue
+	for i, section := range episode.Sections {
+		if section.SectionID != sectionID && section.Text == original[section.SectionID] {
+			continue
+		}
+		changed = append(changed, section.SectionID)
+		fixed, issues := lintEpisode(podcast.Episode{Sections: []podcast.EpisodeSection{section}})
+		episode.Sections[i].Text = fixed.Sections[0].Text
+		lintIssues = append(lintIssues, issues...)
+	}
+
+	checks, err := newEpisodeChecks(cfg, topic, specs)
+	if err != nil {
+		return err
+	}
+	wordCount, safetyHits, err := checks.run(episode)
+	if err != nil {
+		return err
+	}
+
+	for _, section := range episode.Sections {
+		if !slices.Contains(changed, section.SectionID) {
+			continue
+		}
+		if err := os.WriteFile(builder.EpisodeSectionMarkdown(date, section.SectionID), []byte(section.Text+"\n"), 0o644); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(builder.EpisodeMarkdown(date), []byte(episode.RenderMarkdown()), 0o644); err != nil {
+		return err
+	}
+	// The raw structured output no longer matches the episode.
+	if err := writeReport(builder.EpisodeRawJSON(date), nil, false); err != nil {
+		return err
+	}
+	reviewPath := builder.EpisodeReview(date)
+	if err := writeReport(reviewPath, review, review != nil); err != nil {
+		return err
+	}
+	factCheckPath := builder.EpisodeFactCheck(date)
+	if err := writeReport(factCheckPath, factCheck, factCheck != nil); err != nil {
+		return err
+	}
+
+	meta.WordCount = wordCount
+	meta.SafetyHits = safetyHits
+	meta.SectionWords = sectionWordCounts(episode, specs)
+	if prompts.Locale.IsEnglish() {
+		meta.Readability = podcast.AnalyzeEpisodeReadability(episode)
+	}
+	meta.Lint = slices.DeleteFunc(meta.Lint, func(issue podcast.LintIssue) bool { return slices.Contains(changed, issue.SectionID) })
+	meta.Lint = append(meta.Lint, lintIssues...)
+	meta.Usage = newUsageMeta(meta.Usage.tokenUsage().Add(usage))
+	for _, id := range changed {
+		if _, err := os.Stat(builder.EpisodeSectionMP3(date, id)); err == nil && !slices.Contains(meta.StaleAudio, id) {
+			meta.StaleAudio = append(meta.StaleAudio, id)
+		}
+	}
+	var failure error
+	meta.Status = ""
+	if review != nil && review.Blocked {
+		meta.Status = scriptStatusBlocked
+		failure = fmt.Errorf("safety review blocked episode: findings at or above %s severity (see %s)", review.Threshold, reviewPath)
+	} else if factCheck != nil && factCheck.Failed {
+		meta.Status = scriptStatusFactCheckFailed
+		failure = fmt.Errorf("fact check failed: claims below %.2f confidence (see %s)", factCheck.MinConfidence, factCheckPath)
+	}
+	if err := writeScriptMeta(metaPath, meta); err != nil {
+		return err
+	}
+	if failure != nil {
+		return failure
+	}
+	slog.Info(
+		"section replaced",
+		"date", meta.Date,
+		"sectionID", sectionID,
+		"changed", changed,
+		"wordCount", meta.WordCount,
+		"staleAudio", meta.StaleAudio,
+		"totalTokens", usage.TotalTokens,
+	)
+	return nil
+}
+
+// readScriptMeta reads an episode's meta.json.
+func readScriptMeta(path string) (scriptMeta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return scriptMeta{}, err
+	}
+	var meta scriptMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return scriptMeta{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return meta, nil
+}
+
+// episodeShow returns the show an existing episode was written with: the
+// recap show when its meta.json is a weekly recap, the configured show
+// otherwise.
+func episodeShow(cfg cfgpkg.Config, metaPath string) (podcast.ShowDefinition, error) {
+	if meta, err := readScriptMeta(metaPath); err == nil && meta.Topic == podcast.RecapTopic {
+		return podcast.RecapShow(), nil
+	}
+	return podcast.LoadShowDefinition(cfg.ShowPath)
+}
+
+func writeScriptMeta(path string, meta scriptMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/ai"
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/paths"
+)
+
+func TestScriptRegeneratesOneSection(t *testing.T) {
+	fake := &fakeTextClient{responses: makeSectionResponses(100)}
+	cfgPath := setupScriptConfigTest(t, `{}`, fake)
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Bees", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	history, err := os.ReadFile("out/topic-history.json")
+	if err != nil {
+		t.Fatalf("read topic history: %v", err)
+	}
+	if err := os.WriteFile(builder.EpisodeSectionMP3(date, "game"), []byte("mp3bytes"), 0o644); err != nil {
+		t.Fatalf("write game.mp3: %v", err)
+	}
+
+	newRound := strings.Replace(testGameRound, "a hat that keeps rain away", "a bee-sized umbrella", 1)
+	*fake = fakeTextClient{responses: []string{newRound}}
+	if code := run([]string{"script", "--date=2025-09-30", "--section=game", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script --section returned non-zero: %d", code)
+	}
+	if fake.calls != 1 {
+		t.Fatalf("expected only the game call and no topic selection, got %d calls", fake.calls)
+	}
+	if !strings.Contains(fake.prompts[0], "Bees") || !strings.Contains(fake.prompts[0], "Game: Build-It Brainstorm") {
+		t.Fatalf("expected the episode's topic and game in the prompt, got %q", fake.prompts[0])
+	}
+	game, err := os.ReadFile(builder.EpisodeSectionMarkdown(date, "game"))
+	if err != nil || strings.TrimSpace(string(game)) != newRound {
+		t.Fatalf("expected the new round in game.md, got %q, %v", game, err)
+	}
+	md, err := os.ReadFile(builder.EpisodeMarkdown(date))
+	if err != nil {
+		t.Fatalf("read episode.md: %v", err)
+	}
+	if !strings.HasPrefix(string(md), "Intro text.\n\nTopic text.\n\n"+newRound) {
+		t.Fatalf("expected episode.md re-rendered with the new round, got %q", md)
+	}
+	meta, err := readScriptMeta(builder.EpisodeMeta(date))
+	if err != nil {
+		t.Fatalf("read meta.json: %v", err)
+	}
+	if meta.Topic != "Bees" || len(meta.StaleAudio) != 1 || meta.StaleAudio[0] != "game" {
+		t.Fatalf("expected the game audio marked stale, got %+v", meta)
+	}
+	after, err := os.ReadFile("out/topic-history.json")
+	if err != nil || string(after) != string(history) {
+		t.Fatalf("expected the topic history untouched, got %s", after)
+	}
+
+	*fake = fakeTextClient{responses: []string{"New topic text."}}
+	if code := run([]string{"script", "--date=2025-09-30", "--section=topic", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script --section returned non-zero: %d", code)
+	}
+	for _, want := range []string{"Intro text.", "The next section (game) begins: Today's challenge:"} {
+		if !strings.Contains(fake.prompts[0], want) {
+			t.Fatalf("expected %q anchoring the topic prompt, got %q", want, fake.prompts[0])
+		}
+	}
+	if meta, err := readScriptMeta(builder.EpisodeMeta(date)); err != nil || len(meta.StaleAudio) != 1 {
+		t.Fatalf("expected only sections with audio marked stale, got %+v, %v", meta.StaleAudio, err)
+	}
+}
+
+func TestScriptSectionRerunsSafetyReview(t *testing.T) {
+	fake := &fakeTextClient{responses: makeSectionResponses(100), jsonResponses: []string{cleanReview}}
+	cfgPath := setupReviewTest(t, "block", fake)
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Volcanoes", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	if err := os.WriteFile(builder.EpisodeRawJSON(date), []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("write episode.raw.json: %v", err)
+	}
+
+	*fake = fakeTextClient{responses: []string{"Touch the lava."}, jsonResponses: []string{flaggedTopicReview}}
+	if code := run([]string{"script", "--date=2025-09-30", "--section=topic", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected the review to block the new section")
+	}
+	if fake.jsonCalls != 1 {
+		t.Fatalf("expected the safety review to run again, got %d JSON calls", fake.jsonCalls)
+	}
+	if _, err := os.Stat(builder.EpisodeRawJSON(date)); !os.IsNotExist(err) {
+		t.Fatalf("expected the stale episode.raw.json to be removed, got %v", err)
+	}
+	review, err := os.ReadFile(builder.EpisodeReview(date))
+	if err != nil || !strings.Contains(string(review), `"blocked": true`) {
+		t.Fatalf("expected a blocked review.json, got %s, %v", review, err)
+	}
+	if meta, err := readScriptMeta(builder.EpisodeMeta(date)); err != nil || meta.Status != scriptStatusBlocked {
+		t.Fatalf("expected a blocked status in meta.json, got %+v, %v", meta, err)
+	}
+
+	*fake = fakeTextClient{responses: []string{"Lava is very hot, so scientists watch it from far away."}, jsonResponses: []string{cleanReview}}
+	if code := run([]string{"script", "--date=2025-09-30", "--section=topic", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script --section returned non-zero: %d", code)
+	}
+	if meta, err := readScriptMeta(builder.EpisodeMeta(date)); err != nil || meta.Status != "" {
+		t.Fatalf("expected the blocked status cleared, got %+v, %v", meta, err)
+	}
+}
+
+func TestScriptSectionErrors(t *testing.T) {
+	fake := &fakeTextClient{responses: makeSectionResponses(100)}
+	cfgPath := setupScriptConfigTest(t, `{}`, fake)
+	if code := run([]string{"script", "--date=2025-09-30", "--section=game", "--config", cfgPath}); code == 0 {
+		t.Fatalf("expected an error without an existing episode")
+	}
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Bees", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	for _, args := range [][]string{
+		{"--section=joke"},
+		{"--section=game", "--topic=Owls"},
+	} {
+		if code := run(append([]string{"script", "--date=2025-09-30", "--config", cfgPath}, args...)); code == 0 {
+			t.Fatalf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestAudioRegeneratesStaleSections(t *testing.T) {
+	origConcat := concatMP3
+	t.Cleanup(func() { concatMP3 = origConcat })
+	concatMP3 = concatMP3ByCopy
+	origClient := newTTSClient
+	t.Cleanup(func() { newTTSClient = origClient })
+	fake := &fakeTTSClient{}
+	newTTSClient = func(cfg cfgpkg.Config) (ai.TTSClient, error) {
+		return fake, nil
+	}
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	if err := builder.EnsureOutDir(date); err != nil {
+		t.Fatalf("EnsureOutDir: %v", err)
+	}
+	for _, id := range []string{"intro", "topic", "game", "outro"} {
+		if err := os.WriteFile(builder.EpisodeSectionMarkdown(date, id), []byte(id+" text.\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", id, err)
+		}
+		if err := os.WriteFile(builder.EpisodeSectionMP3(date, id), []byte("old-"+id), 0o644); err != nil {
+			t.Fatalf("write %s mp3: %v", id, err)
+		}
+	}
+	if err := os.WriteFile(builder.EpisodeMP3(date), []byte("old"), 0o644); err != nil {
+		t.Fatalf("write episode.mp3: %v", err)
+	}
+	if err := writeScriptMeta(builder.EpisodeMeta(date), scriptMeta{Date: "2025-09-30", Topic: "Bees", StaleAudio: []string{"game"}}); err != nil {
+		t.Fatalf("write meta: %v", err)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	if code := run([]string{"audio", "--date=2025-09-30"}); code != 0 {
+		t.Fatalf("audio returned non-zero: %d", code)
+	}
+	if fake.calls != 1 || fake.lastText != "game text.\n" {
+		t.Fatalf("expected only the stale game section voiced, got %d calls (%q)", fake.calls, fake.lastText)
+	}
+	episode, err := os.ReadFile(builder.EpisodeMP3(date))
+	if err != nil || string(episode) != "old-introold-topicmp3bytesold-outro" {
+		t.Fatalf("expected episode.mp3 rebuilt from the sections, got %q, %v", episode, err)
+	}
+	meta, err := readScriptMeta(builder.EpisodeMeta(date))
+	if err != nil || len(meta.StaleAudio) != 0 || meta.Topic != "Bees" {
+		t.Fatalf("expected the stale list cleared, got %+v, %v", meta, err)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/paths"
+)
+
+func TestScriptUsesShowDefinition(t *testing.T) {
+	fake := &fakeTextClient{responses: []string{
+		"Intro text.",
+		"Topic text.",
+		"Why did the volcano blush? It saw the lava!",
+		"Recap text. What did you learn?",
+		testGameRound,
+	}}
+	tmp := t.TempDir()
+	showPath := filepath.Join(tmp, "show.json")
+	show := `{"sections": [
+		{"id": "intro"},
+		{"id": "topic"},
+		{"id": "joke", "prompt": "Tell one joke about {{.Topic}}."},
+		{"id": "game"},
+		{"id": "sponsor", "kind": "static", "text": "This show is brought to you by curiosity."},
+		{"id": "outro"}
+	]}`
+	if err := os.WriteFile(showPath, []byte(show), 0o644); err != nil {
+		t.Fatalf("write show: %v", err)
+	}
+	cfgPath := setupScriptConfigTest(t, `{"showPath": "`+showPath+`"}`, fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Volcanoes", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if fake.calls != 5 {
+		t.Fatalf("expected 4 generated sections plus the game, got %d calls", fake.calls)
+	}
+
+	date := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	builder := paths.New("")
+	md, err := os.ReadFile(builder.EpisodeMarkdown(date))
+	if err != nil {
+		t.Fatalf("read episode.md: %v", err)
+	}
+	order := []string{"Intro text.", "Topic text.", "Why did the volcano blush?", "Today's challenge:", "brought to you by curiosity", "Recap text."}
+	last := -1
+	for _, part := range order {
+		i := strings.Index(string(md), part)
+		if i <= last {
+			t.Fatalf("expected %q after previous section in %q", part, md)
+		}
+		last = i
+	}
+	for _, id := range []string{"joke", "sponsor"} {
+		if _, err := os.Stat(builder.EpisodeSectionMarkdown(date, id)); err != nil {
+			t.Fatalf("expected %s.md: %v", id, err)
+		}
+	}
+}
+
+func TestScriptPlaysSpanishGameWithoutEnglishChecks(t *testing.T) {
+	responses := makeSectionResponses(100)
+	responses[3] = "El reto de hoy: inventa un sombrero para la lluvia. ¿De qué estaría hecho? [long pause] ¡Qué gran idea!"
+	fake := &fakeTextClient{responses: responses}
+	cfgPath := setupScriptConfigTest(t, `{"gameLanguage": "es"}`, fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Lluvia", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if fake.calls != 4 {
+		t.Fatalf("expected the Spanish round to be kept without regenerating, got %d calls", fake.calls)
+	}
+	if strings.Contains(fake.prompts[3], "word for word") {
+		t.Fatalf("expected no English phrases demanded of a Spanish game: %q", fake.prompts[3])
+	}
+}
+
+func TestScriptUsesConfiguredIdentity(t *testing.T) {
+	fake := &fakeTextClient{responses: makeSectionResponses(100)}
+	cfgPath := setupScriptConfigTest(t, `{
+		"showName": "Big Questions",
+		"hostName": "Marco",
+		"hostPersona": "a science journalist",
+		"audience": "curious 10- to 12-year-olds",
+		"ageRange": "10–12"
+	}`, fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Black Holes", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if len(fake.systems) != 4 {
+		t.Fatalf("expected 4 calls, got %d", len(fake.systems))
+	}
+	if !strings.HasPrefix(fake.systems[0], "You are a science journalist for curious 10- to 12-year-olds.") {
+		t.Fatalf("unexpected section system prompt: %q", fake.systems[0])
+	}
+	if !strings.Contains(fake.prompts[0], `"Big Questions" and introduce the host, Marco`) {
+		t.Fatalf("expected identity in intro prompt: %q", fake.prompts[0])
+	}
+	if !strings.Contains(fake.systems[3], "for kids ages 10–12") {
+		t.Fatalf("expected age range in game system prompt: %q", fake.systems[3])
+	}
+}
+
+func TestScriptWritesSpanishEdition(t *testing.T) {
+	fake := &fakeTextClient{responses: makeSectionResponses(100)}
+	cfgPath := setupScriptConfigTest(t, `{"language": "es-MX", "gameLanguage": "en"}`, fake)
+
+	if code := run([]string{"script", "--date=2025-09-30", "--topic=Volcanes", "--config", cfgPath}); code != 0 {
+		t.Fatalf("script returned non-zero: %d", code)
+	}
+	if len(fake.systems) != 4 {
+		t.Fatalf("expected 4 calls, got %d", len(fake.systems))
+	}
+	if !strings.Contains(fake.systems[0], "in Spanish (español)") {
+		t.Fatalf("expected Spanish instruction in section system prompt: %q", fake.systems[0])
+	}
+	if !strings.Contains(fake.prompts[0], "martes, 30 de septiembre de 2025") {
+		t.Fatalf("expected Spanish date in intro prompt: %q", fake.prompts[0])
+	}
+	if !strings.Contains(fake.systems[3], "Play this game in English") || !strings.Contains(fake.prompts[3], "It's Tuesday") {
+		t.Fatalf("expected English game in a Spanish episode: %q / %q", fake.systems[3], fake.prompts[3])
+	}
+
+	metaBytes, err := os.ReadFile(paths.New("").EpisodeMeta(time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("read meta.json: %v", err)
+	}
+	var meta scriptMeta
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		t.Fatalf("parse meta.json: %v", err)
+	}
+	if meta.Language != "es-MX" {
+		t.Fatalf("expected meta language es-MX, got %q", meta.Language)
+	}
+}
@@ -14,6 +14,9 @@ import (
 
 // yodex topic
 func cmdTopic(args []string) error {
+	if len(args) > 0 && args[0] == "queue" {
+		return cmdTopicQueue(args[1:])
+	}
 	var cf commonFlags
 
 	fs := flag.NewFlagSet("topic", flag.ContinueOnError)
@@ -27,17 +30,17 @@ func cmdTopic(args []string) error {
 		return err
 	}
 	setupLogger(cf.logLevel)
-	date, err := resolveDate(cf.date)
-	if err != nil {
-		return err
-	}
 
-	fileCfg, err := cfgpkg.LoadFile(cf.config)
+	fileCfg, err := loadFileConfig(cf)
 	if err != nil {
 		return err
 	}
 	envOv, apiKey, elevenLabsKey := cfgpkg.FromEnv()
 	cfg := cfgpkg.Merge(fileCfg, envOv, cfgpkg.Overrides{}, apiKey, elevenLabsKey)
+	date, err := resolveDate(cf.date, cfg)
+	if err != nil {
+		return err
+	}
 
 	var client ai.TextClient
 	if cfg.Topic == "" {
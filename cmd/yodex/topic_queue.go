@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	cfgpkg "yodex/internal/config"
+	"yodex/internal/podcast"
+)
+
+var openTopicBacklog = podcast.OpenTopicBacklog
+
+// topicQueueOut is where the backlog listing is written; replaced in tests.
+var topicQueueOut io.Writer = os.Stdout
+
+const topicQueueUsage = `Usage:
+  yodex topic queue add [flags] TOPIC...
+  yodex topic queue add [flags] --date DATE TOPIC
+  yodex topic queue list [flags]
+  yodex topic queue rm [flags] SLOT...
+
+Topics pinned to a date are used on that date; queued topics are used in
+order on dates without a pin, before any topic is generated. SLOT is a pinned
+DATE or a queue position as shown by list. Works on topic-backlog.json in S3
+when a bucket is configured, otherwise on the local topicBacklogPath file.
+`
+
+// yodex topic queue
+func cmdTopicQueue(args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(os.Stderr, topicQueueUsage)
+		return nil
+	}
+	action := args[0]
+	switch action {
+	case "add", "list", "rm":
+	default:
+		fmt.Fprint(os.Stderr, topicQueueUsage)
+		return fmt.Errorf("unknown topic queue command: %s", action)
+	}
+
+	var cf commonFlags
+	var date string
+	fs := flag.NewFlagSet("topic queue "+action, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	addConfigFlags(fs, &cf)
+	if action == "add" {
+		fs.StringVar(&date, "date", "", "Pin the topic to this YYYY-MM-DD episode date instead of queueing it")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	setupLogger(cf.logLevel)
+
+	fileCfg, err := loadFileConfig(cf)
+	if err != nil {
+		return err
+	}
+	envOv, apiKey, elevenLabsKey := cfgpkg.FromEnv()
+	cfg := cfgpkg.Merge(fileCfg, envOv, cfgpkg.Overrides{}, apiKey, elevenLabsKey)
+	if cfg.S3Bucket == "" && strings.TrimSpace(cfg.TopicBacklogPath) == "" {
+		return errors.New("no topic backlog configured: set AWS_S3_BUCKET or topicBacklogPath")
+	}
+
+	ctx := context.Background()
+	store, err := openTopicBacklog(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	rest := fs.Args()
+	switch action {
+	case "add":
+		var topics []string
+		for _, arg := range rest {
+			if topic := strings.TrimSpace(arg); topic != "" {
+				topics = append(topics, topic)
+			}
+		}
+		if len(topics) == 0 {
+			return errors.New("topic queue add takes at least one TOPIC")
+		}
+		if date != "" {
+			key, err := historyDateKey(date)
+			if err != nil {
+				return err
+			}
+			if len(topics) != 1 {
+				return errors.New("topic queue add --date takes one TOPIC")
+			}
+			if day, _ := time.Parse("2006-01-02", key); cfg.RecapOn(day) {
+				return fmt.Errorf("%s is a weekly recap day (recapDay %s); pin the topic to another date", key, cfg.RecapDay)
+			}
+			err = store.Update(ctx, func(backlog *podcast.TopicBacklog) error {
+				if existing, ok := backlog.Pins[key]; ok {
+					return fmt.Errorf("%s is already pinned to %q (rm it first)", key, existing)
+				}
+				backlog.Pins[key] = topics[0]
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			slog.Info("topic pinned", "date", key, "topic", topics[0], "location", store.Location())
+			return nil
+		}
+		err := store.Update(ctx, func(backlog *podcast.TopicBacklog) error {
+			backlog.Queue = append(backlog.Queue, topics...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("topics queued", "count", len(topics), "location", store.Location())
+		return nil
+	case "list":
+		if len(rest) != 0 {
+			return errors.New("topic queue list takes no arguments")
+		}
+		backlog, err := store.Load(ctx)
+		if err != nil {
+			return err
+		}
+		return writeTopicBacklog(topicQueueOut, backlog)
+	default: // rm
+		if len(rest) == 0 {
+			return errors.New("topic queue rm takes at least one SLOT")
+		}
+		var dates []string
+		positions := map[int]bool{}
+		for _, arg := range rest {
+			if n, err := strconv.Atoi(arg); err == nil {
+				if n < 1 {
+					return fmt.Errorf("invalid queue position %d", n)
+				}
+				positions[n] = true
+				continue
+			}
+			key, err := historyDateKey(arg)
+			if err != nil {
+				return err
+			}
+			dates = append(dates, key)
+		}
+		err := store.Update(ctx, func(backlog *podcast.TopicBacklog) error {
+			for _, key := range dates {
+				if _, ok := backlog.Pins[key]; !ok {
+					return fmt.Errorf("no topic pinned to %s", key)
+				}
+				delete(backlog.Pins, key)
+			}
+			for n := range positions {
+				if n > len(backlog.Queue) {
+					return fmt.Errorf("no queued topic at position %d", n)
+				}
+			}
+			queue := make([]string, 0, len(backlog.Queue))
+			for i, topic := range backlog.Queue {
+				if !positions[i+1] {
+					queue = append(queue, topic)
+				}
+			}
+			backlog.Queue = queue
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("backlog topics removed", "count", len(rest), "location", store.Location())
+		return nil
+	}
+}
+
+// writeTopicBacklog lists pinned topics by date, then queued topics by
+// position.
+func writeTopicBacklog(w io.Writer, backlog podcast.TopicBacklog) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SLOT\tTOPIC")
+	for _, key := range backlog.PinDates() {
+		fmt.Fprintf(tw, "%s\t%s\n", key, backlog.Pins[key])
+	}
+	for i, topic := range backlog.Queue {
+		fmt.Fprintf(tw, "%d\t%s\n", i+1, topic)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"yodex/internal/podcast"
+)
+
+func TestTopicQueueCommands(t *testing.T) {
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+	var out strings.Builder
+	orig := topicQueueOut
+	t.Cleanup(func() { topicQueueOut = orig })
+	topicQueueOut = &out
+
+	if code := run([]string{"topic", "queue", "add", "--date", "2026-07-20", "The Moon Landing"}); code != 0 {
+		t.Fatalf("queue add --date returned %d", code)
+	}
+	if code := run([]string{"topic", "queue", "add", "--date", "2026-07-20", "Rockets"}); code == 0 {
+		t.Fatalf("expected pinning a taken date to fail")
+	}
+	t.Setenv("YODEX_RECAP_DAY", "saturday")
+	if code := run([]string{"topic", "queue", "add", "--date", "2026-07-25", "Rockets"}); code == 0 {
+		t.Fatalf("expected pinning a recap day to fail")
+	}
+	if code := run([]string{"topic", "queue", "add", "Volcanoes", "Honey Bees", "Tide Pools"}); code != 0 {
+		t.Fatalf("queue add returned %d", code)
+	}
+	if code := run([]string{"topic", "queue", "rm", "2"}); code != 0 {
+		t.Fatalf("queue rm returned %d", code)
+	}
+	if code := run([]string{"topic", "queue", "rm", "9"}); code == 0 {
+		t.Fatalf("expected removing a missing position to fail")
+	}
+	if code := run([]string{"topic", "queue", "list"}); code != 0 {
+		t.Fatalf("queue list returned %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "2026-07-20") || !strings.Contains(lines[2], "Volcanoes") || !strings.Contains(lines[3], "Tide Pools") {
+		t.Fatalf("unexpected list output:\n%s", out.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join("out", "topic-backlog.json"))
+	if err != nil {
+		t.Fatalf("read backlog: %v", err)
+	}
+	if !strings.Contains(string(data), `"2026-07-20": "The Moon Landing"`) {
+		t.Fatalf("unexpected backlog file:\n%s", data)
+	}
+
+	// The pinned topic is used without calling the model.
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	if code := run([]string{"topic", "--date=2026-07-20"}); code != 0 {
+		t.Fatalf("topic returned %d", code)
+	}
+	data, err = os.ReadFile(filepath.Join("out", "topic-backlog.json"))
+	if err != nil {
+		t.Fatalf("read backlog: %v", err)
+	}
+	backlog, err := podcast.ParseTopicBacklog(data)
+	if err != nil {
+		t.Fatalf("parse backlog: %v", err)
+	}
+	if len(backlog.Pins) != 0 || len(backlog.Queue) != 2 {
+		t.Fatalf("expected only the pinned topic used, got %+v", backlog)
+	}
+}
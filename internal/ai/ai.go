@@ -9,6 +9,7 @@ import (
 type TextClient interface {
 	GenerateText(ctx context.Context, model, system, prompt string) (string, error)
 	GenerateTextWithUsage(ctx context.Context, model, system, prompt string) (string, TokenUsage, error)
+	GenerateJSONWithUsage(ctx context.Context, model, system, prompt, schemaName string, schema map[string]any) (string, TokenUsage, error)
 }
 
 // TTSClient synthesizes speech audio from text.
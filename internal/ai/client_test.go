@@ -1,6 +1,13 @@
 package ai
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
 
 func TestNewClientRequiresKey(t *testing.T) {
 	if _, err := New("", ""); err == nil {
@@ -20,3 +27,37 @@ func TestNewClientStoresFields(t *testing.T) {
 		t.Fatalf("baseURL mismatch")
 	}
 }
+
+func TestEmbedOrdersVectorsByIndex(t *testing.T) {
+	var req struct {
+		Input      []string `json:"input"`
+		Model      string   `json:"model"`
+		Dimensions int      `json:"dimensions"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"object":"list","model":"text-embedding-3-small","data":[
+			{"object":"embedding","index":1,"embedding":[0,1]},
+			{"object":"embedding","index":0,"embedding":[1,0]}
+		],"usage":{"prompt_tokens":4,"total_tokens":4}}`)
+	}))
+	defer srv.Close()
+
+	c, err := New("sk-test", srv.URL)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	vectors, err := c.Embed(context.Background(), "text-embedding-3-small", 2, []string{"bees", "volcanoes"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if req.Model != "text-embedding-3-small" || req.Dimensions != 2 || len(req.Input) != 2 {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Fatalf("expected vectors in input order, got %v", vectors)
+	}
+}
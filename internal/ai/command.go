@@ -0,0 +1,214 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	commandTTSModelPlaceholder  = "{model}"
+	commandTTSVoicePlaceholder  = "{voice}"
+	commandTTSOutputPlaceholder = "{output}"
+)
+
+// AudioConverter converts WAV audio to MP3 and writes it to w.
+type AudioConverter func(ctx context.Context, wav []byte, w io.Writer) error
+
+// CommandTTSOption configures the command TTS client.
+type CommandTTSOption func(*CommandTTSClient)
+
+// WithCommandTTSConverter sets the converter used for WAV output.
+func WithCommandTTSConverter(convert AudioConverter) CommandTTSOption {
+	return func(c *CommandTTSClient) {
+		if convert != nil {
+			c.convert = convert
+		}
+	}
+}
+
+// CommandTTSClient synthesizes speech by piping text to a local executable
+// such as Piper, espeak-ng, or Coqui.
+//
+// The command line may reference {model}, {voice}, and {output}. Text is
+// written to stdin. When {output} is present the audio is read from that file
+// after the command exits; otherwise it is read from stdout. WAV output is
+// converted to MP3 so it matches the rest of the audio pipeline.
+type CommandTTSClient struct {
+	args    []string
+	convert AudioConverter
+}
+
+// NewCommandTTS constructs a command TTS client. The command is required.
+func NewCommandTTS(command string, opts ...CommandTTSOption) (*CommandTTSClient, error) {
+	args, err := splitCommandLine(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errors.New("tts command is required")
+	}
+	client := &CommandTTSClient{
+		args:    args,
+		convert: convertWAVWithFFmpeg,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client, nil
+}
+
+// Args returns the parsed command line, before placeholder expansion.
+func (c *CommandTTSClient) Args() []string {
+	args := make([]string, len(c.args))
+	copy(args, c.args)
+	return args
+}
+
+// TTS writes MP3 audio to the provided writer using the configured command.
+func (c *CommandTTSClient) TTS(ctx context.Context, model, voice, text string, w io.Writer) error {
+	if strings.TrimSpace(text) == "" {
+		return errors.New("text is required")
+	}
+	outputPath := ""
+	if c.usesOutputFile() {
+		f, err := os.CreateTemp("", "yodex-tts-*")
+		if err != nil {
+			return err
+		}
+		outputPath = f.Name()
+		if err := f.Close(); err != nil {
+			return err
+		}
+		defer os.Remove(outputPath)
+	}
+
+	args := make([]string, len(c.args))
+	for i, arg := range c.args {
+		arg = strings.ReplaceAll(arg, commandTTSModelPlaceholder, model)
+		arg = strings.ReplaceAll(arg, commandTTSVoicePlaceholder, voice)
+		arg = strings.ReplaceAll(arg, commandTTSOutputPlaceholder, outputPath)
+		args[i] = arg
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("tts command %s: %w: %s", args[0], err, msg)
+		}
+		return fmt.Errorf("tts command %s: %w", args[0], err)
+	}
+
+	audio := stdout.Bytes()
+	if outputPath != "" {
+		data, err := os.ReadFile(outputPath)
+		if err != nil {
+			return fmt.Errorf("read tts command output: %w", err)
+		}
+		audio = data
+	}
+
+	switch {
+	case isWAV(audio):
+		return c.convert(ctx, audio, w)
+	case isMP3(audio):
+		_, err := w.Write(audio)
+		return err
+	case len(audio) == 0:
+		return fmt.Errorf("tts command %s produced no audio", args[0])
+	default:
+		return fmt.Errorf("tts command %s produced unrecognized audio (expected WAV or MP3)", args[0])
+	}
+}
+
+func (c *CommandTTSClient) usesOutputFile() bool {
+	for _, arg := range c.args {
+		if strings.Contains(arg, commandTTSOutputPlaceholder) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWAV(b []byte) bool {
+	return len(b) >= 12 && string(b[0:4]) == "RIFF" && string(b[8:12]) == "WAVE"
+}
+
+func isMP3(b []byte) bool {
+	if len(b) >= 3 && string(b[0:3]) == "ID3" {
+		return true
+	}
+	return len(b) >= 2 && b[0] == 0xFF && b[1]&0xE0 == 0xE0
+}
+
+func convertWAVWithFFmpeg(ctx context.Context, wav []byte, w io.Writer) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-loglevel", "error", "-f", "wav", "-i", "pipe:0", "-c:a", "libmp3lame", "-f", "mp3", "pipe:1")
+	cmd.Stdin = bytes.NewReader(wav)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("convert wav to mp3: %w: %s", err, msg)
+		}
+		return fmt.Errorf("convert wav to mp3: %w", err)
+	}
+	return nil
+}
+
+// splitCommandLine splits a command string into arguments, honoring single
+// and double quotes and backslash escapes outside single quotes.
+func splitCommandLine(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("tts command has an unterminated quote")
+	}
+	if escaped {
+		return nil, errors.New("tts command ends with a dangling escape")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
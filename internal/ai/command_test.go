@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tts.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	return path
+}
+
+func TestNewCommandTTSRequiresCommand(t *testing.T) {
+	if _, err := NewCommandTTS("  "); err == nil {
+		t.Fatalf("expected error when command missing")
+	}
+	if _, err := NewCommandTTS(`piper --model "unterminated`); err == nil {
+		t.Fatalf("expected error for unterminated quote")
+	}
+}
+
+func TestNewCommandTTSSplitsQuotedArgs(t *testing.T) {
+	c, err := NewCommandTTS(`piper --model "/models/en US.onnx" --output_file {output}`)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := []string{"piper", "--model", "/models/en US.onnx", "--output_file", "{output}"}
+	got := c.Args()
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("args mismatch: got %q want %q", got, want)
+	}
+}
+
+func TestCommandTTSPassesThroughMP3FromStdout(t *testing.T) {
+	script := writeScript(t, `cat > /dev/null; printf 'ID3%s-%s' "$1" "$2"`)
+	c, err := NewCommandTTS(script + " {model} {voice}")
+	if err != nil {
+		t.Fatalf("NewCommandTTS: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := c.TTS(context.Background(), "m", "v", "Hello there.", &buf); err != nil {
+		t.Fatalf("TTS: %v", err)
+	}
+	if buf.String() != "ID3m-v" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestCommandTTSConvertsWAVFromOutputFile(t *testing.T) {
+	script := writeScript(t, `cat > "$1.txt"; printf 'RIFF0000WAVEdata' > "$1"`)
+	var gotWAV []byte
+	convert := func(ctx context.Context, wav []byte, w io.Writer) error {
+		gotWAV = wav
+		_, err := w.Write([]byte("mp3bytes"))
+		return err
+	}
+	c, err := NewCommandTTS(script+" {output}", WithCommandTTSConverter(convert))
+	if err != nil {
+		t.Fatalf("NewCommandTTS: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := c.TTS(context.Background(), "", "", "Hello there.", &buf); err != nil {
+		t.Fatalf("TTS: %v", err)
+	}
+	if string(gotWAV) != "RIFF0000WAVEdata" {
+		t.Fatalf("converter got %q", gotWAV)
+	}
+	if buf.String() != "mp3bytes" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestCommandTTSReportsFailures(t *testing.T) {
+	failing := writeScript(t, `echo "model not found" >&2; exit 3`)
+	c, err := NewCommandTTS(failing)
+	if err != nil {
+		t.Fatalf("NewCommandTTS: %v", err)
+	}
+	err = c.TTS(context.Background(), "", "", "Hello.", io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "model not found") {
+		t.Fatalf("expected stderr in error, got %v", err)
+	}
+
+	garbage := writeScript(t, `cat > /dev/null; printf 'not audio'`)
+	c, err = NewCommandTTS(garbage)
+	if err != nil {
+		t.Fatalf("NewCommandTTS: %v", err)
+	}
+	if err := c.TTS(context.Background(), "", "", "Hello.", io.Discard); err == nil {
+		t.Fatalf("expected error for unrecognized audio")
+	}
+}
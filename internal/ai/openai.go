@@ -2,9 +2,12 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"sort"
 
 	openai "github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
@@ -107,3 +110,71 @@ func (c *Client) TTS(ctx context.Context, model, voice, text string, w io.Writer
 	_, err = io.Copy(w, resp.Body)
 	return err
 }
+
+// Embed returns one embedding vector per input, in input order. dimensions
+// shortens the vectors when the model supports it; zero keeps the default.
+func (c *Client) Embed(ctx context.Context, model string, dimensions int, inputs []string) ([][]float64, error) {
+	req := openai.EmbeddingNewParams{
+		Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: inputs},
+		Model: openai.EmbeddingModel(model),
+	}
+	if dimensions > 0 {
+		req.Dimensions = param.NewOpt(int64(dimensions))
+	}
+	res, err := c.sdk.Embeddings.New(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Data) != len(inputs) {
+		return nil, fmt.Errorf("embeddings returned %d vectors for %d inputs", len(res.Data), len(inputs))
+	}
+	vectors := make([][]float64, len(inputs))
+	for _, d := range res.Data {
+		if d.Index < 0 || int(d.Index) >= len(inputs) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
+
+// ModerationResult is the moderation verdict for a single input.
+type ModerationResult struct {
+	Flagged    bool
+	Categories []string
+}
+
+// Moderate classifies each input with the Moderations API and returns one result per input.
+func (c *Client) Moderate(ctx context.Context, inputs []string) ([]ModerationResult, error) {
+	req := openai.ModerationNewParams{
+		Input: openai.ModerationNewParamsInputUnion{OfStringArray: inputs},
+		Model: openai.ModerationModelOmniModerationLatest,
+	}
+	res, err := c.sdk.Moderations.New(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]ModerationResult, 0, len(res.Results))
+	for _, r := range res.Results {
+		results = append(results, ModerationResult{
+			Flagged:    r.Flagged,
+			Categories: flaggedCategories(r.Categories.RawJSON()),
+		})
+	}
+	return results, nil
+}
+
+func flaggedCategories(raw string) []string {
+	var categories map[string]bool
+	if err := json.Unmarshal([]byte(raw), &categories); err != nil {
+		return nil
+	}
+	var flagged []string
+	for name, on := range categories {
+		if on {
+			flagged = append(flagged, name)
+		}
+	}
+	sort.Strings(flagged)
+	return flagged
+}
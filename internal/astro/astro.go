@@ -0,0 +1,73 @@
+// Package astro computes sky events locally from published astronomical
+// algorithms: moon phases and equinoxes/solstices follow Meeus, Astronomical
+// Algorithms (2nd ed.), chapters 49 and 27, and planet positions use JPL's
+// approximate Keplerian elements (valid 1800-2050). Times are accurate to a
+// few minutes for the phases and seasons and to about a day for planet events,
+// which is plenty for "tonight" mentions.
+package astro
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// EventKind says what sort of sky event an Event is.
+type EventKind string
+
+const (
+	KindNewMoon            EventKind = "new-moon"
+	KindFullMoon           EventKind = "full-moon"
+	KindEquinox            EventKind = "equinox"
+	KindSolstice           EventKind = "solstice"
+	KindMeteorShower       EventKind = "meteor-shower"
+	KindOpposition         EventKind = "opposition"
+	KindGreatestElongation EventKind = "greatest-elongation"
+)
+
+// Event is a sky event with a short, kid-friendly description.
+type Event struct {
+	Kind        EventKind
+	Name        string
+	Time        time.Time
+	Description string
+}
+
+// EventsOn returns the events that fall on the calendar day containing day,
+// in day's location, ordered by time.
+func EventsOn(day time.Time) []Event {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+	var events []Event
+	events = append(events, moonPhasesBetween(start, end)...)
+	events = append(events, seasonsBetween(start, end)...)
+	events = append(events, meteorShowersOn(start)...)
+	events = append(events, planetEventsOn(start)...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
+
+const (
+	unixEpochJD = 2440587.5
+	j2000       = 2451545.0
+	// deltaT is TT-UT in the 2020s, close enough for day-level events.
+	deltaT = 69 * time.Second
+)
+
+// julianDay returns the Julian Day of t.
+func julianDay(t time.Time) float64 {
+	return float64(t.UnixNano())/float64(24*time.Hour) + unixEpochJD
+}
+
+// fromJDE converts a Julian Ephemeris Day to UTC.
+func fromJDE(jde float64) time.Time {
+	ns := (jde - unixEpochJD) * float64(24*time.Hour)
+	return time.Unix(0, int64(ns)).UTC().Add(-deltaT)
+}
+
+func sinDeg(d float64) float64 { return math.Sin(d * math.Pi / 180) }
+func cosDeg(d float64) float64 { return math.Cos(d * math.Pi / 180) }
+
+func inRange(t, start, end time.Time) bool {
+	return !t.Before(start) && t.Before(end)
+}
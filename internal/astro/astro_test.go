@@ -0,0 +1,112 @@
+package astro
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSeasonTimes(t *testing.T) {
+	cases := []struct {
+		season int
+		year   int
+		want   time.Time
+	}{
+		{0, 2024, time.Date(2024, 3, 20, 3, 6, 0, 0, time.UTC)},
+		{1, 2024, time.Date(2024, 6, 20, 20, 51, 0, 0, time.UTC)},
+		{2, 2024, time.Date(2024, 9, 22, 12, 44, 0, 0, time.UTC)},
+		{3, 2024, time.Date(2024, 12, 21, 9, 20, 0, 0, time.UTC)},
+		{0, 2026, time.Date(2026, 3, 20, 14, 46, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		got := seasonTime(seasons[tc.season], tc.year)
+		if d := got.Sub(tc.want); d < -5*time.Minute || d > 5*time.Minute {
+			t.Fatalf("%s %d: expected %s, got %s", seasons[tc.season].name, tc.year, tc.want, got)
+		}
+	}
+}
+
+func TestMoonPhases(t *testing.T) {
+	cases := map[time.Time]EventKind{
+		time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC): KindFullMoon,
+		time.Date(2024, 4, 8, 18, 21, 0, 0, time.UTC):  KindNewMoon, // total solar eclipse
+		time.Date(2024, 8, 19, 18, 26, 0, 0, time.UTC): KindFullMoon,
+	}
+	for want, kind := range cases {
+		events := EventsOn(want)
+		var found bool
+		for _, e := range events {
+			if e.Kind != kind {
+				continue
+			}
+			found = true
+			if d := e.Time.Sub(want); d < -10*time.Minute || d > 10*time.Minute {
+				t.Fatalf("%s: expected %s, got %s", kind, want, e.Time)
+			}
+		}
+		if !found {
+			t.Fatalf("expected %s on %s, got %+v", kind, want.Format("2006-01-02"), events)
+		}
+	}
+	if lit := MoonIllumination(time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC)); math.Abs(lit-1) > 0.01 {
+		t.Fatalf("expected a full moon to be fully lit, got %v", lit)
+	}
+	if lit := MoonIllumination(time.Date(2024, 4, 8, 18, 21, 0, 0, time.UTC)); lit > 0.01 {
+		t.Fatalf("expected a new moon to be dark, got %v", lit)
+	}
+}
+
+func TestPlanetEvents(t *testing.T) {
+	cases := map[string]string{
+		"2023-11-03": "Jupiter at opposition",
+		"2024-09-08": "Saturn at opposition",
+		"2025-01-16": "Mars at opposition",
+		"2025-01-10": "Venus at greatest eastern elongation",
+		"2025-06-01": "Venus at greatest western elongation",
+		"2024-03-24": "Mercury at greatest eastern elongation",
+	}
+	for date, want := range cases {
+		d, _ := time.Parse("2006-01-02", date)
+		if !hasEvent(d, want) && !hasEvent(d.AddDate(0, 0, -1), want) && !hasEvent(d.AddDate(0, 0, 1), want) {
+			t.Fatalf("expected %s within a day of %s", want, date)
+		}
+	}
+}
+
+func TestMeteorShowerPeaks(t *testing.T) {
+	events := EventsOn(time.Date(2026, 8, 12, 0, 0, 0, 0, time.UTC))
+	var perseids *Event
+	for i := range events {
+		if events[i].Kind == KindMeteorShower {
+			perseids = &events[i]
+		}
+	}
+	if perseids == nil || perseids.Name != "Perseid meteor shower" || !strings.Contains(perseids.Description, "Swift-Tuttle") {
+		t.Fatalf("expected the Perseids, got %+v", events)
+	}
+	// The 2026 Perseids peak two days before a new moon.
+	if !strings.Contains(perseids.Description, "great year to watch") {
+		t.Fatalf("expected a dark-moon note, got %q", perseids.Description)
+	}
+}
+
+func TestEventsOnUsesLocalDay(t *testing.T) {
+	// The June 2024 solstice at 20:51 UTC was already June 21 in Tokyo.
+	tokyo := time.FixedZone("JST", 9*60*60)
+	if !hasEvent(time.Date(2024, 6, 21, 0, 0, 0, 0, tokyo), "June solstice") {
+		t.Fatalf("expected the June solstice on June 21 in Tokyo")
+	}
+	if hasEvent(time.Date(2024, 6, 20, 0, 0, 0, 0, tokyo), "June solstice") {
+		t.Fatalf("did not expect the June solstice on June 20 in Tokyo")
+	}
+}
+
+func hasEvent(day time.Time, name string) bool {
+	for _, e := range EventsOn(day) {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
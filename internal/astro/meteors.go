@@ -0,0 +1,47 @@
+package astro
+
+import (
+	"fmt"
+	"time"
+)
+
+type meteorShower struct {
+	name   string
+	month  time.Month
+	day    int // typical night of the peak
+	rate   int // meteors an hour under ideal dark skies
+	parent string
+}
+
+// meteorShowers are the major annual showers with their usual peak nights.
+var meteorShowers = []meteorShower{
+	{"Quadrantid", time.January, 3, 120, "the asteroid 2003 EH1"},
+	{"Lyrid", time.April, 22, 18, "Comet Thatcher"},
+	{"Eta Aquariid", time.May, 5, 50, "Halley's Comet"},
+	{"Perseid", time.August, 12, 100, "Comet Swift-Tuttle"},
+	{"Draconid", time.October, 8, 10, "Comet Giacobini-Zinner"},
+	{"Orionid", time.October, 21, 20, "Halley's Comet"},
+	{"Leonid", time.November, 17, 15, "Comet Tempel-Tuttle"},
+	{"Geminid", time.December, 14, 150, "the asteroid 3200 Phaethon"},
+	{"Ursid", time.December, 22, 10, "Comet Tuttle"},
+}
+
+func meteorShowersOn(start time.Time) []Event {
+	var events []Event
+	for _, shower := range meteorShowers {
+		if start.Month() != shower.month || start.Day() != shower.day {
+			continue
+		}
+		night := start.Add(23 * time.Hour)
+		desc := fmt.Sprintf("The %s meteor shower peaks tonight, with up to about %d shooting stars an hour from a dark spot. "+
+			"They are tiny bits of dust from %s burning up high in our atmosphere.", shower.name, shower.rate, shower.parent)
+		switch lit := MoonIllumination(night); {
+		case lit >= 0.6:
+			desc += " A bright Moon will hide the fainter ones this year."
+		case lit <= 0.25:
+			desc += " The Moon is dim, so it's a great year to watch."
+		}
+		events = append(events, Event{Kind: KindMeteorShower, Name: shower.name + " meteor shower", Time: night, Description: desc})
+	}
+	return events
+}
@@ -0,0 +1,85 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+const synodicMonth = 29.530588861
+
+// moonPhase returns the time of the new moon (phase 0) or full moon (phase
+// 0.5) for lunation k, counted from the new moon of 2000 January 6.
+func moonPhase(k float64) time.Time {
+	t := k / 1236.85
+	jde := 2451550.09766 + synodicMonth*k + 0.00015437*t*t - 0.000000150*t*t*t + 0.00000000073*t*t*t*t
+	e := 1 - 0.002516*t - 0.0000074*t*t
+	m := 2.5534 + 29.10535670*k - 0.0000014*t*t - 0.00000011*t*t*t
+	mp := 201.5643 + 385.81693528*k + 0.0107582*t*t + 0.00001238*t*t*t - 0.000000058*t*t*t*t
+	f := 160.7108 + 390.67050284*k - 0.0016118*t*t - 0.00000227*t*t*t + 0.000000011*t*t*t*t
+	om := 124.7746 - 1.56375588*k + 0.0020672*t*t + 0.00000215*t*t*t
+
+	c := [2]float64{-0.40720, 0.17241} // new moon
+	if k-math.Floor(k) != 0 {
+		c = [2]float64{-0.40614, 0.17302} // full moon
+	}
+	jde += c[0]*sinDeg(mp) +
+		c[1]*e*sinDeg(m) +
+		0.01608*sinDeg(2*mp) +
+		0.01039*sinDeg(2*f) +
+		0.00739*e*sinDeg(mp-m) -
+		0.00514*e*sinDeg(mp+m) +
+		0.00208*e*e*sinDeg(2*m) -
+		0.00111*sinDeg(mp-2*f) -
+		0.00057*sinDeg(mp+2*f) +
+		0.00056*e*sinDeg(2*mp+m) -
+		0.00042*sinDeg(3*mp) +
+		0.00042*e*sinDeg(m+2*f) +
+		0.00038*e*sinDeg(m-2*f) -
+		0.00024*e*sinDeg(2*mp-m) -
+		0.00017*sinDeg(om)
+	return fromJDE(jde)
+}
+
+// lunation returns the lunation number of the new moon at or before t.
+func lunation(t time.Time) float64 {
+	k := math.Floor((julianDay(t) - 2451550.09766) / synodicMonth)
+	// The mean phase can be off by most of a day; settle on the true one.
+	for moonPhase(k).After(t) {
+		k--
+	}
+	for !moonPhase(k + 1).After(t) {
+		k++
+	}
+	return k
+}
+
+// MoonIllumination returns the lit fraction of the Moon's disk at t, from 0
+// (new) to 1 (full), estimated from the Moon's age.
+func MoonIllumination(t time.Time) float64 {
+	k := lunation(t)
+	age := t.Sub(moonPhase(k)).Hours() / 24
+	return (1 - math.Cos(2*math.Pi*age/synodicMonth)) / 2
+}
+
+func moonPhasesBetween(start, end time.Time) []Event {
+	var events []Event
+	k := lunation(start)
+	for _, phase := range []float64{k, k + 0.5, k + 1, k + 1.5} {
+		at := moonPhase(phase)
+		if !inRange(at, start, end) {
+			continue
+		}
+		if phase == math.Floor(phase) {
+			events = append(events, Event{
+				Kind: KindNewMoon, Name: "New Moon", Time: at,
+				Description: "It's a new moon, so the Moon is hidden near the Sun and tonight's sky is extra dark for spotting stars.",
+			})
+		} else {
+			events = append(events, Event{
+				Kind: KindFullMoon, Name: "Full Moon", Time: at,
+				Description: "The Moon is full tonight, so the whole side facing Earth is lit by the Sun and it rises around sunset.",
+			})
+		}
+	}
+	return events
+}
@@ -0,0 +1,131 @@
+package astro
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// orbit holds JPL approximate Keplerian elements at J2000 and their rates per
+// Julian century: semi-major axis (au), eccentricity, inclination, mean
+// longitude, longitude of perihelion, and longitude of the ascending node
+// (degrees).
+type orbit struct {
+	a, e, i, l, peri, node       float64
+	da, de, di, dl, dperi, dnode float64
+}
+
+type planet struct {
+	name  string
+	inner bool
+	orbit orbit
+}
+
+var earthOrbit = orbit{
+	1.00000261, 0.01671123, -0.00001531, 100.46457166, 102.93768193, 0,
+	0.00000562, -0.00004392, -0.01294668, 35999.37244981, 0.32327364, 0,
+}
+
+var planets = []planet{
+	{"Mercury", true, orbit{
+		0.38709927, 0.20563593, 7.00497902, 252.25032350, 77.45779628, 48.33076593,
+		0.00000037, 0.00001906, -0.00594749, 149472.67411175, 0.16047689, -0.12534081,
+	}},
+	{"Venus", true, orbit{
+		0.72333566, 0.00677672, 3.39467605, 181.97909950, 131.60246718, 76.67984255,
+		0.00000390, -0.00004107, -0.00078890, 58517.81538729, 0.00268329, -0.27769418,
+	}},
+	{"Mars", false, orbit{
+		1.52371034, 0.09339410, 1.84969142, -4.55343205, -23.94362959, 49.55953891,
+		0.00001847, 0.00007882, -0.00813131, 19140.30268499, 0.44441088, -0.29257343,
+	}},
+	{"Jupiter", false, orbit{
+		5.20288700, 0.04838624, 1.30439695, 34.39644051, 14.72847983, 100.47390909,
+		-0.00011607, -0.00013253, -0.00183714, 3034.74612775, 0.21252668, 0.20469106,
+	}},
+	{"Saturn", false, orbit{
+		9.53667594, 0.05386179, 2.48599187, 49.95424423, 92.59887831, 113.66242448,
+		-0.00125060, -0.00050991, 0.00193609, 1222.49362201, -0.41897216, -0.28867794,
+	}},
+}
+
+type vec3 struct{ x, y, z float64 }
+
+func (v vec3) sub(w vec3) vec3    { return vec3{v.x - w.x, v.y - w.y, v.z - w.z} }
+func (v vec3) dot(w vec3) float64 { return v.x*w.x + v.y*w.y + v.z*w.z }
+func (v vec3) norm() float64      { return math.Sqrt(v.dot(v)) }
+
+// position returns the heliocentric ecliptic position (au) at Julian Day jd.
+func (o orbit) position(jd float64) vec3 {
+	t := (jd - j2000) / 36525
+	a := o.a + o.da*t
+	e := o.e + o.de*t
+	inc := (o.i + o.di*t) * math.Pi / 180
+	l := o.l + o.dl*t
+	peri := o.peri + o.dperi*t
+	node := (o.node + o.dnode*t) * math.Pi / 180
+	w := peri*math.Pi/180 - node
+	m := math.Mod(l-peri, 360) * math.Pi / 180
+
+	// Solve Kepler's equation E - e sin E = M by Newton's method.
+	ea := m + e*math.Sin(m)
+	for i := 0; i < 10; i++ {
+		ea -= (ea - e*math.Sin(ea) - m) / (1 - e*math.Cos(ea))
+	}
+	xp := a * (math.Cos(ea) - e)
+	yp := a * math.Sqrt(1-e*e) * math.Sin(ea)
+
+	cw, sw := math.Cos(w), math.Sin(w)
+	cn, sn := math.Cos(node), math.Sin(node)
+	ci, si := math.Cos(inc), math.Sin(inc)
+	return vec3{
+		x: (cw*cn-sw*sn*ci)*xp + (-sw*cn-cw*sn*ci)*yp,
+		y: (cw*sn+sw*cn*ci)*xp + (-sw*sn+cw*cn*ci)*yp,
+		z: sw*si*xp + cw*si*yp,
+	}
+}
+
+// elongation returns the planet's angle from the Sun as seen from Earth in
+// degrees, and whether it lies east of the Sun (in the evening sky).
+func (p planet) elongation(t time.Time) (float64, bool) {
+	jd := julianDay(t)
+	earth := earthOrbit.position(jd)
+	geo := p.orbit.position(jd).sub(earth)
+	sun := vec3{-earth.x, -earth.y, -earth.z}
+	angle := math.Acos(geo.dot(sun)/(geo.norm()*sun.norm())) * 180 / math.Pi
+	diff := math.Atan2(geo.y, geo.x) - math.Atan2(sun.y, sun.x)
+	east := math.Sin(diff) > 0
+	return angle, east
+}
+
+// planetEventsOn reports oppositions of the outer planets and greatest
+// elongations of Mercury and Venus: the day the planet's angle from the Sun
+// peaks, sampled at local noon.
+func planetEventsOn(start time.Time) []Event {
+	noon := start.Add(12 * time.Hour)
+	var events []Event
+	for _, p := range planets {
+		prev, _ := p.elongation(noon.AddDate(0, 0, -1))
+		cur, east := p.elongation(noon)
+		next, _ := p.elongation(noon.AddDate(0, 0, 1))
+		if !(cur > prev && cur >= next) {
+			continue
+		}
+		if !p.inner {
+			events = append(events, Event{
+				Kind: KindOpposition, Name: p.name + " at opposition", Time: noon,
+				Description: fmt.Sprintf("%s is at opposition: Earth passes between it and the Sun, so %s rises at sunset and shines its brightest all night.", p.name, p.name),
+			})
+			continue
+		}
+		side, sky := "western", "eastern sky before sunrise"
+		if east {
+			side, sky = "eastern", "western sky after sunset"
+		}
+		events = append(events, Event{
+			Kind: KindGreatestElongation, Name: fmt.Sprintf("%s at greatest %s elongation", p.name, side), Time: noon,
+			Description: fmt.Sprintf("%s is as far from the Sun as it gets in our sky (%.0f degrees), so it's easiest to spot low in the %s.", p.name, cur, sky),
+		})
+	}
+	return events
+}
@@ -0,0 +1,64 @@
+package astro
+
+import "time"
+
+// seasonTerms are the periodic terms (A, B, C) of Meeus table 27.C.
+var seasonTerms = [][3]float64{
+	{485, 324.96, 1934.136}, {203, 337.23, 32964.467}, {199, 342.08, 20.186},
+	{182, 27.85, 445267.112}, {156, 73.14, 45036.886}, {136, 171.52, 22518.443},
+	{77, 222.54, 65928.934}, {74, 296.72, 3034.906}, {70, 243.58, 9037.513},
+	{58, 119.81, 33718.147}, {52, 297.17, 150.678}, {50, 21.02, 2281.226},
+	{45, 247.54, 29929.562}, {44, 325.15, 31555.956}, {29, 60.93, 4443.417},
+	{18, 155.12, 67555.328}, {17, 288.79, 4562.452}, {16, 198.04, 62894.029},
+	{14, 199.76, 31436.921}, {12, 95.39, 14577.848}, {12, 287.11, 31931.756},
+	{12, 320.81, 34777.259}, {9, 227.73, 1222.114}, {8, 15.45, 16859.074},
+}
+
+type season struct {
+	kind        EventKind
+	name        string
+	description string
+	mean        [5]float64 // Meeus table 27.B polynomial in millennia from 2000
+}
+
+var seasons = []season{
+	{KindEquinox, "March equinox",
+		"Today is the March equinox, when day and night are about the same length all over Earth. Spring begins in the Northern Hemisphere and autumn in the Southern Hemisphere.",
+		[5]float64{2451623.80984, 365242.37404, 0.05169, -0.00411, -0.00057}},
+	{KindSolstice, "June solstice",
+		"Today is the June solstice, the longest day of the year in the Northern Hemisphere and the shortest in the Southern Hemisphere.",
+		[5]float64{2451716.56767, 365241.62603, 0.00325, 0.00888, -0.00030}},
+	{KindEquinox, "September equinox",
+		"Today is the September equinox, when day and night are about the same length all over Earth. Autumn begins in the Northern Hemisphere and spring in the Southern Hemisphere.",
+		[5]float64{2451810.21715, 365242.01767, -0.11575, 0.00337, 0.00078}},
+	{KindSolstice, "December solstice",
+		"Today is the December solstice, the shortest day of the year in the Northern Hemisphere and the longest in the Southern Hemisphere.",
+		[5]float64{2451900.05952, 365242.74049, -0.06223, -0.00823, 0.00032}},
+}
+
+// seasonTime returns the instant of a season's start in the given year.
+func seasonTime(s season, year int) time.Time {
+	y := float64(year-2000) / 1000
+	jde0 := s.mean[0] + s.mean[1]*y + s.mean[2]*y*y + s.mean[3]*y*y*y + s.mean[4]*y*y*y*y
+	t := (jde0 - j2000) / 36525
+	w := 35999.373*t - 2.47
+	dl := 1 + 0.0334*cosDeg(w) + 0.0007*cosDeg(2*w)
+	var sum float64
+	for _, term := range seasonTerms {
+		sum += term[0] * cosDeg(term[1]+term[2]*t)
+	}
+	return fromJDE(jde0 + 0.00001*sum/dl)
+}
+
+func seasonsBetween(start, end time.Time) []Event {
+	var events []Event
+	for year := start.UTC().Year(); year <= end.UTC().Year(); year++ {
+		for _, s := range seasons {
+			at := seasonTime(s, year)
+			if inRange(at, start, end) {
+				events = append(events, Event{Kind: s.kind, Name: s.name, Time: at, Description: s.description})
+			}
+		}
+	}
+	return events
+}
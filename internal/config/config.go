@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Config holds resolved configuration values after merging file, env, and flags.
@@ -22,11 +23,195 @@ type Config struct {
 	TextModel        string `json:"textModel,omitempty"`
 	TTSModel         string `json:"ttsModel,omitempty"`
 	TTSProvider      string `json:"ttsProvider,omitempty"`
+	TTSCommand       string `json:"ttsCommand,omitempty"`
 	TopicHistoryPath string `json:"topicHistoryPath,omitempty"`
 
+	// TopicBacklogPath is the local topic backlog (topics pinned to dates and
+	// queued for upcoming episodes), used when no S3 bucket is configured.
+	TopicBacklogPath string `json:"topicBacklogPath,omitempty"`
+
+	// TopicSimilarity is how a generated topic is compared with history:
+	// "ngram" (character trigram overlap, the default), "embeddings" (cosine
+	// similarity of EmbeddingModel vectors cached in the history), or "off".
+	// Topics at or above TopicSimilarityThreshold (0 uses the mode's default)
+	// are rejected and regenerated. TopicHistoryPromptLimit caps how many
+	// recent topics are listed in the topic prompt (0 lists them all).
+	TopicSimilarity          string  `json:"topicSimilarity,omitempty"`
+	TopicSimilarityThreshold float64 `json:"topicSimilarityThreshold,omitempty"`
+	EmbeddingModel           string  `json:"embeddingModel,omitempty"`
+	TopicHistoryPromptLimit  int     `json:"topicHistoryPromptLimit,omitempty"`
+
+	// CategoryBalance classifies each generated topic into one of
+	// TopicCategories, stored in the topic history, and steers away from
+	// categories used in the last CategoryCooldown episodes or already used
+	// CategoryQuotas[category] times in the last CategoryWindow episodes.
+	// ThemeWeeks apply with or without it.
+	CategoryBalance  bool           `json:"categoryBalance,omitempty"`
+	TopicCategories  []string       `json:"topicCategories,omitempty"`
+	CategoryCooldown int            `json:"categoryCooldown,omitempty"`
+	CategoryWindow   int            `json:"categoryWindow,omitempty"`
+	CategoryQuotas   map[string]int `json:"categoryQuotas,omitempty"`
+	ThemeWeeks       []ThemeWeek    `json:"themeWeeks,omitempty"`
+
+	// Series schedules multi-part series that run on consecutive episodes.
+	Series []Series `json:"series,omitempty"`
+
+	// RecapDay is the weekday ("saturday") whose episode reviews the six days
+	// before it instead of covering a new topic. Empty turns recaps off.
+	RecapDay string `json:"recapDay,omitempty"`
+
+	// Timezone is the IANA zone (such as "America/Los_Angeles") whose
+	// calendar day is the episode date. Empty means UTC.
+	Timezone string `json:"timezone,omitempty"`
+
+	// OutDir is the local output root (default "out").
+	OutDir string `json:"outDir,omitempty"`
+
+	// Shows holds per-show settings keyed by show name. Each entry is a
+	// partial config applied over the top-level settings when the show is
+	// selected with --show; see SelectShow.
+	Shows map[string]json.RawMessage `json:"shows,omitempty"`
+
+	// ShowPath points to a JSON show definition that lists the episode's
+	// sections. Empty uses the built-in intro/topic/game/outro structure.
+	ShowPath string `json:"showPath,omitempty"`
+
+	// Show identity used by the prompt templates. Empty fields use the
+	// built-in Curious World Podcast identity. AgeRange is written like "7–9".
+	ShowName    string `json:"showName,omitempty"`
+	HostName    string `json:"hostName,omitempty"`
+	HostPersona string `json:"hostPersona,omitempty"`
+	Audience    string `json:"audience,omitempty"`
+	AgeRange    string `json:"ageRange,omitempty"`
+	Tone        string `json:"tone,omitempty"`
+
+	// Language is the BCP-47 tag episodes are written and voiced in (default
+	// English). GameLanguage, when set to a different language, makes the
+	// brain game bilingual practice in that language. Voices maps a language
+	// tag to the TTS voice for sections spoken in a language other than
+	// Language (such as a bilingual game); Voice is used for the rest.
+	Language     string            `json:"language,omitempty"`
+	GameLanguage string            `json:"gameLanguage,omitempty"`
+	Voices       map[string]string `json:"voices,omitempty"`
+
+	// HolidayRegions selects built-in holiday calendars (such as "us", "uk",
+	// "ca", "mx", "in", and "world"); HolidayFiles adds JSON rule files or
+	// .ics calendars, which take precedence over the regions. Holiday
+	// descriptions can be replaced by holiday ID with HolidayDescriptions.
+	HolidayRegions      []string          `json:"holidayRegions,omitempty"`
+	HolidayFiles        []string          `json:"holidayFiles,omitempty"`
+	HolidayDescriptions map[string]string `json:"holidayDescriptions,omitempty"`
+
+	// SkyEvents mentions the day's moon phases, equinoxes and solstices,
+	// meteor shower peaks, and planet events in the intro and topic prompts.
+	// It is off unless enabled.
+	SkyEvents bool `json:"skyEvents,omitempty"`
+
+	// PromptsDir holds <name>.tmpl files that replace the built-in prompt
+	// templates, and sections/<id>.tmpl files that replace section prompts.
+	PromptsDir string `json:"promptsDir,omitempty"`
+
+	// SafetyReview enables the model-based safety review after script generation.
+	// Findings at or above SafetyReviewThreshold either block the episode or
+	// regenerate the offending sections, depending on SafetyReviewAction.
+	SafetyReview          bool   `json:"safetyReview,omitempty"`
+	SafetyReviewThreshold string `json:"safetyReviewThreshold,omitempty"`
+	SafetyReviewAction    string `json:"safetyReviewAction,omitempty"`
+
+	// SafetyTermsPath points to a JSON term list merged over the built-in
+	// lexical safety terms. Hits at or above SafetyTermThreshold fail the script.
+	SafetyTermsPath     string `json:"safetyTermsPath,omitempty"`
+	SafetyTermThreshold string `json:"safetyTermThreshold,omitempty"`
+
+	// FactCheck enables claim extraction and assessment after script generation.
+	// Claims below FactCheckMinConfidence (0-1) either fail the run or
+	// regenerate the affected sections, depending on FactCheckAction.
+	FactCheck              bool    `json:"factCheck,omitempty"`
+	FactCheckMinConfidence float64 `json:"factCheckMinConfidence,omitempty"`
+	FactCheckAction        string  `json:"factCheckAction,omitempty"`
+
+	// ReadingGradeCeiling is the highest Flesch-Kincaid grade allowed per
+	// section; sections above it are rewritten in simpler words. Zero disables.
+	ReadingGradeCeiling float64 `json:"readingGradeCeiling,omitempty"`
+
+	// TargetWordCount is the total spoken-word target for an episode. Section
+	// budgets are scaled to add up to it, and sections outside
+	// WordCountTolerance (a fraction of their budget) are expanded or
+	// tightened. Zero keeps the default budgets and skips the follow-up passes.
+	TargetWordCount    int     `json:"targetWordCount,omitempty"`
+	WordCountTolerance float64 `json:"wordCountTolerance,omitempty"`
+
 	// Not persisted to file; sourced from env only.
 	OpenAIAPIKey     string `json:"-"`
 	ElevenLabsAPIKey string `json:"-"`
+
+	// Show is the selected show name, set by SelectShow.
+	Show          string `json:"-"`
+	showNamespace showNamespace
+}
+
+// ThemeWeek makes every weekday episode from Start to End (YYYY-MM-DD,
+// inclusive) draw its topic from one theme, such as "Ocean Week".
+type ThemeWeek struct {
+	Name  string `json:"name"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// ThemeOn returns the theme week covering date, if date is a weekday inside
+// one.
+func (c Config) ThemeOn(date time.Time) (ThemeWeek, bool) {
+	if wd := date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return ThemeWeek{}, false
+	}
+	key := date.Format("2006-01-02")
+	for _, week := range c.ThemeWeeks {
+		if week.Start <= key && key <= week.End {
+			return week, true
+		}
+	}
+	return ThemeWeek{}, false
+}
+
+// RecapOn reports whether date's episode is the weekly recap.
+func (c Config) RecapOn(date time.Time) bool {
+	day, ok := parseWeekday(c.RecapDay)
+	return ok && date.Weekday() == day
+}
+
+// parseWeekday parses an English weekday name or its three-letter
+// abbreviation, in any case.
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) < 3 {
+		return 0, false
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// Series is a multi-part series. The first episode on or after Start begins
+// it and each following episode covers the next part. Outline, when set,
+// gives the part titles; otherwise the text model plans Parts parts when the
+// series begins.
+type Series struct {
+	Title   string   `json:"title"`
+	Start   string   `json:"start"`
+	Parts   int      `json:"parts,omitempty"`
+	Outline []string `json:"outline,omitempty"`
+}
+
+// PartCount returns the number of parts in the series.
+func (s Series) PartCount() int {
+	if len(s.Outline) > 0 {
+		return len(s.Outline)
+	}
+	return s.Parts
 }
 
 // Overrides represents optional overrides from env or flags.
@@ -42,9 +227,79 @@ type Overrides struct {
 	TextModel        *string
 	TTSModel         *string
 	TTSProvider      *string
+	TTSCommand       *string
 	TopicHistoryPath *string
+	TopicBacklogPath *string
+	OutDir           *string
+
+	TopicSimilarity          *string
+	TopicSimilarityThreshold *float64
+	EmbeddingModel           *string
+	TopicHistoryPromptLimit  *int
+
+	CategoryBalance  *bool
+	TopicCategories  *[]string
+	CategoryCooldown *int
+	CategoryWindow   *int
+
+	RecapDay *string
+
+	ShowPath *string
+	Timezone *string
+
+	ShowName    *string
+	HostName    *string
+	HostPersona *string
+	Audience    *string
+	AgeRange    *string
+	Tone        *string
+	PromptsDir  *string
+
+	Language     *string
+	GameLanguage *string
+
+	HolidayRegions *[]string
+	HolidayFiles   *[]string
+	SkyEvents      *bool
+
+	SafetyReview          *bool
+	SafetyReviewThreshold *string
+	SafetyReviewAction    *string
+
+	SafetyTermsPath     *string
+	SafetyTermThreshold *string
+
+	FactCheck              *bool
+	FactCheckMinConfidence *float64
+	FactCheckAction        *string
+
+	ReadingGradeCeiling *float64
+
+	TargetWordCount    *int
+	WordCountTolerance *float64
 }
 
+// Safety review actions.
+const (
+	SafetyActionBlock      = "block"
+	SafetyActionRegenerate = "regenerate"
+)
+
+// Topic similarity modes.
+const (
+	TopicSimilarityNgram      = "ngram"
+	TopicSimilarityEmbeddings = "embeddings"
+	TopicSimilarityOff        = "off"
+)
+
+// Fact-check actions.
+const (
+	FactCheckActionFail       = "fail"
+	FactCheckActionRegenerate = "regenerate"
+)
+
+const defaultOutDir = "out"
+
 func Default() Config {
 	return Config{
 		Voice:            "alloy",
@@ -53,7 +308,32 @@ func Default() Config {
 		TextModel:        "gpt-5-mini",
 		TTSModel:         "gpt-4o-mini-tts",
 		TTSProvider:      "openai",
-		TopicHistoryPath: filepath.Join("out", "topic-history.json"),
+		TopicHistoryPath: filepath.Join(defaultOutDir, "topic-history.json"),
+		TopicBacklogPath: filepath.Join(defaultOutDir, "topic-backlog.json"),
+
+		TopicSimilarity:         TopicSimilarityNgram,
+		EmbeddingModel:          "text-embedding-3-small",
+		TopicHistoryPromptLimit: 30,
+
+		TopicCategories: []string{
+			"animals", "plants", "human body", "astronomy", "earth and weather",
+			"oceans", "physics", "chemistry", "technology", "history",
+			"geography", "cultural celebrations",
+		},
+		CategoryCooldown: 2,
+		CategoryWindow:   14,
+
+		HolidayRegions: []string{"us"},
+
+		SafetyReviewThreshold: "medium",
+		SafetyReviewAction:    SafetyActionBlock,
+
+		SafetyTermThreshold: "medium",
+
+		FactCheckMinConfidence: 0.7,
+		FactCheckAction:        FactCheckActionFail,
+
+		WordCountTolerance: 0.15,
 	}
 }
 
@@ -113,14 +393,163 @@ func FromEnv() (Overrides, string, string) {
 	if v, ok := os.LookupEnv("YODEX_TTS_PROVIDER"); ok {
 		ov.TTSProvider = &[]string{v}[0]
 	}
+	if v, ok := os.LookupEnv("YODEX_TTS_COMMAND"); ok {
+		ov.TTSCommand = &[]string{v}[0]
+	}
 	if v, ok := os.LookupEnv("YODEX_TOPIC_HISTORY_PATH"); ok {
 		ov.TopicHistoryPath = &[]string{v}[0]
 	}
+	if v, ok := os.LookupEnv("YODEX_TOPIC_BACKLOG_PATH"); ok {
+		ov.TopicBacklogPath = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_TOPIC_SIMILARITY"); ok {
+		ov.TopicSimilarity = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_TOPIC_SIMILARITY_THRESHOLD"); ok {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			ov.TopicSimilarityThreshold = &[]float64{f}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_EMBEDDING_MODEL"); ok {
+		ov.EmbeddingModel = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_TOPIC_HISTORY_PROMPT_LIMIT"); ok {
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			ov.TopicHistoryPromptLimit = &[]int{n}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_CATEGORY_BALANCE"); ok {
+		if b, err := parseBool(v); err == nil {
+			ov.CategoryBalance = &[]bool{b}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_TOPIC_CATEGORIES"); ok {
+		categories := splitList(v, ",")
+		ov.TopicCategories = &categories
+	}
+	if v, ok := os.LookupEnv("YODEX_CATEGORY_COOLDOWN"); ok {
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			ov.CategoryCooldown = &[]int{n}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_CATEGORY_WINDOW"); ok {
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			ov.CategoryWindow = &[]int{n}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_RECAP_DAY"); ok {
+		ov.RecapDay = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_OUT_DIR"); ok {
+		ov.OutDir = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_TIMEZONE"); ok {
+		ov.Timezone = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_SHOW_PATH"); ok {
+		ov.ShowPath = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_SHOW_NAME"); ok {
+		ov.ShowName = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_HOST_NAME"); ok {
+		ov.HostName = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_HOST_PERSONA"); ok {
+		ov.HostPersona = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_AUDIENCE"); ok {
+		ov.Audience = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_AGE_RANGE"); ok {
+		ov.AgeRange = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_TONE"); ok {
+		ov.Tone = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_LANGUAGE"); ok {
+		ov.Language = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_GAME_LANGUAGE"); ok {
+		ov.GameLanguage = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_HOLIDAY_REGIONS"); ok {
+		regions := splitList(v, ",")
+		ov.HolidayRegions = &regions
+	}
+	if v, ok := os.LookupEnv("YODEX_HOLIDAY_FILES"); ok {
+		files := filepath.SplitList(v)
+		ov.HolidayFiles = &files
+	}
+	if v, ok := os.LookupEnv("YODEX_SKY_EVENTS"); ok {
+		if b, err := parseBool(v); err == nil {
+			ov.SkyEvents = &[]bool{b}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_PROMPTS_DIR"); ok {
+		ov.PromptsDir = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_SAFETY_REVIEW"); ok {
+		if b, err := parseBool(v); err == nil {
+			ov.SafetyReview = &[]bool{b}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_SAFETY_REVIEW_THRESHOLD"); ok {
+		ov.SafetyReviewThreshold = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_SAFETY_REVIEW_ACTION"); ok {
+		ov.SafetyReviewAction = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_SAFETY_TERMS_PATH"); ok {
+		ov.SafetyTermsPath = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_SAFETY_TERM_THRESHOLD"); ok {
+		ov.SafetyTermThreshold = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_FACT_CHECK"); ok {
+		if b, err := parseBool(v); err == nil {
+			ov.FactCheck = &[]bool{b}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_FACT_CHECK_MIN_CONFIDENCE"); ok {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			ov.FactCheckMinConfidence = &[]float64{f}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_FACT_CHECK_ACTION"); ok {
+		ov.FactCheckAction = &[]string{v}[0]
+	}
+	if v, ok := os.LookupEnv("YODEX_READING_GRADE_CEILING"); ok {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			ov.ReadingGradeCeiling = &[]float64{f}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_TARGET_WORD_COUNT"); ok {
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			ov.TargetWordCount = &[]int{n}[0]
+		}
+	}
+	if v, ok := os.LookupEnv("YODEX_WORD_COUNT_TOLERANCE"); ok {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			ov.WordCountTolerance = &[]float64{f}[0]
+		}
+	}
 	apiKey = os.Getenv("OPENAI_API_KEY")
 	elevenLabsKey = os.Getenv("ELEVENLABS_API_KEY")
 	return ov, apiKey, elevenLabsKey
 }
 
+// splitList splits a separated env value into trimmed, non-empty items.
+func splitList(s, sep string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func parseBool(s string) (bool, error) {
 	s = strings.TrimSpace(strings.ToLower(s))
 	if s == "" {
@@ -136,7 +565,9 @@ func parseBool(s string) (bool, error) {
 	return strconv.ParseBool(s)
 }
 
-// Merge applies overrides in order: file -> env -> flags.
+// Merge applies overrides in order: file -> env -> flags. When a show is
+// selected, its output directory, S3 prefix, and topic history path are then
+// namespaced by the show name.
 func Merge(fileCfg Config, env Overrides, flags Overrides, openAIKey string, elevenLabsKey string) Config {
 	cfg := fileCfg
 
@@ -171,9 +602,120 @@ func Merge(fileCfg Config, env Overrides, flags Overrides, openAIKey string, ele
 		if ov.TTSProvider != nil {
 			cfg.TTSProvider = *ov.TTSProvider
 		}
+		if ov.TTSCommand != nil {
+			cfg.TTSCommand = *ov.TTSCommand
+		}
 		if ov.TopicHistoryPath != nil {
 			cfg.TopicHistoryPath = *ov.TopicHistoryPath
 		}
+		if ov.TopicBacklogPath != nil {
+			cfg.TopicBacklogPath = *ov.TopicBacklogPath
+		}
+		if ov.OutDir != nil {
+			cfg.OutDir = *ov.OutDir
+		}
+		if ov.TopicSimilarity != nil {
+			cfg.TopicSimilarity = *ov.TopicSimilarity
+		}
+		if ov.TopicSimilarityThreshold != nil {
+			cfg.TopicSimilarityThreshold = *ov.TopicSimilarityThreshold
+		}
+		if ov.EmbeddingModel != nil {
+			cfg.EmbeddingModel = *ov.EmbeddingModel
+		}
+		if ov.TopicHistoryPromptLimit != nil {
+			cfg.TopicHistoryPromptLimit = *ov.TopicHistoryPromptLimit
+		}
+		if ov.CategoryBalance != nil {
+			cfg.CategoryBalance = *ov.CategoryBalance
+		}
+		if ov.TopicCategories != nil {
+			cfg.TopicCategories = *ov.TopicCategories
+		}
+		if ov.CategoryCooldown != nil {
+			cfg.CategoryCooldown = *ov.CategoryCooldown
+		}
+		if ov.CategoryWindow != nil {
+			cfg.CategoryWindow = *ov.CategoryWindow
+		}
+		if ov.RecapDay != nil {
+			cfg.RecapDay = *ov.RecapDay
+		}
+		if ov.ShowPath != nil {
+			cfg.ShowPath = *ov.ShowPath
+		}
+		if ov.Timezone != nil {
+			cfg.Timezone = *ov.Timezone
+		}
+		if ov.ShowName != nil {
+			cfg.ShowName = *ov.ShowName
+		}
+		if ov.HostName != nil {
+			cfg.HostName = *ov.HostName
+		}
+		if ov.HostPersona != nil {
+			cfg.HostPersona = *ov.HostPersona
+		}
+		if ov.Audience != nil {
+			cfg.Audience = *ov.Audience
+		}
+		if ov.AgeRange != nil {
+			cfg.AgeRange = *ov.AgeRange
+		}
+		if ov.Tone != nil {
+			cfg.Tone = *ov.Tone
+		}
+		if ov.Language != nil {
+			cfg.Language = *ov.Language
+		}
+		if ov.GameLanguage != nil {
+			cfg.GameLanguage = *ov.GameLanguage
+		}
+		if ov.HolidayRegions != nil {
+			cfg.HolidayRegions = *ov.HolidayRegions
+		}
+		if ov.HolidayFiles != nil {
+			cfg.HolidayFiles = *ov.HolidayFiles
+		}
+		if ov.SkyEvents != nil {
+			cfg.SkyEvents = *ov.SkyEvents
+		}
+		if ov.PromptsDir != nil {
+			cfg.PromptsDir = *ov.PromptsDir
+		}
+		if ov.SafetyReview != nil {
+			cfg.SafetyReview = *ov.SafetyReview
+		}
+		if ov.SafetyReviewThreshold != nil {
+			cfg.SafetyReviewThreshold = *ov.SafetyReviewThreshold
+		}
+		if ov.SafetyReviewAction != nil {
+			cfg.SafetyReviewAction = *ov.SafetyReviewAction
+		}
+		if ov.SafetyTermsPath != nil {
+			cfg.SafetyTermsPath = *ov.SafetyTermsPath
+		}
+		if ov.SafetyTermThreshold != nil {
+			cfg.SafetyTermThreshold = *ov.SafetyTermThreshold
+		}
+		if ov.FactCheck != nil {
+			cfg.FactCheck = *ov.FactCheck
+		}
+		if ov.FactCheckMinConfidence != nil {
+			cfg.FactCheckMinConfidence = *ov.FactCheckMinConfidence
+		}
+		if ov.FactCheckAction != nil {
+			cfg.FactCheckAction = *ov.FactCheckAction
+		}
+		if ov.ReadingGradeCeiling != nil {
+			cfg.ReadingGradeCeiling = *ov.ReadingGradeCeiling
+		}
+		if ov.TargetWordCount != nil {
+			cfg.TargetWordCount = *ov.TargetWordCount
+		}
+		if ov.WordCountTolerance != nil {
+			cfg.WordCountTolerance = *ov.WordCountTolerance
+		}
 	}
 
 	apply(env)
@@ -181,7 +723,20 @@ func Merge(fileCfg Config, env Overrides, flags Overrides, openAIKey string, ele
 
 	cfg.OpenAIAPIKey = openAIKey
 	cfg.ElevenLabsAPIKey = elevenLabsKey
-	return cfg
+	return applyShowNamespace(cfg)
+}
+
+// Location returns the configured timezone, or UTC when none is set.
+func (c Config) Location() (*time.Location, error) {
+	name := strings.TrimSpace(c.Timezone)
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %q: %w", c.Timezone, err)
+	}
+	return loc, nil
 }
 
 // Validation helpers
@@ -192,9 +747,106 @@ func ValidateForScript(cfg Config) error {
 	if cfg.TextModel == "" {
 		return errors.New("text model is required")
 	}
+	if !validThreshold(cfg.SafetyTermThreshold) {
+		return fmt.Errorf("invalid safety term threshold: %q (expected low, medium, or high)", cfg.SafetyTermThreshold)
+	}
+	if cfg.SafetyReview {
+		if !validThreshold(cfg.SafetyReviewThreshold) {
+			return fmt.Errorf("invalid safety review threshold: %q (expected low, medium, or high)", cfg.SafetyReviewThreshold)
+		}
+		switch strings.ToLower(strings.TrimSpace(cfg.SafetyReviewAction)) {
+		case SafetyActionBlock, SafetyActionRegenerate:
+		default:
+			return fmt.Errorf("invalid safety review action: %q (expected %s or %s)", cfg.SafetyReviewAction, SafetyActionBlock, SafetyActionRegenerate)
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.TopicSimilarity)) {
+	case "", TopicSimilarityNgram, TopicSimilarityEmbeddings, TopicSimilarityOff:
+	default:
+		return fmt.Errorf("invalid topic similarity: %q (expected %s, %s, or %s)", cfg.TopicSimilarity, TopicSimilarityNgram, TopicSimilarityEmbeddings, TopicSimilarityOff)
+	}
+	if cfg.TopicSimilarityThreshold < 0 || cfg.TopicSimilarityThreshold > 1 {
+		return fmt.Errorf("invalid topic similarity threshold: %v (expected 0 to 1)", cfg.TopicSimilarityThreshold)
+	}
+	if cfg.TopicHistoryPromptLimit < 0 {
+		return fmt.Errorf("invalid topic history prompt limit: %d (expected 0 or more)", cfg.TopicHistoryPromptLimit)
+	}
+	if cfg.CategoryBalance {
+		if len(cfg.TopicCategories) < 2 {
+			return errors.New("category balance needs at least two topic categories")
+		}
+		if cfg.CategoryCooldown < 0 || cfg.CategoryWindow < 0 {
+			return fmt.Errorf("invalid category cooldown or window: %d, %d (expected 0 or more)", cfg.CategoryCooldown, cfg.CategoryWindow)
+		}
+		for category, quota := range cfg.CategoryQuotas {
+			if quota < 1 {
+				return fmt.Errorf("invalid quota for category %q: %d (expected 1 or more)", category, quota)
+			}
+		}
+	}
+	for _, week := range cfg.ThemeWeeks {
+		if strings.TrimSpace(week.Name) == "" {
+			return errors.New("theme week needs a name")
+		}
+		start, err := time.Parse("2006-01-02", week.Start)
+		if err != nil {
+			return fmt.Errorf("theme week %q: invalid start %q (expected YYYY-MM-DD)", week.Name, week.Start)
+		}
+		end, err := time.Parse("2006-01-02", week.End)
+		if err != nil {
+			return fmt.Errorf("theme week %q: invalid end %q (expected YYYY-MM-DD)", week.Name, week.End)
+		}
+		if end.Before(start) {
+			return fmt.Errorf("theme week %q ends before it starts", week.Name)
+		}
+	}
+	for _, series := range cfg.Series {
+		if strings.TrimSpace(series.Title) == "" {
+			return errors.New("series needs a title")
+		}
+		if _, err := time.Parse("2006-01-02", series.Start); err != nil {
+			return fmt.Errorf("series %q: invalid start %q (expected YYYY-MM-DD)", series.Title, series.Start)
+		}
+		if len(series.Outline) > 0 && series.Parts != 0 && series.Parts != len(series.Outline) {
+			return fmt.Errorf("series %q: parts is %d but the outline has %d", series.Title, series.Parts, len(series.Outline))
+		}
+		if n := series.PartCount(); n < 2 || n > 10 {
+			return fmt.Errorf("series %q: invalid part count %d (expected 2 to 10)", series.Title, n)
+		}
+	}
+	if _, ok := parseWeekday(cfg.RecapDay); strings.TrimSpace(cfg.RecapDay) != "" && !ok {
+		return fmt.Errorf("invalid recap day: %q (expected a weekday such as saturday)", cfg.RecapDay)
+	}
+	if cfg.ReadingGradeCeiling < 0 {
+		return fmt.Errorf("invalid reading grade ceiling: %v (expected 0 or more)", cfg.ReadingGradeCeiling)
+	}
+	if cfg.TargetWordCount < 0 {
+		return fmt.Errorf("invalid target word count: %d (expected 0 or more)", cfg.TargetWordCount)
+	}
+	if cfg.TargetWordCount > 0 && (cfg.WordCountTolerance <= 0 || cfg.WordCountTolerance >= 1) {
+		return fmt.Errorf("invalid word count tolerance: %v (expected between 0 and 1)", cfg.WordCountTolerance)
+	}
+	if cfg.FactCheck {
+		if cfg.FactCheckMinConfidence < 0 || cfg.FactCheckMinConfidence > 1 {
+			return fmt.Errorf("invalid fact check min confidence: %v (expected 0 to 1)", cfg.FactCheckMinConfidence)
+		}
+		switch strings.ToLower(strings.TrimSpace(cfg.FactCheckAction)) {
+		case FactCheckActionFail, FactCheckActionRegenerate:
+		default:
+			return fmt.Errorf("invalid fact check action: %q (expected %s or %s)", cfg.FactCheckAction, FactCheckActionFail, FactCheckActionRegenerate)
+		}
+	}
 	return nil
 }
 
+func validThreshold(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "low", "medium", "high":
+		return true
+	}
+	return false
+}
+
 func ValidateForAudio(cfg Config) error {
 	provider := strings.ToLower(strings.TrimSpace(cfg.TTSProvider))
 	if provider == "" {
@@ -209,6 +861,11 @@ func ValidateForAudio(cfg Config) error {
 		if cfg.ElevenLabsAPIKey == "" {
 			return errors.New("ELEVENLABS_API_KEY is required for audio generation")
 		}
+	case "command":
+		if strings.TrimSpace(cfg.TTSCommand) == "" {
+			return errors.New("tts command is required for the command tts provider")
+		}
+		return nil
 	default:
 		return fmt.Errorf("unsupported tts provider: %s", cfg.TTSProvider)
 	}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 )
 
 func TestMergePrecedence(t *testing.T) {
@@ -31,6 +32,19 @@ func TestMergePrecedence(t *testing.T) {
 	}
 }
 
+func TestLocation(t *testing.T) {
+	if loc, err := (Config{}).Location(); err != nil || loc != time.UTC {
+		t.Fatalf("expected UTC by default, got %v, %v", loc, err)
+	}
+	loc, err := Config{Timezone: "America/Los_Angeles"}.Location()
+	if err != nil || loc.String() != "America/Los_Angeles" {
+		t.Fatalf("expected Los Angeles, got %v, %v", loc, err)
+	}
+	if _, err := (Config{Timezone: "Mars/Olympus_Mons"}).Location(); err == nil {
+		t.Fatalf("expected invalid timezone error")
+	}
+}
+
 func TestValidateScriptRequiresAPIKey(t *testing.T) {
 	cfg := Default()
 	if err := ValidateForScript(cfg); err == nil {
@@ -62,4 +76,114 @@ func TestFromEnv(t *testing.T) {
 	}
 }
 
+func TestFromEnvHolidayLists(t *testing.T) {
+	t.Setenv("YODEX_HOLIDAY_REGIONS", " uk, world ,")
+	ov, _, _ := FromEnv()
+	if ov.HolidayRegions == nil || len(*ov.HolidayRegions) != 2 || (*ov.HolidayRegions)[1] != "world" {
+		t.Fatalf("unexpected holiday regions: %v", ov.HolidayRegions)
+	}
+	cfg := Merge(Default(), ov, Overrides{}, "", "")
+	if len(cfg.HolidayRegions) != 2 || cfg.HolidayRegions[0] != "uk" {
+		t.Fatalf("expected env regions to replace defaults, got %v", cfg.HolidayRegions)
+	}
+
+	t.Setenv("YODEX_HOLIDAY_REGIONS", "")
+	ov, _, _ = FromEnv()
+	if cfg := Merge(Default(), ov, Overrides{}, "", ""); len(cfg.HolidayRegions) != 0 {
+		t.Fatalf("expected empty env to disable regions, got %v", cfg.HolidayRegions)
+	}
+}
+
 func strPtr(s string) *string { return &s }
+
+func TestValidateAudioCommandProvider(t *testing.T) {
+	cfg := Default()
+	cfg.TTSProvider = "command"
+	if err := ValidateForAudio(cfg); err == nil {
+		t.Fatalf("expected error without tts command")
+	}
+	cfg.TTSCommand = "piper --model en_US.onnx --output_file {output}"
+	cfg.TTSModel = ""
+	if err := ValidateForAudio(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTopicSimilaritySettings(t *testing.T) {
+	t.Setenv("YODEX_TOPIC_SIMILARITY", "embeddings")
+	t.Setenv("YODEX_TOPIC_SIMILARITY_THRESHOLD", "0.9")
+	t.Setenv("YODEX_TOPIC_HISTORY_PROMPT_LIMIT", "12")
+	ov, _, _ := FromEnv()
+	cfg := Merge(Default(), ov, Overrides{}, "sk-test", "")
+	if cfg.TopicSimilarity != TopicSimilarityEmbeddings || cfg.TopicSimilarityThreshold != 0.9 || cfg.TopicHistoryPromptLimit != 12 {
+		t.Fatalf("unexpected topic similarity settings: %q %v %d", cfg.TopicSimilarity, cfg.TopicSimilarityThreshold, cfg.TopicHistoryPromptLimit)
+	}
+	if cfg.EmbeddingModel != "text-embedding-3-small" {
+		t.Fatalf("expected default embedding model, got %q", cfg.EmbeddingModel)
+	}
+	if err := ValidateForScript(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg.TopicSimilarity = "fuzzy"
+	if err := ValidateForScript(cfg); err == nil {
+		t.Fatalf("expected invalid topic similarity error")
+	}
+	cfg.TopicSimilarity = TopicSimilarityNgram
+	cfg.TopicSimilarityThreshold = 1.5
+	if err := ValidateForScript(cfg); err == nil {
+		t.Fatalf("expected invalid threshold error")
+	}
+}
+
+func TestThemeWeeksAndSeries(t *testing.T) {
+	cfg := Default()
+	cfg.OpenAIAPIKey = "sk-test"
+	cfg.ThemeWeeks = []ThemeWeek{{Name: "Ocean Week", Start: "2026-06-08", End: "2026-06-14"}}
+	if err := ValidateForScript(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if week, ok := cfg.ThemeOn(time.Date(2026, 6, 12, 0, 0, 0, 0, time.UTC)); !ok || week.Name != "Ocean Week" {
+		t.Fatalf("expected Friday in the theme week, got %v %v", week, ok)
+	}
+	if _, ok := cfg.ThemeOn(time.Date(2026, 6, 13, 0, 0, 0, 0, time.UTC)); ok {
+		t.Fatalf("expected Saturday outside the theme week")
+	}
+	cfg.ThemeWeeks[0].End = "2026-06-01"
+	if err := ValidateForScript(cfg); err == nil {
+		t.Fatalf("expected error for a theme week ending before it starts")
+	}
+	cfg.ThemeWeeks = nil
+	cfg.Series = []Series{{Title: "Journey Through the Solar System", Start: "2026-09-07", Parts: 5}}
+	if err := ValidateForScript(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg.Series[0].Outline = []string{"The Sun", "Rocky Planets"}
+	if err := ValidateForScript(cfg); err == nil {
+		t.Fatalf("expected error for an outline that does not match the part count")
+	}
+	cfg.Series = nil
+	cfg.CategoryBalance = true
+	cfg.CategoryQuotas = map[string]int{"animals": 0}
+	if err := ValidateForScript(cfg); err == nil {
+		t.Fatalf("expected error for a zero category quota")
+	}
+}
+
+func TestRecapDay(t *testing.T) {
+	t.Setenv("YODEX_RECAP_DAY", "Sat")
+	envOv, _, _ := FromEnv()
+	cfg := Merge(Default(), envOv, Overrides{}, "key", "")
+	if err := ValidateForScript(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.RecapOn(time.Date(2026, 6, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected Saturday to be the recap day")
+	}
+	if cfg.RecapOn(time.Date(2026, 6, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected Friday not to be the recap day")
+	}
+	cfg.RecapDay = "caturday"
+	if err := ValidateForScript(cfg); err == nil {
+		t.Fatalf("expected error for an invalid recap day")
+	}
+}
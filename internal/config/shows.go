@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AllShows selects every show in the config (yodex all --show=*).
+const AllShows = "*"
+
+var showNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// showNamespace records which per-show locations the show entry set itself.
+// Locations it leaves unset are derived from the show name during Merge.
+type showNamespace struct {
+	outDir           bool
+	s3Prefix         bool
+	topicHistoryPath bool
+	topicBacklogPath bool
+}
+
+// ShowNames returns the configured show names in sorted order.
+func ShowNames(cfg Config) []string {
+	names := make([]string, 0, len(cfg.Shows))
+	for name := range cfg.Shows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelectShow applies the named entry from cfg.Shows over the top-level
+// settings. An empty name returns cfg unchanged. The show entry sits between
+// the file's top-level settings and env/flags in precedence.
+func SelectShow(cfg Config, name string) (Config, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return cfg, nil
+	}
+	if !showNamePattern.MatchString(name) {
+		return cfg, fmt.Errorf("invalid show name: %q (use lowercase letters, digits, '-' and '_')", name)
+	}
+	raw, ok := cfg.Shows[name]
+	if !ok {
+		if len(cfg.Shows) == 0 {
+			return cfg, fmt.Errorf("unknown show %q: no shows configured", name)
+		}
+		return cfg, fmt.Errorf("unknown show %q (configured: %s)", name, strings.Join(ShowNames(cfg), ", "))
+	}
+	shows := cfg.Shows
+	cfg.Shows = nil
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse show %s: %w", name, err)
+	}
+	var set struct {
+		OutDir           *string `json:"outDir"`
+		S3Prefix         *string `json:"s3Prefix"`
+		TopicHistoryPath *string `json:"topicHistoryPath"`
+		TopicBacklogPath *string `json:"topicBacklogPath"`
+		Shows            any     `json:"shows"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return cfg, fmt.Errorf("parse show %s: %w", name, err)
+	}
+	if set.Shows != nil {
+		return cfg, fmt.Errorf("show %s: shows cannot be nested", name)
+	}
+	cfg.Shows = shows
+	cfg.Show = name
+	cfg.showNamespace = showNamespace{
+		outDir:           set.OutDir != nil,
+		s3Prefix:         set.S3Prefix != nil,
+		topicHistoryPath: set.TopicHistoryPath != nil,
+		topicBacklogPath: set.TopicBacklogPath != nil,
+	}
+	return cfg, nil
+}
+
+// applyShowNamespace gives the selected show its own output subtree, S3
+// prefix (and so its own latest/ pointers and S3 topic history), and local
+// topic history file, unless the show entry set them explicitly.
+func applyShowNamespace(cfg Config) Config {
+	if cfg.Show == "" {
+		return cfg
+	}
+	if !cfg.showNamespace.outDir {
+		base := cfg.OutDir
+		if base == "" {
+			base = defaultOutDir
+		}
+		cfg.OutDir = filepath.Join(base, cfg.Show)
+	}
+	if !cfg.showNamespace.s3Prefix {
+		prefix := strings.Trim(cfg.S3Prefix, "/")
+		if prefix == "" {
+			cfg.S3Prefix = cfg.Show
+		} else {
+			cfg.S3Prefix = path.Join(prefix, cfg.Show)
+		}
+	}
+	if !cfg.showNamespace.topicHistoryPath && strings.TrimSpace(cfg.TopicHistoryPath) != "" {
+		cfg.TopicHistoryPath = filepath.Join(filepath.Dir(cfg.TopicHistoryPath), cfg.Show, filepath.Base(cfg.TopicHistoryPath))
+	}
+	if !cfg.showNamespace.topicBacklogPath && strings.TrimSpace(cfg.TopicBacklogPath) != "" {
+		cfg.TopicBacklogPath = filepath.Join(filepath.Dir(cfg.TopicBacklogPath), cfg.Show, filepath.Base(cfg.TopicBacklogPath))
+	}
+	return cfg
+}
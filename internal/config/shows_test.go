@@ -0,0 +1,85 @@
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func showsConfig(t *testing.T, body string) Config {
+	t.Helper()
+	cfg := Default()
+	if err := json.Unmarshal([]byte(body), &cfg); err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	return cfg
+}
+
+func TestSelectShowNamespacesOutputs(t *testing.T) {
+	file := showsConfig(t, `{
+		"voice": "alloy",
+		"shows": {
+			"kids": {"showName": "Curious World Podcast"},
+			"teens": {"voice": "nova", "ageRange": "10–12", "s3Prefix": "big-questions"}
+		}
+	}`)
+
+	kids, err := SelectShow(file, "kids")
+	if err != nil {
+		t.Fatalf("SelectShow kids: %v", err)
+	}
+	cfg := Merge(kids, Overrides{}, Overrides{}, "", "")
+	if cfg.Show != "kids" || cfg.Voice != "alloy" {
+		t.Fatalf("unexpected kids config: %+v", cfg)
+	}
+	if cfg.OutDir != filepath.Join("out", "kids") {
+		t.Fatalf("unexpected out dir: %s", cfg.OutDir)
+	}
+	if cfg.S3Prefix != "yodex/kids" {
+		t.Fatalf("unexpected prefix: %s", cfg.S3Prefix)
+	}
+	if cfg.TopicHistoryPath != filepath.Join("out", "kids", "topic-history.json") {
+		t.Fatalf("unexpected topic history path: %s", cfg.TopicHistoryPath)
+	}
+	if cfg.TopicBacklogPath != filepath.Join("out", "kids", "topic-backlog.json") {
+		t.Fatalf("unexpected topic backlog path: %s", cfg.TopicBacklogPath)
+	}
+
+	teens, err := SelectShow(file, "teens")
+	if err != nil {
+		t.Fatalf("SelectShow teens: %v", err)
+	}
+	cfg = Merge(teens, Overrides{}, Overrides{}, "", "")
+	if cfg.Voice != "nova" || cfg.AgeRange != "10–12" {
+		t.Fatalf("expected show overrides, got %+v", cfg)
+	}
+	if cfg.S3Prefix != "big-questions" {
+		t.Fatalf("expected explicit show prefix to be kept, got %s", cfg.S3Prefix)
+	}
+
+	env := Overrides{Voice: strPtr("env-voice"), S3Prefix: strPtr("shared")}
+	teens, _ = SelectShow(file, "teens")
+	kids, _ = SelectShow(file, "kids")
+	if cfg := Merge(teens, env, Overrides{}, "", ""); cfg.Voice != "env-voice" {
+		t.Fatalf("expected env to override show, got %s", cfg.Voice)
+	}
+	if cfg := Merge(kids, env, Overrides{}, "", ""); cfg.S3Prefix != "shared/kids" {
+		t.Fatalf("expected env prefix to be namespaced, got %s", cfg.S3Prefix)
+	}
+}
+
+func TestSelectShowErrors(t *testing.T) {
+	file := showsConfig(t, `{"shows": {"kids": {}, "bad": {"shows": {}}}}`)
+	for _, name := range []string{"teens", "../kids", "bad"} {
+		if _, err := SelectShow(file, name); err == nil {
+			t.Fatalf("expected error for show %q", name)
+		}
+	}
+	cfg, err := SelectShow(file, "")
+	if err != nil || cfg.Show != "" {
+		t.Fatalf("expected empty name to keep top-level config, got %+v, %v", cfg, err)
+	}
+	if got := ShowNames(file); len(got) != 2 || got[0] != "bad" || got[1] != "kids" {
+		t.Fatalf("unexpected show names: %v", got)
+	}
+}
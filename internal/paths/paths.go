@@ -9,12 +9,15 @@ import (
 )
 
 const (
-	defaultBaseDir         = "out"
-	defaultEpisodeFilename = "episode.md"
-	defaultMP3Filename     = "episode.mp3"
-	defaultMetaFilename    = "meta.json"
-	defaultSectionExt      = ".md"
-	defaultSectionMP3Ext   = ".mp3"
+	defaultBaseDir           = "out"
+	defaultEpisodeFilename   = "episode.md"
+	defaultMP3Filename       = "episode.mp3"
+	defaultMetaFilename      = "meta.json"
+	defaultRawJSONFilename   = "episode.raw.json"
+	defaultReviewFilename    = "review.json"
+	defaultFactCheckFilename = "factcheck.json"
+	defaultSectionExt        = ".md"
+	defaultSectionMP3Ext     = ".mp3"
 )
 
 // Builder constructs output paths rooted at Base (default "out").
@@ -29,9 +32,10 @@ func New(base string) *Builder {
 	return &Builder{Base: base}
 }
 
-// OutDir returns the date-based output directory: Base/YYYY/MM/DD
+// OutDir returns the date-based output directory: Base/YYYY/MM/DD. The
+// calendar day is taken in t's location (the episode timezone).
 func (b *Builder) OutDir(t time.Time) string {
-	y, m, d := t.UTC().Date()
+	y, m, d := t.Date()
 	return filepath.Join(b.Base, fmt.Sprintf("%04d", y), fmt.Sprintf("%02d", int(m)), fmt.Sprintf("%02d", d))
 }
 
@@ -45,6 +49,18 @@ func (b *Builder) EpisodeMeta(t time.Time) string {
 	return filepath.Join(b.OutDir(t), defaultMetaFilename)
 }
 
+func (b *Builder) EpisodeRawJSON(t time.Time) string {
+	return filepath.Join(b.OutDir(t), defaultRawJSONFilename)
+}
+
+func (b *Builder) EpisodeReview(t time.Time) string {
+	return filepath.Join(b.OutDir(t), defaultReviewFilename)
+}
+
+func (b *Builder) EpisodeFactCheck(t time.Time) string {
+	return filepath.Join(b.OutDir(t), defaultFactCheckFilename)
+}
+
 func (b *Builder) EpisodeSectionMarkdown(t time.Time, section string) string {
 	return filepath.Join(b.OutDir(t), section+defaultSectionExt)
 }
@@ -25,6 +25,12 @@ func TestOutPaths(t *testing.T) {
 	if b.EpisodeMeta(ts) != filepath.Join(wantDir, "meta.json") {
 		t.Fatalf("EpisodeMeta path incorrect")
 	}
+	if b.EpisodeRawJSON(ts) != filepath.Join(wantDir, "episode.raw.json") {
+		t.Fatalf("EpisodeRawJSON path incorrect")
+	}
+	if b.EpisodeFactCheck(ts) != filepath.Join(wantDir, "factcheck.json") {
+		t.Fatalf("EpisodeFactCheck path incorrect")
+	}
 	if b.EpisodeSectionMarkdown(ts, "intro") != filepath.Join(wantDir, "intro.md") {
 		t.Fatalf("EpisodeSectionMarkdown path incorrect")
 	}
@@ -33,6 +39,16 @@ func TestOutPaths(t *testing.T) {
 	}
 }
 
+func TestOutDirUsesLocalDay(t *testing.T) {
+	b := New("out")
+	// 6pm Pacific on September 30 is already October 1 in UTC.
+	pacific := time.FixedZone("PDT", -7*60*60)
+	ts := time.Date(2025, 9, 30, 18, 0, 0, 0, pacific)
+	if got, want := b.OutDir(ts), filepath.Join("out", "2025", "09", "30"); got != want {
+		t.Fatalf("OutDir: got %q want %q", got, want)
+	}
+}
+
 func TestEnsureOutDirAndOverwrite(t *testing.T) {
 	base := t.TempDir()
 	b := New(base)
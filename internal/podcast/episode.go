@@ -13,7 +13,15 @@ type Episode struct {
 	Sections []EpisodeSection `json:"sections"`
 }
 
+// Validate checks the episode against the default show.
 func (e Episode) Validate() error {
+	return e.ValidateSections(StandardSectionIDs())
+}
+
+// ValidateSections checks that the episode has a title, that every section
+// has text, that no section ID repeats, and that every required section ID
+// is present.
+func (e Episode) ValidateSections(required []string) error {
 	if strings.TrimSpace(e.Title) == "" {
 		return errors.New("title is required")
 	}
@@ -28,18 +36,37 @@ func (e Episode) Validate() error {
 		if strings.TrimSpace(section.Text) == "" {
 			return fmt.Errorf("section %q is empty", section.SectionID)
 		}
+		if seen[section.SectionID] {
+			return fmt.Errorf("duplicate section: %s", section.SectionID)
+		}
 		seen[section.SectionID] = true
 	}
-	for _, required := range standardSectionIDs {
-		if !seen[required] {
-			return fmt.Errorf("missing required section: %s", required)
+	for _, id := range required {
+		if !seen[id] {
+			return fmt.Errorf("missing required section: %s", id)
 		}
 	}
 	return nil
 }
 
-// EpisodeSchema returns the JSON schema used for structured output.
+// EpisodeSchema returns the JSON schema used for structured output of the
+// default show.
 func EpisodeSchema() map[string]any {
+	return EpisodeSchemaForSections(StandardSectionIDs())
+}
+
+// EpisodeSchemaForSections returns the structured output schema with
+// section_id limited to the given IDs. Every object sets additionalProperties
+// to false and lists all properties as required so the schema can be used in
+// strict mode.
+func EpisodeSchemaForSections(sectionIDs []string) map[string]any {
+	stringList := func(description string) map[string]any {
+		return map[string]any{
+			"type":        "array",
+			"description": description,
+			"items":       map[string]any{"type": "string"},
+		}
+	}
 	return map[string]any{
 		"type":                 "object",
 		"additionalProperties": false,
@@ -51,18 +78,23 @@ func EpisodeSchema() map[string]any {
 			"sections": map[string]any{
 				"type": "array",
 				"items": map[string]any{
-					"type": "object",
+					"type":                 "object",
+					"additionalProperties": false,
 					"properties": map[string]any{
 						"section_id": map[string]any{
 							"type":        "string",
 							"description": "Section identifier",
+							"enum":        sectionIDs,
 						},
 						"text": map[string]any{
 							"type":        "string",
 							"description": "Section content",
 						},
+						"key_facts":  stringList("Discrete facts taught in this section"),
+						"vocabulary": stringList("New or tricky words introduced in this section"),
+						"questions":  stringList("Questions asked to the listener in this section"),
 					},
-					"required": []string{"section_id", "text"},
+					"required": []string{"section_id", "text", "key_facts", "vocabulary", "questions"},
 				},
 			},
 		},
@@ -0,0 +1,270 @@
+package podcast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"yodex/internal/ai"
+)
+
+// Claim is a discrete factual statement extracted from a section.
+type Claim struct {
+	SectionID string `json:"section_id"`
+	Claim     string `json:"claim"`
+}
+
+// Fact-check verdicts.
+const (
+	VerdictAccurate   = "accurate"
+	VerdictInaccurate = "inaccurate"
+	VerdictUncertain  = "uncertain"
+)
+
+// ClaimAssessment is the fact-check result for one claim. Confidence is the
+// reviewer's confidence (0-1) that the claim is accurate as stated.
+type ClaimAssessment struct {
+	SectionID  string  `json:"section_id"`
+	Claim      string  `json:"claim"`
+	Verdict    string  `json:"verdict"`
+	Confidence float64 `json:"confidence"`
+	Note       string  `json:"note,omitempty"`
+}
+
+// FactCheckReport holds every assessed claim for an episode.
+type FactCheckReport struct {
+	Claims []ClaimAssessment `json:"claims"`
+}
+
+// LowConfidence returns claims whose confidence is below minConfidence or whose
+// verdict is inaccurate.
+func (r FactCheckReport) LowConfidence(minConfidence float64) []ClaimAssessment {
+	var out []ClaimAssessment
+	for _, c := range r.Claims {
+		if c.Verdict == VerdictInaccurate || c.Confidence < minConfidence {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// LowConfidenceSections returns the section IDs with low-confidence claims, in claim order.
+func (r FactCheckReport) LowConfidenceSections(minConfidence float64) []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, c := range r.LowConfidence(minConfidence) {
+		if seen[c.SectionID] {
+			continue
+		}
+		seen[c.SectionID] = true
+		ids = append(ids, c.SectionID)
+	}
+	return ids
+}
+
+// RevisionNotes summarizes a section's low-confidence claims as instructions for regeneration.
+func (r FactCheckReport) RevisionNotes(sectionID string, minConfidence float64) string {
+	var b strings.Builder
+	for _, c := range r.LowConfidence(minConfidence) {
+		if c.SectionID != sectionID {
+			continue
+		}
+		fmt.Fprintf(&b, "- %q (%s, confidence %.2f)", c.Claim, c.Verdict, c.Confidence)
+		if note := strings.TrimSpace(c.Note); note != "" {
+			b.WriteString(": ")
+			b.WriteString(note)
+		}
+		b.WriteString("\n")
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "A fact-checker could not confirm these claims; correct them or replace them with well-established facts:\n" + strings.TrimSpace(b.String())
+}
+
+// ClaimsSchema returns the JSON schema for claim extraction.
+func ClaimsSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"claims": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":                 "object",
+					"additionalProperties": false,
+					"properties": map[string]any{
+						"section_id": map[string]any{
+							"type":        "string",
+							"description": "Section the claim appears in",
+						},
+						"claim": map[string]any{
+							"type":        "string",
+							"description": "One self-contained factual statement",
+						},
+					},
+					"required": []string{"section_id", "claim"},
+				},
+			},
+		},
+		"required": []string{"claims"},
+	}
+}
+
+// ClaimAssessmentSchema returns the JSON schema for claim assessment.
+func ClaimAssessmentSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"assessments": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":                 "object",
+					"additionalProperties": false,
+					"properties": map[string]any{
+						"index": map[string]any{
+							"type":        "integer",
+							"description": "Number of the claim being assessed",
+						},
+						"verdict": map[string]any{
+							"type": "string",
+							"enum": []string{VerdictAccurate, VerdictInaccurate, VerdictUncertain},
+						},
+						"confidence": map[string]any{
+							"type":        "number",
+							"description": "Confidence from 0 to 1 that the claim is accurate as stated",
+						},
+						"note": map[string]any{
+							"type":        "string",
+							"description": "Short correction or caveat, or empty",
+						},
+					},
+					"required": []string{"index", "verdict", "confidence", "note"},
+				},
+			},
+		},
+		"required": []string{"assessments"},
+	}
+}
+
+const claimExtractionSystemPrompt = "You extract factual claims from scripts for a kids' science podcast. " +
+	"You list checkable statements of fact and skip opinions, greetings, questions, and imaginative play."
+
+const claimAssessmentSystemPrompt = "You are a meticulous science fact-checker for a kids' podcast. " +
+	"You judge whether each claim is accurate as stated, using well-established scientific consensus. " +
+	"Simplifications suitable for children are fine; statements that are false or misleading are not."
+
+// BuildClaimExtractionPrompt builds the system and user prompt for claim extraction.
+func BuildClaimExtractionPrompt(episode Episode) (string, string) {
+	var b strings.Builder
+	b.WriteString("List every discrete factual claim in the podcast sections below. ")
+	b.WriteString("Rewrite each claim as one short, self-contained sentence that can be checked on its own. ")
+	b.WriteString("Include surprising facts, numbers, and comparisons. ")
+	b.WriteString("For Fact or Fib games, extract each statement together with its reveal as the claim being made, for example \"It is a fib that bats are blind\" or \"It is a fact that octopuses have three hearts\". ")
+	b.WriteString("Ignore bracketed audio tags such as [short pause].\n")
+	for _, section := range episode.Sections {
+		fmt.Fprintf(&b, "\nSection ID: %s\n%s\n", section.SectionID, strings.TrimSpace(section.Text))
+	}
+	return claimExtractionSystemPrompt, strings.TrimSpace(b.String())
+}
+
+// BuildClaimAssessmentPrompt builds the system and user prompt for claim assessment.
+func BuildClaimAssessmentPrompt(topic string, claims []Claim) (string, string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Fact-check these claims from a kids' science episode about %q. ", strings.TrimSpace(topic))
+	b.WriteString("Assess every claim by its number. Give a verdict, a confidence from 0 to 1 that the claim is accurate as stated, and a short note with a correction when needed.\n\n")
+	for i, claim := range claims {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, strings.TrimSpace(claim.Claim))
+	}
+	return claimAssessmentSystemPrompt, strings.TrimSpace(b.String())
+}
+
+// ExtractClaims asks the model to list the factual claims in each section.
+func ExtractClaims(ctx context.Context, gen JSONGenerator, model string, episode Episode) ([]Claim, ai.TokenUsage, error) {
+	if gen == nil {
+		return nil, ai.TokenUsage{}, errors.New("ai client is required for fact checking")
+	}
+	system, user := BuildClaimExtractionPrompt(episode)
+	raw, usage, err := gen.GenerateJSONWithUsage(ctx, model, system, user, "claims", ClaimsSchema())
+	if err != nil {
+		return nil, ai.TokenUsage{}, err
+	}
+	var parsed struct {
+		Claims []Claim `json:"claims"`
+	}
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil, usage, fmt.Errorf("parse claims: %w", err)
+	}
+	known := make(map[string]bool, len(episode.Sections))
+	for _, section := range episode.Sections {
+		known[section.SectionID] = true
+	}
+	claims := make([]Claim, 0, len(parsed.Claims))
+	for _, c := range parsed.Claims {
+		if !known[c.SectionID] || strings.TrimSpace(c.Claim) == "" {
+			continue
+		}
+		claims = append(claims, c)
+	}
+	return claims, usage, nil
+}
+
+// AssessClaims asks the model to rate each claim. Claims the model skips are
+// reported as uncertain with zero confidence.
+func AssessClaims(ctx context.Context, gen JSONGenerator, model, topic string, claims []Claim) ([]ClaimAssessment, ai.TokenUsage, error) {
+	if len(claims) == 0 {
+		return nil, ai.TokenUsage{}, nil
+	}
+	system, user := BuildClaimAssessmentPrompt(topic, claims)
+	raw, usage, err := gen.GenerateJSONWithUsage(ctx, model, system, user, "claim_assessments", ClaimAssessmentSchema())
+	if err != nil {
+		return nil, ai.TokenUsage{}, err
+	}
+	var parsed struct {
+		Assessments []struct {
+			Index      int     `json:"index"`
+			Verdict    string  `json:"verdict"`
+			Confidence float64 `json:"confidence"`
+			Note       string  `json:"note"`
+		} `json:"assessments"`
+	}
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil, usage, fmt.Errorf("parse claim assessments: %w", err)
+	}
+	assessments := make([]ClaimAssessment, len(claims))
+	for i, claim := range claims {
+		assessments[i] = ClaimAssessment{
+			SectionID: claim.SectionID,
+			Claim:     claim.Claim,
+			Verdict:   VerdictUncertain,
+			Note:      "not assessed",
+		}
+	}
+	for _, a := range parsed.Assessments {
+		i := a.Index - 1
+		if i < 0 || i >= len(claims) {
+			continue
+		}
+		assessments[i].Verdict = a.Verdict
+		assessments[i].Confidence = a.Confidence
+		assessments[i].Note = strings.TrimSpace(a.Note)
+	}
+	return assessments, usage, nil
+}
+
+// FactCheckEpisode extracts claims from every section and assesses each one.
+func FactCheckEpisode(ctx context.Context, gen JSONGenerator, model, topic string, episode Episode) (FactCheckReport, ai.TokenUsage, error) {
+	claims, usage, err := ExtractClaims(ctx, gen, model, episode)
+	if err != nil {
+		return FactCheckReport{}, usage, err
+	}
+	assessments, assessUsage, err := AssessClaims(ctx, gen, model, topic, claims)
+	usage = usage.Add(assessUsage)
+	if err != nil {
+		return FactCheckReport{}, usage, err
+	}
+	return FactCheckReport{Claims: assessments}, usage, nil
+}
@@ -0,0 +1,85 @@
+package podcast
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"yodex/internal/ai"
+)
+
+type scriptedJSONGen struct {
+	raws    []string
+	prompts []string
+}
+
+func (f *scriptedJSONGen) GenerateJSONWithUsage(ctx context.Context, model, system, prompt, schemaName string, schema map[string]any) (string, ai.TokenUsage, error) {
+	f.prompts = append(f.prompts, prompt)
+	raw := f.raws[0]
+	f.raws = f.raws[1:]
+	return raw, ai.TokenUsage{TotalTokens: 5}, nil
+}
+
+func TestFactCheckEpisode(t *testing.T) {
+	episode := Episode{Title: "Bats", Sections: []EpisodeSection{
+		{SectionID: "topic", Text: "Bats use echolocation to find bugs."},
+		{SectionID: "game", Text: "Fact or fib: bats are blind. [short pause] That's a fib!"},
+	}}
+	gen := &scriptedJSONGen{raws: []string{
+		`{"claims":[
+			{"section_id":"topic","claim":"Bats use echolocation to find insects."},
+			{"section_id":"game","claim":"It is a fib that bats are blind."},
+			{"section_id":"game","claim":"Bats can see ultraviolet light."},
+			{"section_id":"unknown","claim":"ignored"}
+		]}`,
+		`{"assessments":[
+			{"index":1,"verdict":"accurate","confidence":0.95,"note":""},
+			{"index":2,"verdict":"accurate","confidence":0.9,"note":""},
+			{"index":9,"verdict":"inaccurate","confidence":0.1,"note":"out of range"}
+		]}`,
+	}}
+	report, usage, err := FactCheckEpisode(context.Background(), gen, "m", "Bats", episode)
+	if err != nil {
+		t.Fatalf("FactCheckEpisode: %v", err)
+	}
+	if usage.TotalTokens != 10 {
+		t.Fatalf("expected usage from both calls, got %+v", usage)
+	}
+	if len(report.Claims) != 3 {
+		t.Fatalf("expected 3 claims, got %+v", report.Claims)
+	}
+	if !strings.Contains(gen.prompts[0], "Fact or Fib") || !strings.Contains(gen.prompts[0], "Section ID: game") {
+		t.Fatalf("extraction prompt missing reveal guidance or sections: %q", gen.prompts[0])
+	}
+	if !strings.Contains(gen.prompts[1], "3. Bats can see ultraviolet light.") {
+		t.Fatalf("assessment prompt missing numbered claims: %q", gen.prompts[1])
+	}
+	skipped := report.Claims[2]
+	if skipped.Verdict != VerdictUncertain || skipped.Confidence != 0 {
+		t.Fatalf("expected unassessed claim to be uncertain, got %+v", skipped)
+	}
+	if got := report.LowConfidenceSections(0.7); len(got) != 1 || got[0] != "game" {
+		t.Fatalf("unexpected low-confidence sections: %v", got)
+	}
+}
+
+func TestFactCheckReportRevisionNotes(t *testing.T) {
+	report := FactCheckReport{Claims: []ClaimAssessment{
+		{SectionID: "topic", Claim: "The sun is a planet.", Verdict: VerdictInaccurate, Confidence: 0.9, Note: "the sun is a star"},
+		{SectionID: "topic", Claim: "The sun is hot.", Verdict: VerdictAccurate, Confidence: 0.99},
+		{SectionID: "outro", Claim: "Mars has two moons.", Verdict: VerdictAccurate, Confidence: 0.95},
+	}}
+	if got := report.LowConfidence(0.7); len(got) != 1 {
+		t.Fatalf("expected inaccurate claim to be low confidence, got %+v", got)
+	}
+	notes := report.RevisionNotes("topic", 0.7)
+	if !strings.Contains(notes, "The sun is a planet.") || !strings.Contains(notes, "the sun is a star") {
+		t.Fatalf("unexpected notes: %q", notes)
+	}
+	if strings.Contains(notes, "The sun is hot.") {
+		t.Fatalf("notes should only include low-confidence claims: %q", notes)
+	}
+	if report.RevisionNotes("outro", 0.7) != "" {
+		t.Fatalf("expected no notes for confident section")
+	}
+}
@@ -0,0 +1,100 @@
+package podcast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitFrontMatter separates a leading front-matter block, fenced by "---"
+// lines, from the body. ok is false when text has no front-matter.
+func splitFrontMatter(text string) (front, body string, ok bool, err error) {
+	text = strings.TrimPrefix(text, "\ufeff")
+	first, rest, found := strings.Cut(text, "\n")
+	if !found || strings.TrimSpace(first) != "---" {
+		return "", text, false, nil
+	}
+	lines := strings.SplitAfter(rest, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "---" {
+			return strings.Join(lines[:i], ""), strings.Join(lines[i+1:], ""), true, nil
+		}
+	}
+	return "", "", false, fmt.Errorf("front-matter is not closed with ---")
+}
+
+// parseFrontMatter parses the small YAML subset used in front-matter: one
+// "key: value" per line, where a value is a scalar, an inline [a, b] list, or
+// a block of "- item" lines below the key. Quotes around scalars are
+// removed and # starts a comment outside quotes. Lists are returned as
+// []string and scalars as string.
+func parseFrontMatter(front string) (map[string]any, error) {
+	values := map[string]any{}
+	var listKey string
+	for n, raw := range strings.Split(front, "\n") {
+		line := strings.TrimRight(stripYAMLComment(raw), " \t\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if item, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok && line != strings.TrimLeft(line, " \t") {
+			if listKey == "" {
+				return nil, fmt.Errorf("line %d: list item without a key", n+1)
+			}
+			values[listKey] = append(values[listKey].([]string), unquoteYAML(item))
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" || line != strings.TrimLeft(line, " \t") {
+			return nil, fmt.Errorf("line %d: expected key: value", n+1)
+		}
+		if _, dup := values[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %s", n+1, key)
+		}
+		value = strings.TrimSpace(value)
+		listKey = ""
+		switch {
+		case value == "":
+			values[key] = []string{}
+			listKey = key
+		case strings.HasPrefix(value, "["):
+			if !strings.HasSuffix(value, "]") {
+				return nil, fmt.Errorf("line %d: unclosed list for %s", n+1, key)
+			}
+			items := []string{}
+			if inner := strings.TrimSpace(value[1 : len(value)-1]); inner != "" {
+				for _, item := range strings.Split(inner, ",") {
+					items = append(items, unquoteYAML(item))
+				}
+			}
+			values[key] = items
+		default:
+			values[key] = unquoteYAML(value)
+		}
+	}
+	return values, nil
+}
+
+func stripYAMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquoteYAML(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
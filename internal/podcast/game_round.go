@@ -0,0 +1,76 @@
+package podcast
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pauseTagPattern matches the audio tags that give the listener time to answer.
+var pauseTagPattern = regexp.MustCompile(`\[(short|long) pause\]`)
+
+// apostropheReplacer folds typographic apostrophes so phrase checks accept
+// either form.
+var apostropheReplacer = strings.NewReplacer("’", "'", "‘", "'")
+
+// ValidateRound checks a generated round against the game's metadata and
+// returns one problem per failed check, worded as feedback for the model.
+// Every required phrase must appear. When the game has an ItemPattern, the
+// item count must be within MinItems and MaxItems, and each item must have
+// exactly one pause, ask its QuestionPattern once, and, for games with
+// answers, include its AnswerPattern.
+func (g GameRules) ValidateRound(text string) []string {
+	var problems []string
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return []string{"The round is empty."}
+	}
+	folded := apostropheReplacer.Replace(text)
+	for _, phrase := range g.RequiredPhrases {
+		if !strings.Contains(folded, apostropheReplacer.Replace(phrase)) {
+			problems = append(problems, fmt.Sprintf("Say %q word for word.", phrase))
+		}
+	}
+	if g.ItemPattern == nil {
+		return problems
+	}
+
+	starts := g.ItemPattern.FindAllStringIndex(folded, -1)
+	if count := len(starts); (g.MinItems > 0 && count < g.MinItems) || (g.MaxItems > 0 && count > g.MaxItems) {
+		problems = append(problems, fmt.Sprintf("The round has %d items. %s", count, strings.TrimPrefix(g.itemCountRequirement(), "- ")))
+	}
+	for i, start := range starts {
+		end := len(folded)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		item := folded[start[0]:end]
+		label := fmt.Sprintf("Item %d (%q)", i+1, folded[start[0]:start[1]])
+		if pauses := len(pauseTagPattern.FindAllStringIndex(item, -1)); pauses != 1 {
+			problems = append(problems, fmt.Sprintf("%s has %d pauses; use exactly one [short pause] or [long pause], right after its question.", label, pauses))
+		}
+		if g.QuestionPattern != nil {
+			if asks := len(g.QuestionPattern.FindAllStringIndex(item, -1)); asks != 1 {
+				problems = append(problems, fmt.Sprintf("%s asks its decision question %d times; ask it exactly once in the fixed format.", label, asks))
+			}
+		}
+		if g.HasAnswers && g.AnswerPattern != nil && !g.AnswerPattern.MatchString(item) {
+			problems = append(problems, fmt.Sprintf("%s never reveals the answer in the fixed format.", label))
+		}
+	}
+	return problems
+}
+
+// GameRevisionNotes turns validation problems into revision instructions for
+// regenerating a round, including the rejected round for reference.
+func GameRevisionNotes(problems []string, text string) string {
+	var b strings.Builder
+	b.WriteString("The previous round did not follow the game's format. Write a new round that fixes every problem below and keeps the fixed item format:\n")
+	for _, problem := range problems {
+		b.WriteString("- " + problem + "\n")
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		b.WriteString("\nPrevious round:\n" + text)
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,104 @@
+package podcast
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func factOrFibRound(items int) string {
+	ordinals := []string{"first", "second", "third", "fourth", "fifth", "sixth"}
+	var b strings.Builder
+	b.WriteString("[excited] Let's play! ")
+	for i := 0; i < items; i++ {
+		b.WriteString("Here’s the " + ordinals[i] + " statement: Owls can turn their heads very far. ")
+		b.WriteString("Fact or fib? [short pause] That statement was a... Fact! Owls have extra neck bones. ")
+	}
+	return b.String()
+}
+
+func TestValidateRound(t *testing.T) {
+	game, err := ParseGameRules("fact-or-fib", "---\n"+
+		"minItems: 5\nmaxItems: 5\n"+
+		"requiredPhrases: [\"Fact or fib?\"]\n"+
+		"itemPattern: \"Here's the \\w+ statement:\"\n"+
+		"questionPattern: \"Fact or fib\\? *\\[short pause\\]\"\n"+
+		"answerPattern: \"(?i)that statement was an?\\b.{0,12}\\b(fact|fib)\\b\"\n"+
+		"---\nRules.")
+	if err != nil {
+		t.Fatalf("ParseGameRules: %v", err)
+	}
+	if problems := game.ValidateRound(factOrFibRound(5)); len(problems) != 0 {
+		t.Fatalf("expected a valid round, got %q", problems)
+	}
+
+	bad := strings.Replace(factOrFibRound(4), "[short pause] That statement was a... Fact!", "[short pause] Fact or fib? [short pause] Nice!", 1)
+	problems := game.ValidateRound(bad)
+	joined := strings.Join(problems, "\n")
+	for _, want := range []string{
+		"The round has 4 items. Use exactly 5 items.",
+		`Item 1 ("Here's the first statement:") has 2 pauses`,
+		`Item 1 ("Here's the first statement:") asks its decision question 2 times`,
+		`Item 1 ("Here's the first statement:") never reveals the answer`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected %q in problems, got %q", want, problems)
+		}
+	}
+	if len(problems) != 4 {
+		t.Fatalf("expected only item 1 and the count to fail, got %q", problems)
+	}
+
+	if problems := game.ValidateRound("Let's play a game! [short pause]"); len(problems) != 2 {
+		t.Fatalf("expected the missing phrase and items, got %q", problems)
+	}
+	if problems := (GameRules{Name: "free"}).ValidateRound("Anything goes."); len(problems) != 0 {
+		t.Fatalf("expected no checks without metadata, got %q", problems)
+	}
+
+	notes := GameRevisionNotes(problems, "Old round.")
+	if !strings.Contains(notes, "Previous round:\nOld round.") {
+		t.Fatalf("unexpected notes: %q", notes)
+	}
+}
+
+func TestGameRulesForLocale(t *testing.T) {
+	game := GameRules{
+		Name:            "fact-or-fib",
+		Rules:           "Rules.",
+		MinItems:        5,
+		MaxItems:        5,
+		RequiredPhrases: []string{"Fact or fib?"},
+		HasAnswers:      true,
+		ItemPattern:     regexp.MustCompile(`Here's the \w+ statement:`),
+		AnswerPattern:   regexp.MustCompile(`(?i)that statement was`),
+	}
+	if english := game.ForLocale(DefaultLocale()); len(english.RequiredPhrases) != 1 || english.ItemPattern == nil {
+		t.Fatalf("expected English rules unchanged, got %+v", english)
+	}
+
+	es, err := LookupLocale("es")
+	if err != nil {
+		t.Fatalf("LookupLocale: %v", err)
+	}
+	spanish := game.ForLocale(es)
+	round := "Aquí va la primera afirmación: los búhos giran mucho la cabeza. ¿Verdad o mentira? [short pause] ¡Verdad!"
+	if problems := spanish.ValidateRound(round); len(problems) != 0 {
+		t.Fatalf("expected a Spanish round to skip the English checks, got %q", problems)
+	}
+	if problems := game.ValidateRound(round); len(problems) == 0 {
+		t.Fatalf("expected the English checks to reject the Spanish round")
+	}
+	_, user, err := BuildGamePrompt("", es, "Búhos", time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC), spanish)
+	if err != nil {
+		t.Fatalf("BuildGamePrompt: %v", err)
+	}
+	if strings.Contains(user, "word for word") || !strings.Contains(user, "- Use exactly 5 items.") {
+		t.Fatalf("expected the item count without English phrases in the prompt: %q", user)
+	}
+	quiz := "Pregunta uno, de nuestro episodio sobre volcanes: ¿qué es la lava? [short pause] ¡Roca fundida!"
+	if problems := RecapQuizGame.ForLocale(es).ValidateRound(quiz); len(problems) != 0 {
+		t.Fatalf("expected a Spanish week quiz to skip the English checks, got %q", problems)
+	}
+}
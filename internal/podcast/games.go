@@ -5,18 +5,60 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// GameRules is a brain game: its rules and the front-matter metadata of its
+// rules file.
 type GameRules struct {
-	Name  string
+	Name  string // file name without .md, recorded in meta.json and history
 	Rules string
+
+	// DisplayName is how the host names the game; empty uses Name.
+	DisplayName string
+	// Weekdays limits the days the game is scheduled on; empty allows all.
+	Weekdays []time.Weekday
+	// Weight is the game's share of the rotation among the games allowed on
+	// a day; 0 counts as 1.
+	Weight int
+	// MinItems and MaxItems bound the questions or statements in a round;
+	// 0 leaves that side open.
+	MinItems int
+	MaxItems int
+	// RequiredPhrases must appear word for word in every round.
+	RequiredPhrases []string
+	// HasAnswers says whether the game's questions have correct answers.
+	HasAnswers bool
+
+	// ItemPattern matches the start of each item in a round; ValidateRound
+	// counts and checks items only when it is set.
+	ItemPattern *regexp.Regexp
+	// QuestionPattern matches the decision question every item asks once.
+	QuestionPattern *regexp.Regexp
+	// AnswerPattern matches the answer reveal every item of a game with
+	// answers must include.
+	AnswerPattern *regexp.Regexp
+}
+
+// Title returns the name the host says aloud.
+func (g GameRules) Title() string {
+	if strings.TrimSpace(g.DisplayName) != "" {
+		return strings.TrimSpace(g.DisplayName)
+	}
+	return g.Name
 }
 
 var gameRulesDir = filepath.Join("internal", "podcast", "games")
 
+// LoadGameRules reads every .md file in the game rules directory, sorted by
+// name. A file may start with front-matter setting displayName, weekdays,
+// weight, minItems, maxItems, requiredPhrases, hasAnswers, and the
+// validation patterns itemPattern, questionPattern, and answerPattern.
 func LoadGameRules() ([]GameRules, error) {
 	entries, err := os.ReadDir(resolveGameRulesDir())
 	if err != nil {
@@ -36,14 +78,14 @@ func LoadGameRules() ([]GameRules, error) {
 		if err != nil {
 			return nil, fmt.Errorf("read game rules file %s: %w", name, err)
 		}
-		rules := strings.TrimSpace(string(data))
-		if rules == "" {
+		game, err := ParseGameRules(strings.TrimSuffix(name, filepath.Ext(name)), string(data))
+		if err != nil {
+			return nil, fmt.Errorf("game rules file %s: %w", name, err)
+		}
+		if game.Rules == "" {
 			continue
 		}
-		games = append(games, GameRules{
-			Name:  strings.TrimSuffix(name, filepath.Ext(name)),
-			Rules: rules,
-		})
+		games = append(games, game)
 	}
 	if len(games) == 0 {
 		return nil, errors.New("no game rules found")
@@ -54,6 +96,121 @@ func LoadGameRules() ([]GameRules, error) {
 	return games, nil
 }
 
+// ParseGameRules parses a game rules file: optional front-matter followed by
+// the rules text. Without front-matter the game has answers and no limits.
+func ParseGameRules(name, text string) (GameRules, error) {
+	game := GameRules{Name: name, HasAnswers: true}
+	front, body, ok, err := splitFrontMatter(text)
+	if err != nil {
+		return GameRules{}, err
+	}
+	game.Rules = strings.TrimSpace(body)
+	if !ok {
+		return game, nil
+	}
+	values, err := parseFrontMatter(front)
+	if err != nil {
+		return GameRules{}, fmt.Errorf("front-matter: %w", err)
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := game.setMeta(key, values[key]); err != nil {
+			return GameRules{}, fmt.Errorf("front-matter %s: %w", key, err)
+		}
+	}
+	if game.MaxItems > 0 && game.MinItems > game.MaxItems {
+		return GameRules{}, fmt.Errorf("front-matter: minItems %d is more than maxItems %d", game.MinItems, game.MaxItems)
+	}
+	if game.ItemPattern == nil && (game.QuestionPattern != nil || game.AnswerPattern != nil) {
+		return GameRules{}, errors.New("front-matter: questionPattern and answerPattern need an itemPattern")
+	}
+	return game, nil
+}
+
+func (g *GameRules) setMeta(key string, value any) error {
+	list, isList := value.([]string)
+	scalar, _ := value.(string)
+	if isList != (key == "weekdays" || key == "requiredPhrases") {
+		if isList {
+			return errors.New("expected a single value")
+		}
+		return errors.New("expected a list")
+	}
+	switch key {
+	case "displayName":
+		g.DisplayName = scalar
+	case "weekdays":
+		g.Weekdays = nil
+		for _, item := range list {
+			day, ok := parseGameWeekday(item)
+			if !ok {
+				return fmt.Errorf("unknown weekday %q", item)
+			}
+			g.Weekdays = append(g.Weekdays, day)
+		}
+	case "weight":
+		n, err := strconv.Atoi(scalar)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid weight %q (expected 1 or more)", scalar)
+		}
+		g.Weight = n
+	case "minItems", "maxItems":
+		n, err := strconv.Atoi(scalar)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid count %q (expected 0 or more)", scalar)
+		}
+		if key == "minItems" {
+			g.MinItems = n
+		} else {
+			g.MaxItems = n
+		}
+	case "requiredPhrases":
+		g.RequiredPhrases = nil
+		for _, item := range list {
+			if item != "" {
+				g.RequiredPhrases = append(g.RequiredPhrases, item)
+			}
+		}
+	case "hasAnswers":
+		b, err := strconv.ParseBool(scalar)
+		if err != nil {
+			return fmt.Errorf("invalid value %q (expected true or false)", scalar)
+		}
+		g.HasAnswers = b
+	case "itemPattern", "questionPattern", "answerPattern":
+		re, err := regexp.Compile(scalar)
+		if err != nil || scalar == "" {
+			return fmt.Errorf("invalid pattern %q", scalar)
+		}
+		switch key {
+		case "itemPattern":
+			g.ItemPattern = re
+		case "questionPattern":
+			g.QuestionPattern = re
+		default:
+			g.AnswerPattern = re
+		}
+	default:
+		return errors.New("unknown key")
+	}
+	return nil
+}
+
+func parseGameWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || (len(name) >= 3 && strings.HasPrefix(full, name)) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
 func resolveGameRulesDir() string {
 	if v := strings.TrimSpace(os.Getenv("YODEX_GAME_RULES_DIR")); v != "" {
 		return v
@@ -61,31 +218,82 @@ func resolveGameRulesDir() string {
 	return gameRulesDir
 }
 
-func ChooseGame(date time.Time, games []GameRules) (GameRules, error) {
+// ChooseGame picks the game for the episode date from the games allowed on
+// its weekday (in the date's own location), skipping previous, the game of
+// the last episode, when another game is allowed. Games rotate by weekday in
+// proportion to their weight.
+func ChooseGame(date time.Time, games []GameRules, previous string) (GameRules, error) {
 	if len(games) == 0 {
 		return GameRules{}, errors.New("no games available")
 	}
-	weekday := date.UTC().Weekday()
-	if name, ok := weekdayGameMap()[weekday]; ok {
-		for _, game := range games {
-			if game.Name == name {
-				return game, nil
-			}
+	weekday := date.Weekday()
+	var allowed []GameRules
+	for _, game := range games {
+		if len(game.Weekdays) == 0 || slices.Contains(game.Weekdays, weekday) {
+			allowed = append(allowed, game)
 		}
 	}
-	index := int(weekday) % len(games)
-	return games[index], nil
+	if len(allowed) == 0 {
+		return GameRules{}, fmt.Errorf("no game is scheduled on %s", weekday)
+	}
+	if len(allowed) > 1 && previous != "" {
+		allowed = slices.DeleteFunc(allowed, func(game GameRules) bool { return game.Name == previous })
+	}
+	total := 0
+	for _, game := range allowed {
+		total += game.weight()
+	}
+	slot := int(weekday) % total
+	for _, game := range allowed {
+		if slot < game.weight() {
+			return game, nil
+		}
+		slot -= game.weight()
+	}
+	return allowed[len(allowed)-1], nil
+}
+
+func (g GameRules) weight() int {
+	return max(g.Weight, 1)
 }
 
-func weekdayGameMap() map[time.Weekday]string {
-	return map[time.Weekday]string{
-		time.Sunday:   "fact-or-fib",
-		time.Tuesday:  "build-it-brainstorm",
-		time.Saturday: "build-it-brainstorm",
+// SectionGame returns the game a game section plays: the game it names, from
+// the rules directory or the built-in RecapQuizGame, or else the weekday's
+// game.
+func SectionGame(date time.Time, name string) (GameRules, error) {
+	name = strings.TrimSpace(name)
+	if name == RecapQuizGame.Name {
+		return RecapQuizGame, nil
 	}
+	games, err := LoadGameRules()
+	if err != nil {
+		return GameRules{}, err
+	}
+	if name == "" {
+		return ChooseGame(date, games, "")
+	}
+	for _, game := range games {
+		if game.Name == name {
+			return game, nil
+		}
+	}
+	return GameRules{}, fmt.Errorf("unknown game %q", name)
+}
+
+// ForLocale returns the rules for a round played in locale. Required phrases
+// and item patterns are written in English, so for any other language they
+// are dropped: the prompt no longer demands the phrases and ValidateRound
+// checks only that the round is not empty.
+func (g GameRules) ForLocale(locale Locale) GameRules {
+	if locale.Base == "" || locale.IsEnglish() {
+		return g
+	}
+	g.RequiredPhrases = nil
+	g.ItemPattern, g.QuestionPattern, g.AnswerPattern = nil, nil, nil
+	return g
 }
 
-const gameSystemPrompt = "You are a friendly, curious podcast host creating an audio-only daily game for kids ages 7–9.\n\n" +
+const defaultGameSystemPrompt = "You are a friendly, curious podcast host creating an audio-only daily game for kids ages {{.AgeRange}}.\n\n" +
 	"The following game rules will be provided. Read and follow them exactly.\n\n" +
 	"Your task:\n" +
 	"- Produce ONE complete round of the game.\n" +
@@ -118,9 +326,50 @@ const gameSystemPrompt = "You are a friendly, curious podcast host creating an a
 	"- Avoid mentioning rules explicitly during gameplay.\n" +
 	"- End the game with a positive closing line (e.g., encouragement or fun fact).\n" +
 	"- Do not say goodbye or reference the show ending; the outro handles that.\n\n" +
-	"Now generate the game round using the provided rules."
+	"Now generate the game round using the provided rules." +
+	"{{with .GameLanguageInstruction}}\n\n{{.}}{{end}}"
+
+// roundRequirements lists the limits from the game's metadata for the prompt.
+func (g GameRules) roundRequirements() string {
+	var lines []string
+	if line := g.itemCountRequirement(); line != "" {
+		lines = append(lines, line)
+	}
+	if len(g.RequiredPhrases) > 0 {
+		quoted := make([]string, 0, len(g.RequiredPhrases))
+		for _, phrase := range g.RequiredPhrases {
+			quoted = append(quoted, fmt.Sprintf("%q", phrase))
+		}
+		lines = append(lines, "- Say these phrases word for word: "+strings.Join(quoted, ", ")+".")
+	}
+	if g.HasAnswers {
+		lines = append(lines, "- Each question has a correct answer; reveal it after the pause.")
+	} else {
+		lines = append(lines, "- There are no right or wrong answers; affirm every idea.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// itemCountRequirement is the prompt line for MinItems and MaxItems; empty
+// when neither is set.
+func (g GameRules) itemCountRequirement() string {
+	switch {
+	case g.MinItems > 0 && g.MinItems == g.MaxItems:
+		return fmt.Sprintf("- Use exactly %d items.", g.MinItems)
+	case g.MinItems > 0 && g.MaxItems > 0:
+		return fmt.Sprintf("- Use between %d and %d items.", g.MinItems, g.MaxItems)
+	case g.MinItems > 0:
+		return fmt.Sprintf("- Use at least %d items.", g.MinItems)
+	case g.MaxItems > 0:
+		return fmt.Sprintf("- Use at most %d items.", g.MaxItems)
+	}
+	return ""
+}
 
-func BuildGamePrompt(topic string, date time.Time, rules GameRules) (string, string, error) {
+// BuildGamePrompt returns the system and user prompt for one game round. system
+// is the rendered game-system template (the game spec's Prompt); empty uses the
+// default prompts. locale is the language the game is played in.
+func BuildGamePrompt(system string, locale Locale, topic string, date time.Time, rules GameRules) (string, string, error) {
 	topic = strings.TrimSpace(topic)
 	if topic == "" {
 		return "", "", errors.New("topic is required")
@@ -128,15 +377,23 @@ func BuildGamePrompt(topic string, date time.Time, rules GameRules) (string, str
 	if strings.TrimSpace(rules.Rules) == "" {
 		return "", "", errors.New("game rules are required")
 	}
-	weekday := date.UTC().Weekday().String()
+	if locale.Base == "" {
+		locale = DefaultLocale()
+	}
 	user := fmt.Sprintf(
-		"Weekday: %s\nTopic: %s\nGame: %s\n\nStart the game by saying: It's %s so you know what that means! It's time to play %s.\nThen give a short, friendly summary of how the game works that makes expectations clear.\n\nGame rules:\n%s",
-		weekday,
+		"Weekday: %s\nTopic: %s\nGame: %s\n\nStart the game by saying: %s\nThen give a short, friendly summary of how the game works that makes expectations clear.\n\nGame rules:\n%s\n\nRound requirements:\n%s",
+		date.Weekday().String(),
 		topic,
-		rules.Name,
-		weekday,
-		rules.Name,
+		rules.Title(),
+		locale.GameStart(date, rules.Title()),
 		rules.Rules,
+		rules.roundRequirements(),
 	)
-	return gameSystemPrompt, user, nil
+	if strings.TrimSpace(system) == "" {
+		var err error
+		if system, err = DefaultPrompts().Render(PromptGameSystem, DefaultPrompts().Data(topic, date)); err != nil {
+			return "", "", err
+		}
+	}
+	return system, user, nil
 }
@@ -40,11 +40,11 @@ func TestChooseGameDeterministic(t *testing.T) {
 		{Name: "fact-or-fib", Rules: "fact"},
 	}
 	date := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC) // Monday
-	first, err := ChooseGame(date, games)
+	first, err := ChooseGame(date, games, "")
 	if err != nil {
 		t.Fatalf("ChooseGame: %v", err)
 	}
-	second, err := ChooseGame(date, games)
+	second, err := ChooseGame(date, games, "")
 	if err != nil {
 		t.Fatalf("ChooseGame: %v", err)
 	}
@@ -56,41 +56,116 @@ func TestChooseGameDeterministic(t *testing.T) {
 	}
 }
 
-func TestChooseGameSundayFactOrFib(t *testing.T) {
+func TestChooseGameSchedulesByWeekday(t *testing.T) {
 	games := []GameRules{
-		{Name: "a", Rules: "a"},
-		{Name: "fact-or-fib", Rules: "fact"},
-		{Name: "c", Rules: "c"},
+		{Name: "build-it-brainstorm", Rules: "build", Weekdays: []time.Weekday{time.Tuesday, time.Wednesday, time.Saturday}},
+		{Name: "fact-or-fib", Rules: "fact", Weekdays: []time.Weekday{time.Sunday, time.Monday, time.Thursday}},
+		{Name: "would-you-rather", Rules: "rather", Weekdays: []time.Weekday{time.Monday, time.Wednesday, time.Friday}},
 	}
-	date := time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC) // Sunday
-	game, err := ChooseGame(date, games)
-	if err != nil {
-		t.Fatalf("ChooseGame: %v", err)
+	sunday := time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)
+	if game, err := ChooseGame(sunday, games, ""); err != nil || game.Name != "fact-or-fib" {
+		t.Fatalf("expected fact-or-fib on Sunday, got %q, %v", game.Name, err)
 	}
-	if game.Name != "fact-or-fib" {
-		t.Fatalf("expected fact-or-fib on Sunday, got %q", game.Name)
+	tuesday := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	if game, err := ChooseGame(tuesday, games, ""); err != nil || game.Name != "build-it-brainstorm" {
+		t.Fatalf("expected build-it-brainstorm on Tuesday, got %q, %v", game.Name, err)
+	}
+	// Monday allows two games; the one played last is skipped.
+	monday := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)
+	if game, _ := ChooseGame(monday, games, "fact-or-fib"); game.Name != "would-you-rather" {
+		t.Fatalf("expected the previous game to be skipped, got %q", game.Name)
+	}
+	if game, _ := ChooseGame(monday, games, "would-you-rather"); game.Name != "fact-or-fib" {
+		t.Fatalf("expected the previous game to be skipped, got %q", game.Name)
+	}
+	// The only game allowed on a day plays even if it played last.
+	if game, _ := ChooseGame(sunday, games, "fact-or-fib"); game.Name != "fact-or-fib" {
+		t.Fatalf("expected the only Sunday game, got %q", game.Name)
 	}
 }
 
-func TestChooseGameTuesdayBuildItBrainstorm(t *testing.T) {
-	games := []GameRules{
-		{Name: "build-it-brainstorm", Rules: "build"},
-		{Name: "fact-or-fib", Rules: "fact"},
-		{Name: "would-you-rather", Rules: "rather"},
+func TestChooseGameWeights(t *testing.T) {
+	games := []GameRules{{Name: "a", Rules: "a", Weight: 5}, {Name: "b", Rules: "b"}}
+	counts := map[string]int{}
+	for day := 0; day < 7; day++ {
+		game, err := ChooseGame(time.Date(2026, 1, 18+day, 0, 0, 0, 0, time.UTC), games, "")
+		if err != nil {
+			t.Fatalf("ChooseGame: %v", err)
+		}
+		counts[game.Name]++
+	}
+	if counts["a"] <= counts["b"] {
+		t.Fatalf("expected the heavier game to play more often, got %v", counts)
 	}
-	date := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC) // Tuesday
-	game, err := ChooseGame(date, games)
+}
+
+func TestParseGameRules(t *testing.T) {
+	game, err := ParseGameRules("fact-or-fib", "---\ndisplayName: Fact or Fib # spoken\nweekdays: [sun, Monday]\nweight: 2\nminItems: 3\nmaxItems: 5\nrequiredPhrases:\n  - \"Fact or fib?\"\n  - 'That statement was a'\nhasAnswers: true\n---\n\n# Fact or Fib\nRules.\n")
 	if err != nil {
-		t.Fatalf("ChooseGame: %v", err)
+		t.Fatalf("ParseGameRules: %v", err)
+	}
+	if game.Title() != "Fact or Fib" || game.Weight != 2 || game.MinItems != 3 || game.MaxItems != 5 || !game.HasAnswers {
+		t.Fatalf("unexpected metadata: %+v", game)
+	}
+	if len(game.Weekdays) != 2 || game.Weekdays[0] != time.Sunday || game.Weekdays[1] != time.Monday {
+		t.Fatalf("unexpected weekdays: %v", game.Weekdays)
+	}
+	if strings.Join(game.RequiredPhrases, "|") != "Fact or fib?|That statement was a" {
+		t.Fatalf("unexpected required phrases: %q", game.RequiredPhrases)
+	}
+	if game.Rules != "# Fact or Fib\nRules." {
+		t.Fatalf("unexpected rules: %q", game.Rules)
 	}
-	if game.Name != "build-it-brainstorm" {
-		t.Fatalf("expected build-it-brainstorm on Tuesday, got %q", game.Name)
+
+	plain, err := ParseGameRules("mystery", "Rule")
+	if err != nil || plain.Title() != "mystery" || !plain.HasAnswers || plain.Rules != "Rule" {
+		t.Fatalf("expected a plain rules file to parse, got %+v, %v", plain, err)
+	}
+
+	for _, bad := range []string{
+		"---\nweekdays: [someday]\n---\nRule",
+		"---\nminItems: 4\nmaxItems: 2\n---\nRule",
+		"---\ncolor: blue\n---\nRule",
+		"---\nweight: 0\n---\nRule",
+		"---\nhasAnswers: maybe\n---\nRule",
+		"---\ndisplayName: Quiz\nRule",
+		"---\nitemPattern: \"Question (\"\n---\nRule",
+		"---\nanswerPattern: \"The answer is\"\n---\nRule",
+	} {
+		if _, err := ParseGameRules("bad", bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestBuiltInGameRules(t *testing.T) {
+	t.Setenv("YODEX_GAME_RULES_DIR", "games")
+	games, err := LoadGameRules()
+	if err != nil {
+		t.Fatalf("LoadGameRules: %v", err)
+	}
+	for _, game := range games {
+		if game.DisplayName == "" || len(game.Weekdays) == 0 || len(game.RequiredPhrases) == 0 || game.ItemPattern == nil || strings.HasPrefix(game.Rules, "---") {
+			t.Fatalf("expected front-matter in %s, got %+v", game.Name, game)
+		}
+	}
+	// Every day has a game, and no game plays two days running.
+	previous := ""
+	for day := 0; day < 14; day++ {
+		game, err := ChooseGame(time.Date(2026, 1, 18+day, 0, 0, 0, 0, time.UTC), games, previous)
+		if err != nil {
+			t.Fatalf("ChooseGame: %v", err)
+		}
+		if game.Name == previous {
+			t.Fatalf("day %d repeats %s", day, game.Name)
+		}
+		previous = game.Name
 	}
 }
 
 func TestBuildGamePrompt(t *testing.T) {
 	date := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC) // Monday
-	system, user, err := BuildGamePrompt("Space", date, GameRules{Name: "mystery", Rules: "Rule"})
+	system, user, err := BuildGamePrompt("", DefaultLocale(), "Space", date, GameRules{Name: "mystery", Rules: "Rule"})
 	if err != nil {
 		t.Fatalf("BuildGamePrompt: %v", err)
 	}
@@ -100,6 +175,13 @@ func TestBuildGamePrompt(t *testing.T) {
 	if !containsAll(user, []string{"Weekday: Monday", "Topic: Space", "Game: mystery", "Then give a short, friendly summary", "Game rules:\nRule"}) {
 		t.Fatalf("missing rules in prompt: %q", user)
 	}
+	_, user, err = BuildGamePrompt("", DefaultLocale(), "Space", date, GameRules{Name: "fact-or-fib", DisplayName: "Fact or Fib", Rules: "Rule", MinItems: 5, MaxItems: 5, RequiredPhrases: []string{"Fact or fib?"}, HasAnswers: true})
+	if err != nil {
+		t.Fatalf("BuildGamePrompt: %v", err)
+	}
+	if !containsAll(user, []string{"Game: Fact or Fib", "- Use exactly 5 items.", `- Say these phrases word for word: "Fact or fib?".`, "reveal it after the pause"}) || strings.Contains(user, "fact-or-fib") {
+		t.Fatalf("expected display name and round requirements in prompt: %q", user)
+	}
 	if !strings.Contains(system, "Do not stack multiple pauses for the same question.") {
 		t.Fatalf("missing pause guardrail in system prompt: %q", system)
 	}
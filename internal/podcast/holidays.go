@@ -0,0 +1,311 @@
+package podcast
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed calendars/*.json
+var builtinCalendars embed.FS
+
+// DefaultHolidayRegions are the built-in calendars used when no regions are
+// configured: U.S. holidays. The world cultural and science days are opt-in.
+var DefaultHolidayRegions = []string{"us"}
+
+// Holiday is a named day mentioned in intro and outro prompts.
+type Holiday struct {
+	Name        string
+	Description string
+}
+
+// Holiday rule kinds.
+const (
+	HolidayRuleFixed       = "fixed"        // same month and day every year ("date": "MM-DD")
+	HolidayRuleNthWeekday  = "nth-weekday"  // e.g. the third Monday of January
+	HolidayRuleLastWeekday = "last-weekday" // e.g. the last Monday of May
+	HolidayRuleEaster      = "easter"       // "offset" days from Western Easter Sunday
+	HolidayRuleDates       = "dates"        // explicit "YYYY-MM-DD" dates
+)
+
+// HolidayRule describes when a holiday falls. ID defaults to a slug of the
+// name and is the key for description overrides. Translations hold the name
+// and description in other languages, keyed by primary language subtag.
+type HolidayRule struct {
+	ID           string                 `json:"id,omitempty"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description,omitempty"`
+	Rule         string                 `json:"rule"`
+	Date         string                 `json:"date,omitempty"`
+	Month        int                    `json:"month,omitempty"`
+	Weekday      string                 `json:"weekday,omitempty"`
+	Nth          int                    `json:"nth,omitempty"`
+	Offset       int                    `json:"offset,omitempty"`
+	Dates        []string               `json:"dates,omitempty"`
+	Translations map[string]HolidayText `json:"translations,omitempty"`
+
+	month   time.Month
+	day     int
+	weekday time.Weekday
+	dates   map[string]bool
+}
+
+// HolidayText is a holiday's name and description in one language.
+type HolidayText struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// HolidayFile is the JSON format of a holiday calendar file. Note is free
+// text for maintainers, such as when its dates tables end.
+type HolidayFile struct {
+	Note     string        `json:"note,omitempty"`
+	Holidays []HolidayRule `json:"holidays"`
+}
+
+// Calendar finds the holiday on a date from a list of rules. The first
+// matching rule wins. The zero Calendar has no holidays.
+type Calendar struct {
+	rules        []HolidayRule
+	descriptions map[string]string
+}
+
+var (
+	monthDayPattern = regexp.MustCompile(`^(\d{2})-(\d{2})$`)
+	slugPattern     = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday,
+	"wednesday": time.Wednesday, "thursday": time.Thursday, "friday": time.Friday,
+	"saturday": time.Saturday,
+}
+
+// HolidayRegions returns the names of the built-in regional calendars.
+func HolidayRegions() []string {
+	entries, _ := builtinCalendars.ReadDir("calendars")
+	regions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		regions = append(regions, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// DefaultCalendar returns the calendar for DefaultHolidayRegions.
+func DefaultCalendar() Calendar {
+	cal, err := LoadCalendar(DefaultHolidayRegions, nil, nil)
+	if err != nil {
+		panic(err)
+	}
+	return cal
+}
+
+// LoadCalendar builds a calendar from holiday files (JSON rule files or .ics
+// calendars) followed by built-in regions, so a file's holiday wins over a
+// region's on the same day. descriptions replaces the description of the
+// holiday with that ID in every language.
+func LoadCalendar(regions, files []string, descriptions map[string]string) (Calendar, error) {
+	cal := Calendar{descriptions: descriptions}
+	for _, file := range files {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+		rules, err := readHolidayFile(file)
+		if err != nil {
+			return Calendar{}, err
+		}
+		cal.rules = append(cal.rules, rules...)
+	}
+	for _, region := range regions {
+		region = strings.ToLower(strings.TrimSpace(region))
+		if region == "" {
+			continue
+		}
+		data, err := builtinCalendars.ReadFile(path.Join("calendars", region+".json"))
+		if err != nil {
+			return Calendar{}, fmt.Errorf("unknown holiday region %q (available: %s)", region, strings.Join(HolidayRegions(), ", "))
+		}
+		rules, err := parseHolidayJSON(data)
+		if err != nil {
+			return Calendar{}, fmt.Errorf("holiday region %s: %w", region, err)
+		}
+		cal.rules = append(cal.rules, rules...)
+	}
+	return cal, nil
+}
+
+func readHolidayFile(file string) ([]HolidayRule, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read holiday file: %w", err)
+	}
+	var rules []HolidayRule
+	if strings.EqualFold(filepath.Ext(file), ".ics") {
+		rules, err = parseICS(string(data))
+	} else {
+		rules, err = parseHolidayJSON(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("holiday file %s: %w", file, err)
+	}
+	return rules, nil
+}
+
+func parseHolidayJSON(data []byte) ([]HolidayRule, error) {
+	var file HolidayFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	for i := range file.Holidays {
+		if err := file.Holidays[i].compile(); err != nil {
+			return nil, err
+		}
+	}
+	year := time.Now().Year()
+	for _, name := range datesRunOut(file.Holidays, year) {
+		if _, warned := warnedDatesRunOut.LoadOrStore(name, true); !warned {
+			slog.Warn("holiday dates table has no date this year or next; extend it", "holiday", name, "year", year)
+		}
+	}
+	return file.Holidays, nil
+}
+
+// warnedDatesRunOut records the holidays already warned about, so a calendar
+// loaded several times in a run warns once.
+var warnedDatesRunOut sync.Map
+
+// datesRunOut returns the names of dates rules with no date in year or the
+// year after.
+func datesRunOut(rules []HolidayRule, year int) []string {
+	var names []string
+	for _, r := range rules {
+		if r.Rule != HolidayRuleDates {
+			continue
+		}
+		current := slices.ContainsFunc(r.Dates, func(d string) bool {
+			return strings.HasPrefix(d, strconv.Itoa(year)+"-") || strings.HasPrefix(d, strconv.Itoa(year+1)+"-")
+		})
+		if !current {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// compile checks a rule and fills in its parsed fields.
+func (r *HolidayRule) compile() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("holiday name is required")
+	}
+	if r.ID == "" {
+		r.ID = strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(r.Name), "-"), "-")
+	}
+	switch r.Rule {
+	case HolidayRuleFixed:
+		m := monthDayPattern.FindStringSubmatch(r.Date)
+		if m == nil {
+			return fmt.Errorf("holiday %s: date must look like MM-DD, got %q", r.Name, r.Date)
+		}
+		t, err := time.Parse("2006-01-02", "2000-"+r.Date)
+		if err != nil {
+			return fmt.Errorf("holiday %s: invalid date %q", r.Name, r.Date)
+		}
+		r.month, r.day = t.Month(), t.Day()
+	case HolidayRuleNthWeekday, HolidayRuleLastWeekday:
+		if r.Month < 1 || r.Month > 12 {
+			return fmt.Errorf("holiday %s: month must be 1-12, got %d", r.Name, r.Month)
+		}
+		wd, ok := weekdayNames[strings.ToLower(strings.TrimSpace(r.Weekday))]
+		if !ok {
+			return fmt.Errorf("holiday %s: unknown weekday %q", r.Name, r.Weekday)
+		}
+		if r.Rule == HolidayRuleNthWeekday && (r.Nth < 1 || r.Nth > 5) {
+			return fmt.Errorf("holiday %s: nth must be 1-5, got %d", r.Name, r.Nth)
+		}
+		r.month, r.weekday = time.Month(r.Month), wd
+	case HolidayRuleEaster:
+	case HolidayRuleDates:
+		if len(r.Dates) == 0 {
+			return fmt.Errorf("holiday %s: dates are required", r.Name)
+		}
+		r.dates = make(map[string]bool, len(r.Dates))
+		for _, d := range r.Dates {
+			if _, err := time.Parse("2006-01-02", d); err != nil {
+				return fmt.Errorf("holiday %s: invalid date %q (expected YYYY-MM-DD)", r.Name, d)
+			}
+			r.dates[d] = true
+		}
+	default:
+		return fmt.Errorf("holiday %s: unknown rule %q (expected %s, %s, %s, %s, or %s)", r.Name, r.Rule,
+			HolidayRuleFixed, HolidayRuleNthWeekday, HolidayRuleLastWeekday, HolidayRuleEaster, HolidayRuleDates)
+	}
+	return nil
+}
+
+func (r HolidayRule) matches(date time.Time) bool {
+	year, month, day := date.Date()
+	switch r.Rule {
+	case HolidayRuleFixed:
+		return month == r.month && day == r.day
+	case HolidayRuleNthWeekday:
+		return month == r.month && date.Weekday() == r.weekday && (day-1)/7+1 == r.Nth
+	case HolidayRuleLastWeekday:
+		return month == r.month && date.Weekday() == r.weekday && date.AddDate(0, 0, 7).Month() != month
+	case HolidayRuleEaster:
+		return easterSunday(year).AddDate(0, 0, r.Offset).Equal(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+	case HolidayRuleDates:
+		return r.dates[date.Format("2006-01-02")]
+	}
+	return false
+}
+
+// On returns the holiday on date's calendar day in its own location, named and
+// described in the locale's language when the rule has a translation.
+func (c Calendar) On(date time.Time, locale Locale) (Holiday, bool) {
+	for _, rule := range c.rules {
+		if !rule.matches(date) {
+			continue
+		}
+		h := Holiday{Name: rule.Name, Description: rule.Description}
+		if text, ok := rule.Translations[locale.Base]; ok && text.Name != "" {
+			h = Holiday{Name: text.Name, Description: text.Description}
+		}
+		if desc, ok := c.descriptions[rule.ID]; ok && desc != "" {
+			h.Description = desc
+		}
+		return h, true
+	}
+	return Holiday{}, false
+}
+
+// easterSunday returns the date of Western (Gregorian) Easter Sunday using the
+// anonymous Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
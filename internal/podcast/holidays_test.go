@@ -0,0 +1,199 @@
+package podcast
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/config"
+)
+
+func TestDefaultCalendarRules(t *testing.T) {
+	cal := DefaultCalendar()
+	cases := map[string]string{
+		"2026-01-19": "Martin Luther King Jr. Day", // third Monday
+		"2026-05-25": "Memorial Day",               // last Monday
+		"2026-11-26": "Thanksgiving",
+	}
+	for date, want := range cases {
+		d, _ := time.Parse("2006-01-02", date)
+		h, ok := cal.On(d, DefaultLocale())
+		if !ok || h.Name != want {
+			t.Fatalf("%s: expected %s, got %+v (%v)", date, want, h, ok)
+		}
+	}
+	if h, ok := cal.On(time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), DefaultLocale()); ok {
+		t.Fatalf("expected no holiday, got %+v", h)
+	}
+	if h, ok := cal.On(time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), DefaultLocale()); ok {
+		t.Fatalf("expected world days to be opt-in, got %+v", h)
+	}
+}
+
+func TestWorldCalendarRules(t *testing.T) {
+	cal, err := LoadCalendar([]string{"us", "world"}, nil, nil)
+	if err != nil {
+		t.Fatalf("LoadCalendar: %v", err)
+	}
+	cases := map[string]string{
+		"2026-02-17": "Lunar New Year", // explicit date
+		"2026-03-14": "Pi Day",
+		"2026-11-08": "Diwali",
+		"2035-12-26": "the first day of Hanukkah",
+	}
+	for date, want := range cases {
+		d, _ := time.Parse("2006-01-02", date)
+		h, ok := cal.On(d, DefaultLocale())
+		if !ok || h.Name != want {
+			t.Fatalf("%s: expected %s, got %+v (%v)", date, want, h, ok)
+		}
+	}
+}
+
+func TestWorldCalendarDatesTables(t *testing.T) {
+	data, err := builtinCalendars.ReadFile("calendars/world.json")
+	if err != nil {
+		t.Fatalf("read world calendar: %v", err)
+	}
+	rules, err := parseHolidayJSON(data)
+	if err != nil {
+		t.Fatalf("parse world calendar: %v", err)
+	}
+	if names := datesRunOut(rules, 2034); len(names) != 0 {
+		t.Fatalf("expected dates tables through 2035, run out: %v", names)
+	}
+	if names := datesRunOut(rules, 2036); len(names) != 5 {
+		t.Fatalf("expected all 5 dates tables to run out by 2036, got %v", names)
+	}
+}
+
+func TestCalendarEasterRules(t *testing.T) {
+	for year, want := range map[int]string{2024: "2024-03-31", 2025: "2025-04-20", 2026: "2026-04-05", 2027: "2027-03-28"} {
+		if got := easterSunday(year).Format("2006-01-02"); got != want {
+			t.Fatalf("easter %d: expected %s, got %s", year, want, got)
+		}
+	}
+	cal, err := LoadCalendar([]string{"uk"}, nil, nil)
+	if err != nil {
+		t.Fatalf("LoadCalendar: %v", err)
+	}
+	if h, ok := cal.On(time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC), DefaultLocale()); !ok || h.Name != "Good Friday" {
+		t.Fatalf("expected Good Friday, got %+v", h)
+	}
+	if h, ok := cal.On(time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC), DefaultLocale()); !ok || h.Name != "Easter Monday" {
+		t.Fatalf("expected Easter Monday, got %+v", h)
+	}
+	if h, ok := cal.On(time.Date(2026, 7, 4, 0, 0, 0, 0, time.UTC), DefaultLocale()); ok {
+		t.Fatalf("expected no U.S. holidays in the uk region, got %+v", h)
+	}
+}
+
+func TestCalendarTranslationsAndDescriptions(t *testing.T) {
+	cal, err := LoadCalendar([]string{"mx"}, nil, map[string]string{"the-day-of-the-dead": "a custom description"})
+	if err != nil {
+		t.Fatalf("LoadCalendar: %v", err)
+	}
+	es, err := LookupLocale("es-MX")
+	if err != nil {
+		t.Fatalf("LookupLocale: %v", err)
+	}
+	date := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	if h, _ := cal.On(date, es); h.Name != "el Día de Muertos" || h.Description != "a custom description" {
+		t.Fatalf("expected Spanish name with overridden description, got %+v", h)
+	}
+	if h, _ := cal.On(date, DefaultLocale()); h.Name != "the Day of the Dead" {
+		t.Fatalf("expected English name, got %+v", h)
+	}
+}
+
+func TestLoadCalendarFilesPrecedeRegions(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "school.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"holidays": [
+		{"name": "Founders Day", "description": "our school's birthday", "rule": "fixed", "date": "07-04"}
+	]}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	icsPath := filepath.Join(dir, "family.ics")
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20260310",
+		"SUMMARY:Grandma's Birthday",
+		"RRULE:FREQ=YEARLY",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20260101",
+		"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=2TU",
+		"SUMMARY:Ada Lovelace Day",
+		"DESCRIPTION:celebrating women in science\\, technology\\, and",
+		"  math",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20270415T090000Z",
+		"SUMMARY:Science Fair",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	if err := os.WriteFile(icsPath, []byte(ics), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cal, err := LoadCalendar([]string{"us"}, []string{jsonPath, icsPath}, nil)
+	if err != nil {
+		t.Fatalf("LoadCalendar: %v", err)
+	}
+	cases := map[string]Holiday{
+		"2026-07-04": {Name: "Founders Day", Description: "our school's birthday"},
+		"2030-03-10": {Name: "Grandma's Birthday"},
+		"2026-10-13": {Name: "Ada Lovelace Day", Description: "celebrating women in science, technology, and math"},
+		"2027-04-15": {Name: "Science Fair"},
+	}
+	for date, want := range cases {
+		d, _ := time.Parse("2006-01-02", date)
+		if h, ok := cal.On(d, DefaultLocale()); !ok || h != want {
+			t.Fatalf("%s: expected %+v, got %+v (%v)", date, want, h, ok)
+		}
+	}
+	if _, ok := cal.On(time.Date(2028, 4, 15, 0, 0, 0, 0, time.UTC), DefaultLocale()); ok {
+		t.Fatalf("expected a one-off ics event to match only its date")
+	}
+}
+
+func TestLoadCalendarErrors(t *testing.T) {
+	if _, err := LoadCalendar([]string{"atlantis"}, nil, nil); err == nil || !strings.Contains(err.Error(), "available:") {
+		t.Fatalf("expected unknown region error, got %v", err)
+	}
+	dir := t.TempDir()
+	cases := map[string]string{
+		"bad.json": `{"holidays": [{"name": "Oops", "rule": "nth-weekday", "month": 13, "weekday": "monday", "nth": 1}]}`,
+		"bad.ics":  "BEGIN:VEVENT\nDTSTART:20260101\nRRULE:FREQ=WEEKLY\nSUMMARY:Chores\nEND:VEVENT\n",
+	}
+	for name, body := range cases {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if _, err := LoadCalendar(nil, []string{path}, nil); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestPromptsFromConfigUsesHolidayRegions(t *testing.T) {
+	prompts, err := PromptsFromConfig(config.Config{Language: "es", HolidayRegions: []string{"mx"}})
+	if err != nil {
+		t.Fatalf("PromptsFromConfig: %v", err)
+	}
+	specs, err := DefaultShow().Specs(prompts, "Mariposas", time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Specs: %v", err)
+	}
+	if !strings.Contains(specs[0].Prompt, "today is el Día de Muertos") {
+		t.Fatalf("expected Spanish holiday in intro prompt: %q", specs[0].Prompt)
+	}
+	if _, err := PromptsFromConfig(config.Config{HolidayRegions: []string{"atlantis"}}); err == nil {
+		t.Fatalf("expected unknown region error")
+	}
+}
@@ -0,0 +1,130 @@
+package podcast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var icsWeekdays = map[string]string{
+	"SU": "sunday", "MO": "monday", "TU": "tuesday", "WE": "wednesday",
+	"TH": "thursday", "FR": "friday", "SA": "saturday",
+}
+
+// parseICS converts the VEVENTs of an iCalendar file into holiday rules. An
+// event without RRULE becomes an explicit date; RRULE:FREQ=YEARLY becomes a
+// fixed date, or an nth/last weekday rule when it has BYMONTH and BYDAY (such
+// as BYDAY=3MO or BYDAY=-1MO). Other recurrences are rejected.
+func parseICS(text string) ([]HolidayRule, error) {
+	var rules []HolidayRule
+	var event map[string]string
+	for _, line := range unfoldICSLines(text) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Drop parameters such as DTSTART;VALUE=DATE.
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event = map[string]string{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if event == nil {
+				continue
+			}
+			rule, err := icsEventRule(event)
+			if err != nil {
+				return nil, err
+			}
+			if err := rule.compile(); err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+			event = nil
+		case event != nil:
+			if _, seen := event[name]; !seen {
+				event[name] = value
+			}
+		}
+	}
+	return rules, nil
+}
+
+func icsEventRule(event map[string]string) (HolidayRule, error) {
+	summary := unescapeICSText(event["SUMMARY"])
+	rule := HolidayRule{Name: summary, Description: unescapeICSText(event["DESCRIPTION"])}
+	start := event["DTSTART"]
+	if len(start) < 8 {
+		return rule, fmt.Errorf("event %q: missing DTSTART", summary)
+	}
+	date, err := time.Parse("20060102", start[:8])
+	if err != nil {
+		return rule, fmt.Errorf("event %q: invalid DTSTART %q", summary, start)
+	}
+	rrule := event["RRULE"]
+	if rrule == "" {
+		rule.Rule = HolidayRuleDates
+		rule.Dates = []string{date.Format("2006-01-02")}
+		return rule, nil
+	}
+	parts := map[string]string{}
+	for _, part := range strings.Split(strings.ToUpper(rrule), ";") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			parts[k] = v
+		}
+	}
+	if parts["FREQ"] != "YEARLY" {
+		return rule, fmt.Errorf("event %q: unsupported RRULE %q (only FREQ=YEARLY)", summary, rrule)
+	}
+	byDay := parts["BYDAY"]
+	if byDay == "" {
+		rule.Rule = HolidayRuleFixed
+		rule.Date = date.Format("01-02")
+		return rule, nil
+	}
+	month := int(date.Month())
+	if v := parts["BYMONTH"]; v != "" {
+		if month, err = strconv.Atoi(v); err != nil {
+			return rule, fmt.Errorf("event %q: invalid BYMONTH %q", summary, v)
+		}
+	}
+	if len(byDay) < 3 {
+		return rule, fmt.Errorf("event %q: unsupported BYDAY %q", summary, byDay)
+	}
+	weekday, ok := icsWeekdays[byDay[len(byDay)-2:]]
+	if !ok {
+		return rule, fmt.Errorf("event %q: unsupported BYDAY %q", summary, byDay)
+	}
+	nth, err := strconv.Atoi(byDay[:len(byDay)-2])
+	if err != nil {
+		return rule, fmt.Errorf("event %q: unsupported BYDAY %q", summary, byDay)
+	}
+	rule.Month = month
+	rule.Weekday = weekday
+	if nth == -1 {
+		rule.Rule = HolidayRuleLastWeekday
+	} else {
+		rule.Rule = HolidayRuleNthWeekday
+		rule.Nth = nth
+	}
+	return rule, nil
+}
+
+// unfoldICSLines joins folded continuation lines (lines starting with a space
+// or tab) and strips line endings.
+func unfoldICSLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func unescapeICSText(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(strings.TrimSpace(s))
+}
@@ -0,0 +1,285 @@
+package podcast
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"yodex/internal/astro"
+	"yodex/internal/config"
+)
+
+// Identity describes who the show is and who it is for. Every field is
+// available to prompt templates (for example {{.ShowName}} or {{.Audience}}).
+type Identity struct {
+	ShowName    string
+	HostName    string
+	HostPersona string // "an expert kid's science podcaster"
+	Audience    string // "advanced 7-year-olds"
+	AgeRange    string // "7–9"
+	Tone        string
+}
+
+// DefaultIdentity returns the built-in Curious World Podcast identity.
+func DefaultIdentity() Identity {
+	return Identity{
+		ShowName:    "Curious World Podcast",
+		HostName:    "Jessica",
+		HostPersona: "an expert kid's science podcaster",
+		Audience:    "advanced 7-year-olds",
+		AgeRange:    "7–9",
+		Tone: "Be engaging, positive, accurate, and safe. " +
+			"Use clear explanations and relatable analogies. " +
+			"Avoid scary, graphic, or unsafe content.",
+	}
+}
+
+// IdentityFromConfig returns the configured identity, using the default for
+// any field left empty.
+func IdentityFromConfig(cfg config.Config) Identity {
+	id := DefaultIdentity()
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&id.ShowName, cfg.ShowName},
+		{&id.HostName, cfg.HostName},
+		{&id.HostPersona, cfg.HostPersona},
+		{&id.Audience, cfg.Audience},
+		{&id.AgeRange, cfg.AgeRange},
+		{&id.Tone, cfg.Tone},
+	} {
+		if v := strings.TrimSpace(f.src); v != "" {
+			*f.dst = v
+		}
+	}
+	return id
+}
+
+// Prompt template names. A file named <name>.tmpl in the prompts directory
+// replaces the built-in template, and sections/<section id>.tmpl replaces a
+// show section's prompt.
+const (
+	PromptScriptSystem = "script-system"
+	PromptScript       = "script"
+	PromptTopicSystem  = "topic-system"
+	PromptTopicRequest = "topic-request"
+	PromptGameSystem   = "game-system"
+)
+
+const defaultScriptSystemPrompt = `You are {{.HostPersona}} for {{.Audience}}. {{.Tone}}{{with .LanguageInstruction}} {{.}}{{end}}`
+
+const defaultScriptPrompt = `You are writing a kid-friendly science podcast episode for the "{{.ShowName}}" hosted by {{.HostName}}, about {{printf "%q" .Topic}}. ` +
+	"Each request is for one section of the episode. " +
+	"Write in a friendly narrator voice, no headings or labels. " +
+	"Use inflection tags generously throughout the section to add energy and texture. " +
+	"Place tags at the start of the line or sentence where they apply (e.g., before a punchline), not at the end. " +
+	"You can stack multiple tags when it fits (e.g., [playful][excited]). " +
+	"Use a mix of upbeat emotional tags (e.g., [happy], [excited], [curious], [encouraging], [cheerful], [warm], [playful], [storytelling], [anticipation]) and light non-verbal tags (e.g., [laughing], [chuckles], [short pause]). " +
+	"For yes/no questions, add a [short pause] tag immediately after the question. " +
+	"For free-form questions, add a [long pause] tag immediately after the question. " +
+	"When unsure, use [short pause] unless the question invites imagination or reflection; then use [long pause]. " +
+	"Ask one question at a time; if you need multiple questions, split them into separate sentences and include a pause after each question. " +
+	"Always include a space before any tag; never attach tags directly to punctuation. " +
+	"After the pause, follow up by enthusiastically affirming the listener without assuming their specific answer; keep affirmations generic and vary them (celebrate effort or curiosity). " +
+	"Avoid negative, tired, or bored tags; only use voice-related tags (no music or sound effects). " +
+	`Examples: "[excited][cheerful] We have a cool mystery today!" "[joking][playful] Why did the comet bring a suitcase?" "[laughing][anticipation] Because it was going on a long trip!" ` +
+	"Keep tags brief, natural, and kid-appropriate, and never let a tag change the meaning of the sentence. " +
+	"Keep it upbeat, kid-safe, accurate, and easy to follow. Avoid unsafe instructions."
+
+const defaultTopicSystemPrompt = `You propose safe, accurate science topics for {{.Audience}}.{{with .LanguageInstruction}} {{.}}{{end}}`
+
+const defaultTopicRequestPrompt = "Propose a single science topic for {{.Audience}}. " +
+	"Examples of topics: animals, cultural celebrations, science, astronomy, history, geography, physics, chemistry, biology, or nature. " +
+	"The topic should be interesting and engaging for kids ages {{.AgeRange}}. " +
+	"The topic should be safe and appropriate for kids ages {{.AgeRange}}. " +
+	"You may focus on a specific animal, plant, planet, star, or some other specific thing to do a deep-dive, or you may focus on a general science topic. " +
+	"The topic should be accurate and up to date. " +
+	"Reply with a short title only." +
+	"{{with .SkyEvents}} Happening in the sky today: {{range $i, $e := .}}{{if $i}} {{end}}{{$e.Description}}{{end}} " +
+	"A topic about one of these would be timely and is encouraged.{{end}}"
+
+var defaultPromptTemplates = map[string]string{
+	PromptScriptSystem: defaultScriptSystemPrompt,
+	PromptScript:       defaultScriptPrompt,
+	PromptTopicSystem:  defaultTopicSystemPrompt,
+	PromptTopicRequest: defaultTopicRequestPrompt,
+	PromptGameSystem:   defaultGameSystemPrompt,
+}
+
+// Prompts renders the show's prompt templates with its identity, in the
+// episode language. GameLocale is the language the brain game is played in,
+// and Calendar supplies the holidays mentioned in the intro and outro.
+// SkyEvents adds the day's sky events to the template data, Series the
+// episode's place in a multi-part series, and Recap the episodes a weekly
+// recap reviews.
+type Prompts struct {
+	Identity   Identity
+	Locale     Locale
+	GameLocale Locale
+	Calendar   Calendar
+	SkyEvents  bool
+	Series     *TopicSeries
+	Recap      []RecapEpisode
+	overrides  map[string]string
+	sections   map[string]string
+}
+
+// DefaultPrompts returns the built-in English templates with the default identity.
+func DefaultPrompts() Prompts {
+	return Prompts{Identity: DefaultIdentity(), Locale: DefaultLocale(), GameLocale: DefaultLocale(), Calendar: DefaultCalendar()}
+}
+
+// LoadPrompts returns the built-in templates with any overrides found in dir.
+// Templates in dir/<language>/ (for example dir/es/script.tmpl or
+// dir/es/sections/intro.tmpl) take precedence for that episode language. An
+// empty dir uses the built-in templates only. The default holiday calendar is
+// used and sky events are off.
+func LoadPrompts(identity Identity, locale, gameLocale Locale, dir string) (Prompts, error) {
+	p := Prompts{Identity: identity, Locale: locale, GameLocale: gameLocale, Calendar: DefaultCalendar()}
+	if strings.TrimSpace(dir) == "" {
+		return p, nil
+	}
+	p.overrides = map[string]string{}
+	p.sections = map[string]string{}
+	dirs := []string{dir, filepath.Join(dir, locale.Base)}
+	if locale.Tag != locale.Base {
+		dirs = append(dirs, filepath.Join(dir, locale.Tag))
+	}
+	for i, d := range dirs {
+		overrides, err := readTemplateDir(d)
+		if err != nil {
+			// Only the top-level directory has to exist.
+			if i > 0 && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return Prompts{}, err
+		}
+		for name, text := range overrides {
+			if _, ok := defaultPromptTemplates[name]; !ok {
+				return Prompts{}, fmt.Errorf("unknown prompt template %s in %s", name, d)
+			}
+			p.overrides[name] = text
+		}
+		sections, err := readTemplateDir(filepath.Join(d, "sections"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return Prompts{}, err
+		}
+		for id, text := range sections {
+			p.sections[id] = text
+		}
+	}
+	return p, nil
+}
+
+// PromptsFromConfig loads the prompts for the configured identity, language,
+// game language, templates directory, and holiday calendar.
+func PromptsFromConfig(cfg config.Config) (Prompts, error) {
+	locale, err := LookupLocale(cfg.Language)
+	if err != nil {
+		return Prompts{}, err
+	}
+	gameLocale := locale
+	if strings.TrimSpace(cfg.GameLanguage) != "" {
+		if gameLocale, err = LookupLocale(cfg.GameLanguage); err != nil {
+			return Prompts{}, fmt.Errorf("game language: %w", err)
+		}
+	}
+	calendar, err := LoadCalendar(cfg.HolidayRegions, cfg.HolidayFiles, cfg.HolidayDescriptions)
+	if err != nil {
+		return Prompts{}, err
+	}
+	prompts, err := LoadPrompts(IdentityFromConfig(cfg), locale, gameLocale, cfg.PromptsDir)
+	if err != nil {
+		return Prompts{}, err
+	}
+	prompts.Calendar = calendar
+	prompts.SkyEvents = cfg.SkyEvents
+	return prompts, nil
+}
+
+// Data returns template data for a topic and episode date, with the day's
+// sky events when enabled, the series part, if any, and the recap episodes.
+func (p Prompts) Data(topic string, date time.Time) PromptData {
+	data := NewPromptData(p.Identity, p.Locale, p.GameLocale, p.Calendar, topic, date)
+	data.Series = p.Series
+	data.Recap = p.Recap
+	if p.SkyEvents {
+		data.SkyEvents = astro.EventsOn(data.Date)
+	}
+	return data
+}
+
+// Render renders a named prompt template.
+func (p Prompts) Render(name string, data PromptData) (string, error) {
+	text, ok := p.overrides[name]
+	if !ok {
+		text, ok = defaultPromptTemplates[name]
+	}
+	if !ok {
+		return "", fmt.Errorf("unknown prompt template %s", name)
+	}
+	return renderTemplate(name, text, data)
+}
+
+// sectionPrompt returns the prompt template for a show section, preferring a
+// sections/<id>.tmpl override.
+func (p Prompts) sectionPrompt(section ShowSection) string {
+	if text, ok := p.sections[section.ID]; ok {
+		return text
+	}
+	return section.Prompt
+}
+
+// ScriptPrompts returns the system and base user prompt for section generation.
+func (p Prompts) ScriptPrompts(topic string, date time.Time) (string, string, error) {
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return "", "", errors.New("topic is required")
+	}
+	data := p.Data(topic, date)
+	system, err := p.Render(PromptScriptSystem, data)
+	if err != nil {
+		return "", "", err
+	}
+	user, err := p.Render(PromptScript, data)
+	if err != nil {
+		return "", "", err
+	}
+	return system, user, nil
+}
+
+// readTemplateDir reads every *.tmpl file in dir, keyed by name without the
+// extension, and checks that each parses.
+func readTemplateDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read prompts dir: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".tmpl" {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	templates := make(map[string]string, len(names))
+	for _, file := range names {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("read prompt template: %w", err)
+		}
+		name := strings.TrimSuffix(file, ".tmpl")
+		if _, err := template.New(name).Parse(string(data)); err != nil {
+			return nil, fmt.Errorf("prompt template %s: %w", file, err)
+		}
+		templates[name] = string(data)
+	}
+	return templates, nil
+}
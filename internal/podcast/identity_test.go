@@ -0,0 +1,118 @@
+package podcast
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/config"
+)
+
+func TestIdentityFromConfigFallsBackToDefaults(t *testing.T) {
+	id := IdentityFromConfig(config.Config{ShowName: "Big Questions", AgeRange: "10–12"})
+	if id.ShowName != "Big Questions" || id.AgeRange != "10–12" {
+		t.Fatalf("expected configured fields, got %+v", id)
+	}
+	if id.HostName != DefaultIdentity().HostName || id.Tone != DefaultIdentity().Tone {
+		t.Fatalf("expected defaults for empty fields, got %+v", id)
+	}
+}
+
+func TestPromptsUseIdentity(t *testing.T) {
+	identity := Identity{
+		ShowName:    "Big Questions",
+		HostName:    "Marco",
+		HostPersona: "a science journalist",
+		Audience:    "curious 10- to 12-year-olds",
+		AgeRange:    "10–12",
+		Tone:        "Be witty and precise.",
+	}
+	prompts, err := LoadPrompts(identity, DefaultLocale(), DefaultLocale(), "")
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	date := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)
+	system, user, err := prompts.ScriptPrompts("Black Holes", date)
+	if err != nil {
+		t.Fatalf("ScriptPrompts: %v", err)
+	}
+	if system != "You are a science journalist for curious 10- to 12-year-olds. Be witty and precise." {
+		t.Fatalf("unexpected system prompt: %q", system)
+	}
+	if !strings.Contains(user, `"Big Questions" hosted by Marco, about "Black Holes"`) {
+		t.Fatalf("expected identity in user prompt: %q", user)
+	}
+	specs, err := DefaultShow().Specs(prompts, "Black Holes", date)
+	if err != nil {
+		t.Fatalf("Specs: %v", err)
+	}
+	if !strings.Contains(specs[0].Prompt, `"Big Questions" and introduce the host, Marco`) {
+		t.Fatalf("expected identity in intro prompt: %q", specs[0].Prompt)
+	}
+	if specs[2].Kind != SectionKindGame || !strings.Contains(specs[2].Prompt, "for kids ages 10–12") {
+		t.Fatalf("expected game system prompt on game spec: %q", specs[2].Prompt)
+	}
+}
+
+func TestLoadPromptsOverridesFromDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sections"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	files := map[string]string{
+		"script-system.tmpl":  "You host {{.ShowName}}.",
+		"sections/outro.tmpl": "Say goodbye from {{.HostName}} and ask about {{.Topic}}.",
+		"notes.txt":           "ignored",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	prompts, err := LoadPrompts(DefaultIdentity(), DefaultLocale(), DefaultLocale(), dir)
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	date := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)
+	system, user, err := prompts.ScriptPrompts("Bees", date)
+	if err != nil {
+		t.Fatalf("ScriptPrompts: %v", err)
+	}
+	if system != "You host Curious World Podcast." {
+		t.Fatalf("expected overridden system prompt, got %q", system)
+	}
+	if !strings.Contains(user, "Curious World Podcast") {
+		t.Fatalf("expected default user prompt, got %q", user)
+	}
+	specs, err := DefaultShow().Specs(prompts, "Bees", date)
+	if err != nil {
+		t.Fatalf("Specs: %v", err)
+	}
+	if got := specs[3].Prompt; got != "Say goodbye from Jessica and ask about Bees." {
+		t.Fatalf("expected overridden outro prompt, got %q", got)
+	}
+}
+
+func TestLoadPromptsRejectsBadTemplates(t *testing.T) {
+	cases := map[string]string{
+		"unknown name": "scirpt.tmpl",
+		"parse error":  "script.tmpl",
+	}
+	for name, file := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			body := "Hello {{.ShowName}}"
+			if name == "parse error" {
+				body = "Hello {{.ShowName"
+			}
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(body), 0o644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if _, err := LoadPrompts(DefaultIdentity(), DefaultLocale(), DefaultLocale(), dir); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package podcast
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Default per-section word budgets. Together they land in the middle of the
+// 750-900 word (about five minute) target.
+const (
+	DefaultIntroWordBudget = 80
+	DefaultTopicWordBudget = 460
+	DefaultGameWordBudget  = 200
+	DefaultOutroWordBudget = 80
+)
+
+// SpokenWordCount counts the words that will be spoken, ignoring audio tags.
+func SpokenWordCount(text string) int {
+	return WordCount(StripAudioTags(text))
+}
+
+// WordBudgets returns each section's word budget. Sections without a budget,
+// such as static text, are omitted.
+func WordBudgets(specs []SectionSpec) map[string]int {
+	budgets := make(map[string]int, len(specs))
+	for _, spec := range specs {
+		if spec.WordBudget > 0 {
+			budgets[spec.SectionID] = spec.WordBudget
+		}
+	}
+	return budgets
+}
+
+// EnforcedWordBudgets returns the budgets sections are held to after
+// generation: every budget when the episode has a target word count, and
+// otherwise only the budgets set in the show definition.
+func EnforcedWordBudgets(specs []SectionSpec, targeted bool) map[string]int {
+	budgets := WordBudgets(specs)
+	if targeted {
+		return budgets
+	}
+	for _, spec := range specs {
+		if !spec.EnforceWordBudget {
+			delete(budgets, spec.SectionID)
+		}
+	}
+	return budgets
+}
+
+// ScaleWordBudgets scales budgets proportionally so they add up to total.
+// A non-positive total returns the budgets unchanged.
+func ScaleWordBudgets(budgets map[string]int, total int) map[string]int {
+	sum := 0
+	for _, b := range budgets {
+		sum += b
+	}
+	scaled := make(map[string]int, len(budgets))
+	for id, b := range budgets {
+		if total <= 0 || sum == 0 {
+			scaled[id] = b
+			continue
+		}
+		scaled[id] = int(math.Round(float64(b) * float64(total) / float64(sum)))
+	}
+	return scaled
+}
+
+// ApplyWordBudgets returns a copy of specs with budgets taken from the map.
+func ApplyWordBudgets(specs []SectionSpec, budgets map[string]int) []SectionSpec {
+	out := make([]SectionSpec, len(specs))
+	copy(out, specs)
+	for i := range out {
+		if b, ok := budgets[out[i].SectionID]; ok {
+			out[i].WordBudget = b
+		}
+	}
+	return out
+}
+
+// WithinWordBudget reports whether words is within tolerance (a fraction such
+// as 0.15) of budget. A zero budget always passes.
+func WithinWordBudget(words, budget int, tolerance float64) bool {
+	if budget <= 0 {
+		return true
+	}
+	diff := math.Abs(float64(words - budget))
+	return diff <= float64(budget)*tolerance
+}
+
+// LengthRevisionNotes returns an "expand" or "tighten" instruction for a
+// section that missed its budget.
+func LengthRevisionNotes(words, budget int) string {
+	var b strings.Builder
+	if words < budget {
+		fmt.Fprintf(&b, "Expand this section from about %d to about %d spoken words. ", words, budget)
+		b.WriteString("Add one more concrete example, analogy, or kid-friendly detail rather than padding or repeating yourself. ")
+	} else {
+		fmt.Fprintf(&b, "Tighten this section from about %d to about %d spoken words. ", words, budget)
+		b.WriteString("Cut repetition and side details while keeping the main facts, questions, and the ending that leads into the next section. ")
+	}
+	b.WriteString("Keep the same voice, bracketed tags, and pause tags after questions.")
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package podcast
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScaleWordBudgets(t *testing.T) {
+	specs, err := DefaultShow().Specs(DefaultPrompts(), "Volcanoes", time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Specs: %v", err)
+	}
+	budgets := WordBudgets(specs)
+	if len(budgets) != 4 || budgets["game"] != DefaultGameWordBudget || budgets["topic"] != DefaultTopicWordBudget {
+		t.Fatalf("unexpected default budgets: %v", budgets)
+	}
+	scaled := ScaleWordBudgets(budgets, 410)
+	total := 0
+	for _, b := range scaled {
+		total += b
+	}
+	if total < 408 || total > 412 {
+		t.Fatalf("expected scaled budgets near 410, got %d (%v)", total, scaled)
+	}
+	if scaled["topic"] != 230 {
+		t.Fatalf("expected topic budget halved, got %d", scaled["topic"])
+	}
+	if got := ScaleWordBudgets(budgets, 0); got["topic"] != DefaultTopicWordBudget {
+		t.Fatalf("expected zero total to keep budgets, got %v", got)
+	}
+
+	applied := ApplyWordBudgets(specs, scaled)
+	prompt := BuildSectionPrompt("", applied[1])
+	if !strings.Contains(prompt, "Target length: about 230 spoken words.") {
+		t.Fatalf("expected budget in prompt, got %q", prompt)
+	}
+	if specs[1].WordBudget != DefaultTopicWordBudget {
+		t.Fatalf("ApplyWordBudgets should not modify its input")
+	}
+}
+
+func TestWordBudgetToleranceAndNotes(t *testing.T) {
+	if !WithinWordBudget(90, 100, 0.15) || WithinWordBudget(80, 100, 0.15) || WithinWordBudget(120, 100, 0.15) {
+		t.Fatalf("unexpected tolerance result")
+	}
+	if !WithinWordBudget(5, 0, 0.15) {
+		t.Fatalf("expected zero budget to pass")
+	}
+	if notes := LengthRevisionNotes(50, 100); !strings.HasPrefix(notes, "Expand") {
+		t.Fatalf("expected expand notes, got %q", notes)
+	}
+	if notes := LengthRevisionNotes(150, 100); !strings.HasPrefix(notes, "Tighten") {
+		t.Fatalf("expected tighten notes, got %q", notes)
+	}
+	if got := SpokenWordCount("[excited] Hello there! [short pause]"); got != 2 {
+		t.Fatalf("expected tags to be ignored, got %d", got)
+	}
+}
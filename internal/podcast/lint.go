@@ -0,0 +1,189 @@
+package podcast
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Lint rules reported by LintSection.
+const (
+	LintTagSpacing    = "tag-spacing"
+	LintUnknownTag    = "unknown-tag"
+	LintQuestionPause = "question-pause"
+	LintStackedPauses = "stacked-pauses"
+	LintMarkdown      = "markdown"
+)
+
+// knownAudioTags are the tags the script and game prompts ask for; anything
+// else is likely read aloud or ignored by the voice.
+var knownAudioTags = map[string]bool{
+	"short pause": true, "long pause": true,
+	"happy": true, "excited": true, "curious": true, "encouraging": true, "cheerful": true,
+	"warm": true, "playful": true, "storytelling": true, "anticipation": true, "joking": true,
+	"laughing": true, "chuckles": true,
+}
+
+var (
+	// A tag glued to the word or punctuation before it; tags may still stack.
+	gluedTagBeforePattern = regexp.MustCompile(`[^\s\[\]](\[[^\[\]\n]+\])`)
+	gluedTagAfterPattern  = regexp.MustCompile(`(\[[^\[\]\n]+\])[\p{L}\p{N}]`)
+	stackedPausesPattern  = regexp.MustCompile(`\[(?:short|long) pause\](?:\s*\[(?:short|long) pause\])+`)
+	markdownHeadingLine   = regexp.MustCompile(`(?m)^[ \t]*#{1,6}[ \t]+`)
+	markdownBulletLine    = regexp.MustCompile(`(?m)^[ \t]*[-*•][ \t]+`)
+	markdownEmphasis      = regexp.MustCompile(`\*\*([^*\n]+)\*\*|\*([^*\s][^*\n]*)\*`)
+)
+
+// LintIssue is one spoken-form problem in a section file. Line and Column are
+// 1-based; Column counts characters.
+type LintIssue struct {
+	SectionID string `json:"section_id,omitempty"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	Rule      string `json:"rule"`
+	Message   string `json:"message"`
+	Fixable   bool   `json:"fixable,omitempty"`
+}
+
+func (i LintIssue) String() string {
+	s := fmt.Sprintf("%d:%d: %s: %s", i.Line, i.Column, i.Rule, i.Message)
+	if i.Fixable {
+		s += " (fixable)"
+	}
+	return s
+}
+
+// LintSection checks a section's text against the prompt rules for spoken
+// scripts: tags are separated from words and punctuation, every tag is one
+// the prompts use, every question is followed by a single pause, and no
+// markdown is left for the voice to read aloud.
+func LintSection(sectionID, text string) []LintIssue {
+	type finding struct {
+		offset  int
+		rule    string
+		message string
+		fixable bool
+	}
+	var findings []finding
+	add := func(offset int, rule string, fixable bool, format string, args ...any) {
+		findings = append(findings, finding{offset, rule, fmt.Sprintf(format, args...), fixable})
+	}
+
+	for _, m := range markdownHeadingLine.FindAllStringIndex(text, -1) {
+		add(m[0], LintMarkdown, true, "markdown heading %q is read aloud", strings.TrimSpace(text[m[0]:m[1]]))
+	}
+	for _, m := range markdownBulletLine.FindAllStringIndex(text, -1) {
+		add(m[0], LintMarkdown, true, "bullet %q is read aloud", strings.TrimSpace(text[m[0]:m[1]]))
+	}
+	for _, m := range markdownEmphasis.FindAllStringIndex(text, -1) {
+		add(m[0], LintMarkdown, true, "emphasis asterisks are read aloud")
+	}
+	for _, m := range gluedTagBeforePattern.FindAllStringSubmatchIndex(text, -1) {
+		add(m[2], LintTagSpacing, true, "tag %s is attached to the text before it", text[m[2]:m[3]])
+	}
+	for _, m := range gluedTagAfterPattern.FindAllStringSubmatchIndex(text, -1) {
+		add(m[2], LintTagSpacing, true, "tag %s is attached to the text after it", text[m[2]:m[3]])
+	}
+	for _, m := range audioTagPattern.FindAllStringIndex(text, -1) {
+		tag := text[m[0]:m[1]]
+		if !knownAudioTags[strings.ToLower(strings.TrimSpace(strings.Trim(tag, "[]")))] {
+			add(m[0], LintUnknownTag, false, "unknown tag %s", tag)
+		}
+	}
+
+	offset := 0
+	segments := SplitOnPauses(text)
+	for i, segment := range segments {
+		tagOffset := offset + len(segment.Text)
+		if segment.PauseTag != "" && i > 0 && segments[i-1].PauseTag != "" && strings.TrimSpace(segment.Text) == "" {
+			add(tagOffset, LintStackedPauses, true, "%s follows another pause", segment.PauseTag)
+		}
+		sentences := splitSentences(segment.Text)
+		last := len(sentences) - 1
+		for last >= 0 && !isSpoken(sentences[last]) {
+			last--
+		}
+		normalized := strings.ReplaceAll(segment.Text, "\n", " ")
+		cursor := 0
+		for j, sentence := range sentences {
+			idx := strings.Index(normalized[cursor:], sentence)
+			if idx < 0 {
+				continue
+			}
+			start, end := cursor+idx, cursor+idx+len(sentence)
+			cursor = end
+			if !strings.HasSuffix(sentence, "?") || (j == last && segment.PauseTag != "") {
+				continue
+			}
+			add(offset+end-1, LintQuestionPause, false, "question %q is not followed by [short pause] or [long pause]", sentenceSnippet(segment.Text[start:end]))
+		}
+		offset = tagOffset + len(segment.PauseTag)
+	}
+
+	issues := make([]LintIssue, 0, len(findings))
+	for _, f := range findings {
+		line, column := lintPosition(text, f.offset)
+		issues = append(issues, LintIssue{SectionID: sectionID, Line: line, Column: column, Rule: f.rule, Message: f.message, Fixable: f.fixable})
+	}
+	sort.SliceStable(issues, func(a, b int) bool {
+		if issues[a].Line != issues[b].Line {
+			return issues[a].Line < issues[b].Line
+		}
+		return issues[a].Column < issues[b].Column
+	})
+	return issues
+}
+
+// FixSection applies the safe fixes for LintSection's fixable issues: it
+// removes markdown headings, bullets, and emphasis, separates tags from the
+// text around them, and collapses stacked pauses into one, keeping a
+// [long pause] if any. Unknown tags and questions without a pause need a
+// rewrite and are left alone.
+func FixSection(text string) string {
+	text = markdownHeadingLine.ReplaceAllString(text, "")
+	text = markdownBulletLine.ReplaceAllString(text, "")
+	text = markdownEmphasis.ReplaceAllString(text, "$1$2")
+	text = gluedTagBeforePattern.ReplaceAllStringFunc(text, func(m string) string {
+		_, size := utf8.DecodeRuneInString(m)
+		return m[:size] + " " + m[size:]
+	})
+	text = gluedTagAfterPattern.ReplaceAllStringFunc(text, func(m string) string {
+		_, size := utf8.DecodeLastRuneInString(m)
+		return m[:len(m)-size] + " " + m[len(m)-size:]
+	})
+	return stackedPausesPattern.ReplaceAllStringFunc(text, func(m string) string {
+		if strings.Contains(m, LongPauseTag) {
+			return LongPauseTag
+		}
+		return ShortPauseTag
+	})
+}
+
+// isSpoken reports whether a sentence fragment has words left once its tags
+// are removed.
+func isSpoken(sentence string) bool {
+	return strings.IndexFunc(audioTagPattern.ReplaceAllString(sentence, ""), func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) >= 0
+}
+
+// sentenceSnippet shortens a sentence for a message, keeping its last line
+// and at most 40 characters.
+func sentenceSnippet(sentence string) string {
+	sentence = strings.TrimSpace(sentence[strings.LastIndex(sentence, "\n")+1:])
+	if runes := []rune(sentence); len(runes) > 40 {
+		return "..." + string(runes[len(runes)-37:])
+	}
+	return sentence
+}
+
+// lintPosition converts a byte offset into a 1-based line and column.
+func lintPosition(text string, offset int) (int, int) {
+	before := text[:offset]
+	line := strings.Count(before, "\n") + 1
+	lineStart := strings.LastIndex(before, "\n") + 1
+	return line, utf8.RuneCountInString(before[lineStart:]) + 1
+}
@@ -0,0 +1,81 @@
+package podcast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintSection(t *testing.T) {
+	text := "## Brain Game\n" +
+		"[excited] Ready to play? Here we go![short pause]\n" +
+		"- Is the sun a star? [short pause] [long pause] **Yes!**\n" +
+		"[spooky]Boo. What is your favorite planet? [long pause]"
+	issues := LintSection("game", text)
+	var got []string
+	for _, issue := range issues {
+		if issue.SectionID != "game" {
+			t.Fatalf("expected the section ID on %+v", issue)
+		}
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`1:1: markdown: markdown heading "##" is read aloud (fixable)`,
+		`2:24: question-pause: question "[excited] Ready to play?" is not followed by [short pause] or [long pause]`,
+		`2:37: tag-spacing: tag [short pause] is attached to the text before it (fixable)`,
+		`3:1: markdown: bullet "-" is read aloud (fixable)`,
+		`3:36: stacked-pauses: [long pause] follows another pause (fixable)`,
+		`3:49: markdown: emphasis asterisks are read aloud (fixable)`,
+		`4:1: tag-spacing: tag [spooky] is attached to the text after it (fixable)`,
+		`4:1: unknown-tag: unknown tag [spooky]`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected issues:\n%s", strings.Join(got, "\n"))
+	}
+
+	fixed := FixSection(text)
+	wantFixed := "Brain Game\n" +
+		"[excited] Ready to play? Here we go! [short pause]\n" +
+		"Is the sun a star? [long pause] Yes!\n" +
+		"[spooky] Boo. What is your favorite planet? [long pause]"
+	if fixed != wantFixed {
+		t.Fatalf("unexpected fix:\n%s", fixed)
+	}
+	for _, issue := range LintSection("game", fixed) {
+		if issue.Fixable {
+			t.Fatalf("expected every fixable issue to be fixed, got %s", issue)
+		}
+	}
+}
+
+func TestLintSectionAcceptsPromptFormats(t *testing.T) {
+	for _, text := range []string{
+		"[excited][cheerful] We have a cool mystery today!",
+		"Here's the first statement: Owls hoot. \"Fact or fib?\" [short pause] That statement was a... Fact!",
+		"Which one would you pick? [playful] [long pause] Great choice.",
+		testGameRoundText,
+	} {
+		if issues := LintSection("game", text); len(issues) != 0 {
+			t.Fatalf("expected no issues for %q, got %v", text, issues)
+		}
+	}
+}
+
+const testGameRoundText = "Today's challenge: invent a hat that keeps rain away.\n\n" +
+	"First question: what would your hat be made of? [long pause] What a great idea!"
+
+func TestSplitOnPauses(t *testing.T) {
+	segments := SplitOnPauses("Ready? [short pause] Think big. [long pause] Done.")
+	want := []PauseSegment{
+		{Text: "Ready? ", PauseTag: ShortPauseTag},
+		{Text: " Think big. ", PauseTag: LongPauseTag},
+		{Text: " Done."},
+	}
+	if len(segments) != len(want) {
+		t.Fatalf("unexpected segments: %+v", segments)
+	}
+	for i := range want {
+		if segments[i] != want[i] {
+			t.Fatalf("segment %d: got %+v, want %+v", i, segments[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package podcast
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Locale holds the language-specific pieces of an episode: the language's
+// name for prompt instructions, date formatting, the fixed phrases the host
+// says, and the built-in safety terms.
+type Locale struct {
+	Tag        string // BCP-47 tag as configured, e.g. "es-MX"
+	Base       string // primary language subtag, e.g. "es"
+	Name       string // English name used in prompt instructions, e.g. "Spanish"
+	NativeName string // e.g. "español"
+
+	weekdays  [7]string
+	months    [12]string
+	longDate  func(weekday, month string, day, year int) string
+	shortDate func(weekday, month string, day int) string
+	phrases   localePhrases
+}
+
+type localePhrases struct {
+	day, friday, weekend string
+	holidayToday         string
+	holidayTomorrow      string
+	warmWish             string // %s is the short date
+	gameStart            string // %[1]s is the weekday, %[2]s the game name
+	bilingualGameInvite  string // %s is the game language's native name
+}
+
+var bcp47Pattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+var locales = map[string]Locale{
+	"en": {
+		Name:       "English",
+		NativeName: "English",
+		weekdays:   [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months:     [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		longDate: func(weekday, month string, day, year int) string {
+			return fmt.Sprintf("%s, %s %d, %d", weekday, month, day, year)
+		},
+		shortDate: func(weekday, month string, day int) string {
+			return fmt.Sprintf("%s, %s %d", weekday, month, day)
+		},
+		phrases: localePhrases{
+			day:                 "day",
+			friday:              "Fri-YAY!",
+			weekend:             "weekend",
+			holidayToday:        "If you're celebrating, I hope you have a wonderful holiday today.",
+			holidayTomorrow:     "If you're celebrating, I hope you have a wonderful holiday tomorrow.",
+			warmWish:            "I hope everyone has an amazing %s.",
+			gameStart:           "It's %[1]s so you know what that means! It's time to play %[2]s.",
+			bilingualGameInvite: "Now let's play our game in %s!",
+		},
+	},
+	"es": {
+		Name:       "Spanish",
+		NativeName: "español",
+		weekdays:   [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		months:     [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		longDate: func(weekday, month string, day, year int) string {
+			return fmt.Sprintf("%s, %d de %s de %d", weekday, day, month, year)
+		},
+		shortDate: func(weekday, month string, day int) string {
+			return fmt.Sprintf("%s %d de %s", weekday, day, month)
+		},
+		phrases: localePhrases{
+			day:                 "día",
+			friday:              "¡viernes feliz!",
+			weekend:             "fin de semana",
+			holidayToday:        "Si lo están celebrando, les deseo un día festivo maravilloso.",
+			holidayTomorrow:     "Si lo van a celebrar, les deseo un día festivo maravilloso mañana.",
+			warmWish:            "Espero que todos tengan un %s increíble.",
+			gameStart:           "¡Es %[1]s, así que ya saben lo que significa! Es hora de jugar %[2]s.",
+			bilingualGameInvite: "¡Ahora juguemos nuestro juego en %s!",
+		},
+	},
+}
+
+// DefaultLocale returns the English locale.
+func DefaultLocale() Locale {
+	locale, _ := LookupLocale("en")
+	return locale
+}
+
+// LookupLocale returns the locale for a BCP-47 tag such as "es" or "es-MX".
+// Regional tags use their primary language. An empty tag is English.
+func LookupLocale(tag string) (Locale, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		tag = "en"
+	}
+	if !bcp47Pattern.MatchString(tag) {
+		return Locale{}, fmt.Errorf("invalid language tag: %q", tag)
+	}
+	base := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+	locale, ok := locales[base]
+	if !ok {
+		return Locale{}, fmt.Errorf("unsupported language: %q (supported: %s)", tag, strings.Join(SupportedLanguages(), ", "))
+	}
+	locale.Tag = tag
+	locale.Base = base
+	return locale, nil
+}
+
+// SupportedLanguages returns the primary language subtags with built-in locales.
+func SupportedLanguages() []string {
+	langs := make([]string, 0, len(locales))
+	for lang := range locales {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// IsEnglish reports whether the locale's primary language is English.
+func (l Locale) IsEnglish() bool {
+	return l.Base == "en"
+}
+
+// Weekday returns the localized weekday name.
+func (l Locale) Weekday(t time.Time) string {
+	return l.weekdays[t.Weekday()]
+}
+
+// LongDate formats a date like "Monday, January 2, 2006" or
+// "lunes, 2 de enero de 2006".
+func (l Locale) LongDate(t time.Time) string {
+	y, m, d := t.Date()
+	return l.longDate(l.weekdays[t.Weekday()], l.months[m-1], d, y)
+}
+
+// ShortDate formats a date like "Monday, January 2" or "lunes 2 de enero".
+func (l Locale) ShortDate(t time.Time) string {
+	_, m, d := t.Date()
+	return l.shortDate(l.weekdays[t.Weekday()], l.months[m-1], d)
+}
+
+// DayPhrase returns the phrase the intro uses for the day: "day", "Fri-YAY!",
+// or "weekend" in English.
+func (l Locale) DayPhrase(t time.Time) string {
+	switch t.Weekday() {
+	case time.Friday:
+		return l.phrases.friday
+	case time.Saturday, time.Sunday:
+		return l.phrases.weekend
+	}
+	return l.phrases.day
+}
+
+// GameStart returns the line that opens the brain game.
+func (l Locale) GameStart(t time.Time, game string) string {
+	return fmt.Sprintf(l.phrases.gameStart, l.Weekday(t), game)
+}
+
+// LanguageInstruction tells the model which language to write in. It is empty
+// for English so the built-in English prompts are unchanged.
+func (l Locale) LanguageInstruction() string {
+	if l.IsEnglish() {
+		return ""
+	}
+	return fmt.Sprintf("Write everything the listener will hear in %s (%s), even though these instructions are in English. "+
+		"Keep bracketed audio tags such as [short pause] and [long pause] in English exactly as written.", l.Name, l.NativeName)
+}
+
+// GameLanguageInstruction tells the game prompt which language to play in.
+// When the game language differs from the episode language, the game opens
+// with an invitation in the episode language and uses simple words for
+// language learners.
+func GameLanguageInstruction(episode, game Locale) string {
+	if episode.Base == game.Base {
+		return episode.LanguageInstruction()
+	}
+	return fmt.Sprintf("Play this game in %s (%s) so listeners can practice a second language; the rest of the episode is in %s. "+
+		"Begin with exactly this sentence in %s: %q Then use short, simple %s sentences that a beginner can follow, and repeat key words. "+
+		"Keep bracketed audio tags such as [short pause] and [long pause] in English exactly as written.",
+		game.Name, game.NativeName, episode.Name, episode.Name,
+		fmt.Sprintf(episode.phrases.bilingualGameInvite, game.NativeName), game.Name)
+}
@@ -0,0 +1,139 @@
+package podcast
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupLocale(t *testing.T) {
+	locale, err := LookupLocale("es-MX")
+	if err != nil {
+		t.Fatalf("LookupLocale: %v", err)
+	}
+	if locale.Tag != "es-MX" || locale.Base != "es" || locale.Name != "Spanish" {
+		t.Fatalf("unexpected locale: %+v", locale)
+	}
+	if locale, err := LookupLocale(""); err != nil || !locale.IsEnglish() {
+		t.Fatalf("expected English for empty tag, got %+v, %v", locale, err)
+	}
+	for _, tag := range []string{"fr", "not a tag"} {
+		if _, err := LookupLocale(tag); err == nil {
+			t.Fatalf("expected error for %q", tag)
+		}
+	}
+}
+
+func TestLocaleFormatsDates(t *testing.T) {
+	date := time.Date(2026, 1, 23, 0, 0, 0, 0, time.UTC)
+	en := DefaultLocale()
+	if got := en.LongDate(date); got != "Friday, January 23, 2026" {
+		t.Fatalf("unexpected English long date: %q", got)
+	}
+	es, err := LookupLocale("es")
+	if err != nil {
+		t.Fatalf("LookupLocale: %v", err)
+	}
+	if got := es.LongDate(date); got != "viernes, 23 de enero de 2026" {
+		t.Fatalf("unexpected Spanish long date: %q", got)
+	}
+	if got := es.ShortDate(date); got != "viernes 23 de enero" {
+		t.Fatalf("unexpected Spanish short date: %q", got)
+	}
+	if got := es.GameStart(date, "Adivina el animal"); !strings.HasPrefix(got, "¡Es viernes") || !strings.HasSuffix(got, "jugar Adivina el animal.") {
+		t.Fatalf("unexpected Spanish game start: %q", got)
+	}
+}
+
+func TestSpanishPromptsAndBilingualGame(t *testing.T) {
+	es, err := LookupLocale("es")
+	if err != nil {
+		t.Fatalf("LookupLocale: %v", err)
+	}
+	date := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)
+
+	prompts, err := LoadPrompts(DefaultIdentity(), es, es, "")
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	system, _, err := prompts.ScriptPrompts("Abejas", date)
+	if err != nil {
+		t.Fatalf("ScriptPrompts: %v", err)
+	}
+	if !strings.Contains(system, "in Spanish (español)") {
+		t.Fatalf("expected language instruction in system prompt: %q", system)
+	}
+	specs, err := DefaultShow().Specs(prompts, "Abejas", date)
+	if err != nil {
+		t.Fatalf("Specs: %v", err)
+	}
+	if !strings.Contains(specs[0].Prompt, "lunes, 19 de enero de 2026") || specs[0].Language != "es" {
+		t.Fatalf("expected Spanish date in intro spec: %+v", specs[0])
+	}
+
+	bilingual, err := LoadPrompts(DefaultIdentity(), DefaultLocale(), es, "")
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	system, _, err = bilingual.ScriptPrompts("Bees", date)
+	if err != nil {
+		t.Fatalf("ScriptPrompts: %v", err)
+	}
+	if strings.Contains(system, "Spanish") {
+		t.Fatalf("expected English script system prompt: %q", system)
+	}
+	specs, err = DefaultShow().Specs(bilingual, "Bees", date)
+	if err != nil {
+		t.Fatalf("Specs: %v", err)
+	}
+	game := specs[2]
+	if game.Language != "es" || !strings.Contains(game.Prompt, "Play this game in Spanish") || !strings.Contains(game.Prompt, "Now let's play our game in español!") {
+		t.Fatalf("expected bilingual game spec: %+v", game)
+	}
+	if specs[0].Language != "en" {
+		t.Fatalf("expected English intro, got %q", specs[0].Language)
+	}
+}
+
+func TestLoadPromptsUsesLanguageDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "script-system.tmpl", "Top level.")
+	writeTemplate(t, dir, "es/script-system.tmpl", "Eres {{.HostName}}.")
+	writeTemplate(t, dir, "es/sections/outro.tmpl", "Despídete de {{.Topic}}.")
+	es, err := LookupLocale("es-MX")
+	if err != nil {
+		t.Fatalf("LookupLocale: %v", err)
+	}
+	prompts, err := LoadPrompts(DefaultIdentity(), es, es, dir)
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	date := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)
+	system, _, err := prompts.ScriptPrompts("Abejas", date)
+	if err != nil {
+		t.Fatalf("ScriptPrompts: %v", err)
+	}
+	if system != "Eres Jessica." {
+		t.Fatalf("expected Spanish override, got %q", system)
+	}
+	specs, err := DefaultShow().Specs(prompts, "Abejas", date)
+	if err != nil {
+		t.Fatalf("Specs: %v", err)
+	}
+	if specs[3].Prompt != "Despídete de Abejas." {
+		t.Fatalf("expected Spanish section override, got %q", specs[3].Prompt)
+	}
+}
+
+func writeTemplate(t *testing.T, dir, name, body string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
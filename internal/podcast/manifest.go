@@ -0,0 +1,269 @@
+package podcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"yodex/internal/config"
+	"yodex/internal/storage"
+)
+
+// manifest is a small JSON document, such as the topic history or backlog,
+// that overlapping runs read and rewrite.
+type manifest interface {
+	// read returns the document, or nil if it does not exist yet.
+	read(ctx context.Context) ([]byte, error)
+	// update saves what fn returns for the latest document (nil if it does not
+	// exist yet) without losing writes from overlapping runs. fn may be called
+	// more than once.
+	update(ctx context.Context, fn func(data []byte) ([]byte, error)) error
+	// Location describes where the document lives, for logs and messages.
+	Location() string
+}
+
+// manifestStore is the S3 client manifests are read from and written to.
+type manifestStore interface {
+	DownloadBytesWithETag(ctx context.Context, key string) ([]byte, string, error)
+	UploadBytesIf(ctx context.Context, key string, data []byte, contentType, cacheControl, etag string) error
+	Prefix() string
+}
+
+// Retry and locking limits for manifest updates; replaced in tests.
+var (
+	// manifestAttempts caps conditional S3 writes per update.
+	manifestAttempts = 5
+	// manifestRetryDelay is the pause after a lost S3 write race, with
+	// jitter so competing runs do not retry in lockstep.
+	manifestRetryDelay = func(attempt int) time.Duration {
+		return time.Duration(attempt)*200*time.Millisecond + rand.N(100*time.Millisecond)
+	}
+	// manifestLockTimeout is how long to wait for the local lock file.
+	manifestLockTimeout = 30 * time.Second
+	// manifestLockStale is the age at which a lock file left behind by a
+	// crashed run is removed.
+	manifestLockStale = 2 * time.Minute
+)
+
+var newManifestStore = func(ctx context.Context, cfg config.Config) (manifestStore, error) {
+	return storage.New(ctx, cfg.S3Bucket, cfg.S3Prefix, cfg.Region)
+}
+
+// openManifest returns the S3 object name under the prefix when a bucket is
+// configured and the local file at localPath otherwise. An empty localPath
+// reads as missing and drops saves.
+func openManifest(ctx context.Context, cfg config.Config, name, localPath string) (manifest, error) {
+	if cfg.S3Bucket != "" {
+		store, err := newManifestStore(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		key := name
+		if prefix := store.Prefix(); prefix != "" {
+			key = path.Join(prefix, name)
+		}
+		return s3Manifest{store: store, key: key}, nil
+	}
+	return fileManifest{path: strings.TrimSpace(localPath)}, nil
+}
+
+// s3Manifest stores the document as an S3 object. Updates are conditional on
+// the ETag read, so a run that loses a race rereads the object and tries again.
+type s3Manifest struct {
+	store manifestStore
+	key   string
+}
+
+func (s s3Manifest) read(ctx context.Context) ([]byte, error) {
+	data, _, err := s.load(ctx)
+	return data, err
+}
+
+// load returns the object and its ETag, both empty when the object does not
+// exist yet.
+func (s s3Manifest) load(ctx context.Context) ([]byte, string, error) {
+	data, etag, err := s.store.DownloadBytesWithETag(ctx, s.key)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("download %s: %w", s.key, err)
+	}
+	return data, etag, nil
+}
+
+func (s s3Manifest) update(ctx context.Context, fn func([]byte) ([]byte, error)) error {
+	for attempt := 1; ; attempt++ {
+		current, etag, err := s.load(ctx)
+		if err != nil {
+			return err
+		}
+		data, err := fn(current)
+		if err != nil {
+			return err
+		}
+		err = s.store.UploadBytesIf(ctx, s.key, data, "application/json", "no-cache", etag)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, storage.ErrPreconditionFailed) || attempt >= manifestAttempts {
+			return fmt.Errorf("upload %s: %w", s.key, err)
+		}
+		slog.Warn("manifest changed during update; retrying", "key", s.key, "attempt", attempt)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(manifestRetryDelay(attempt)):
+		}
+	}
+}
+
+func (s s3Manifest) Location() string { return "s3:" + s.key }
+
+// fileManifest stores the document in a local file. An empty path disables
+// it. Updates hold a lock file next to it and replace the file by atomic
+// rename, so readers never see a partial write.
+type fileManifest struct {
+	path string
+}
+
+func (f fileManifest) read(ctx context.Context) ([]byte, error) {
+	if f.path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", filepath.Base(f.path), err)
+	}
+	return data, nil
+}
+
+func (f fileManifest) update(ctx context.Context, fn func([]byte) ([]byte, error)) error {
+	if f.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	unlock, err := lockManifest(ctx, f.path+".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := f.read(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := fn(current)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), "."+filepath.Base(f.path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+func (f fileManifest) Location() string { return f.path }
+
+// lockManifest creates the lock file, waiting while another run holds it,
+// and returns the function that releases it. The lock file holds a token
+// unique to this call, and the release removes it only while it still holds
+// that token. A lock older than manifestLockStale is assumed abandoned and
+// taken over.
+func lockManifest(ctx context.Context, lockPath string) (func(), error) {
+	name := filepath.Base(strings.TrimSuffix(lockPath, ".lock"))
+	token := fmt.Sprintf("%d-%016x", os.Getpid(), rand.Uint64())
+	deadline := time.Now().Add(manifestLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, werr := fmt.Fprintln(f, token)
+			if cerr := f.Close(); werr == nil {
+				werr = cerr
+			}
+			if werr != nil {
+				_ = os.Remove(lockPath)
+				return nil, fmt.Errorf("lock %s: %w", name, werr)
+			}
+			return func() { releaseManifestLock(lockPath, token) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("lock %s: %w", name, err)
+		}
+		if takeOverStaleLock(lockPath, token) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another run (remove %s if no run is active)", name, lockPath)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+// takeOverStaleLock moves a stale lock file aside under a name unique to
+// token and deletes it, reporting whether it did. Moving rather than removing
+// by path means that when two runs find the same stale lock, only one of them
+// gets it; if the file moved turns out to be a fresh lock another run has
+// just created, it is put back.
+func takeOverStaleLock(lockPath, token string) bool {
+	info, err := os.Stat(lockPath)
+	if err != nil || time.Since(info.ModTime()) <= manifestLockStale {
+		return false
+	}
+	aside := lockPath + "." + token + ".stale"
+	if err := os.Rename(lockPath, aside); err != nil {
+		return false
+	}
+	defer os.Remove(aside)
+	if moved, err := os.Stat(aside); err == nil && time.Since(moved.ModTime()) <= manifestLockStale {
+		// Not the stale lock: put the fresh one back unless yet another run
+		// has locked in the meantime.
+		_ = os.Link(aside, lockPath)
+		return false
+	}
+	slog.Warn("removing stale lock", "path", lockPath, "age", time.Since(info.ModTime()).String())
+	return true
+}
+
+// releaseManifestLock removes the lock file if it still holds token, so a run
+// whose lock was taken over as stale does not remove the new holder's lock.
+func releaseManifestLock(lockPath, token string) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return
+	}
+	if strings.TrimSpace(string(data)) != token {
+		slog.Warn("lock was taken over by another run; leaving it in place", "path", lockPath)
+		return
+	}
+	_ = os.Remove(lockPath)
+}
@@ -0,0 +1,53 @@
+package podcast
+
+import "strings"
+
+// Pause tags mark where the listener gets time to answer; audio inserts
+// silence for them instead of speaking them.
+const (
+	LongPauseTag  = "[long pause]"
+	ShortPauseTag = "[short pause]"
+)
+
+// PauseSegment is the text spoken before a pause tag, or the text after the
+// last one when PauseTag is empty.
+type PauseSegment struct {
+	Text     string
+	PauseTag string
+}
+
+// SplitOnPauses splits text at each pause tag. Segments are contiguous, so the
+// offset of a segment is the sum of the earlier segments' text and tag
+// lengths.
+func SplitOnPauses(text string) []PauseSegment {
+	var segments []PauseSegment
+	for len(text) > 0 {
+		longIndex := strings.Index(text, LongPauseTag)
+		shortIndex := strings.Index(text, ShortPauseTag)
+		nextIndex, tag := nextPauseTag(longIndex, shortIndex)
+		if nextIndex == -1 {
+			segments = append(segments, PauseSegment{Text: text})
+			break
+		}
+		segment := PauseSegment{Text: text[:nextIndex], PauseTag: tag}
+		segments = append(segments, segment)
+		text = text[nextIndex+len(tag):]
+	}
+	return segments
+}
+
+func nextPauseTag(longIndex, shortIndex int) (int, string) {
+	if longIndex == -1 && shortIndex == -1 {
+		return -1, ""
+	}
+	if longIndex == -1 {
+		return shortIndex, ShortPauseTag
+	}
+	if shortIndex == -1 {
+		return longIndex, LongPauseTag
+	}
+	if longIndex <= shortIndex {
+		return longIndex, LongPauseTag
+	}
+	return shortIndex, ShortPauseTag
+}
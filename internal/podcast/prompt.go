@@ -1,7 +1,6 @@
 package podcast
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 )
@@ -14,39 +13,6 @@ var requiredSections = []string{
 	"## Outro",
 }
 
-const systemPrompt = "You are an expert kid's science podcaster for advanced 7-year-olds. " +
-	"Be engaging, positive, accurate, and safe. " +
-	"Use clear explanations and relatable analogies. " +
-	"Avoid scary, graphic, or unsafe content."
-
-// BuildScriptPrompts returns the system and base user prompt for section generation.
-func BuildScriptPrompts(topic string) (string, string, error) {
-	topic = strings.TrimSpace(topic)
-	if topic == "" {
-		return "", "", errors.New("topic is required")
-	}
-	var b strings.Builder
-	fmt.Fprintf(&b, "You are writing a kid-friendly science podcast episode for the \"Curious World Podcast\" hosted by Jessica, about %q. ", topic)
-	b.WriteString("Each request is for one section of the episode. ")
-	b.WriteString("Write in a friendly narrator voice, no headings or labels. ")
-	b.WriteString("Use inflection tags generously throughout the section to add energy and texture. ")
-	b.WriteString("Place tags at the start of the line or sentence where they apply (e.g., before a punchline), not at the end. ")
-	b.WriteString("You can stack multiple tags when it fits (e.g., [playful][excited]). ")
-	b.WriteString("Use a mix of upbeat emotional tags (e.g., [happy], [excited], [curious], [encouraging], [cheerful], [warm], [playful], [storytelling], [anticipation]) and light non-verbal tags (e.g., [laughing], [chuckles], [short pause]). ")
-	b.WriteString("For yes/no questions, add a [short pause] tag immediately after the question. ")
-	b.WriteString("For free-form questions, add a [long pause] tag immediately after the question. ")
-	b.WriteString("When unsure, use [short pause] unless the question invites imagination or reflection; then use [long pause]. ")
-	b.WriteString("Ask one question at a time; if you need multiple questions, split them into separate sentences and include a pause after each question. ")
-	b.WriteString("Always include a space before any tag; never attach tags directly to punctuation. ")
-	b.WriteString("After the pause, follow up by enthusiastically affirming the listener without assuming their specific answer; keep affirmations generic and vary them (celebrate effort or curiosity). ")
-	b.WriteString("Avoid negative, tired, or bored tags; only use voice-related tags (no music or sound effects). ")
-	b.WriteString("Examples: \"[excited][cheerful] We have a cool mystery today!\" \"[joking][playful] Why did the comet bring a suitcase?\" \"[laughing][anticipation] Because it was going on a long trip!\" ")
-	b.WriteString("Keep tags brief, natural, and kid-appropriate, and never let a tag change the meaning of the sentence. ")
-	b.WriteString("Keep it upbeat, kid-safe, accurate, and easy to follow. Avoid unsafe instructions.")
-	user := b.String()
-	return systemPrompt, user, nil
-}
-
 // RequiredSections returns the list of required section headers.
 func RequiredSections() []string {
 	sections := make([]string, 0, len(requiredSections))
@@ -127,27 +93,3 @@ func headingLevel(line string) int {
 	}
 	return level
 }
-
-var safetyTerms = []string{
-	"suicide",
-	"self-harm",
-	"bomb",
-	"explosive",
-	"gun",
-	"weapon",
-	"poison",
-	"cocaine",
-	"heroin",
-	"sexual",
-}
-
-// BasicSafetyCheck performs a simple lexical scan for unsafe terms.
-func BasicSafetyCheck(text string) error {
-	lower := strings.ToLower(text)
-	for _, term := range safetyTerms {
-		if strings.Contains(lower, term) {
-			return fmt.Errorf("unsafe term detected: %s", term)
-		}
-	}
-	return nil
-}
@@ -3,10 +3,11 @@ package podcast
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
-func TestBuildScriptPrompts(t *testing.T) {
-	system, user, err := BuildScriptPrompts("Clouds and Rain")
+func TestScriptPrompts(t *testing.T) {
+	system, user, err := DefaultPrompts().ScriptPrompts("Clouds and Rain", time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -49,3 +50,48 @@ func TestBasicSafetyCheck(t *testing.T) {
 		t.Fatalf("expected unsafe term error")
 	}
 }
+
+func TestEpisodeSchemaIsStrict(t *testing.T) {
+	schema := EpisodeSchema()
+	sections := schema["properties"].(map[string]any)["sections"].(map[string]any)
+	item := sections["items"].(map[string]any)
+	if item["additionalProperties"] != false {
+		t.Fatalf("expected section items to disallow additional properties")
+	}
+	props := item["properties"].(map[string]any)
+	required := item["required"].([]string)
+	if len(required) != len(props) {
+		t.Fatalf("expected every section property to be required, got %v", required)
+	}
+}
+
+func TestParseEpisodeJSONReadsSectionMetadata(t *testing.T) {
+	raw := `{"title":"Bees","sections":[{"section_id":"topic","text":"Bees dance.","key_facts":["Bees dance to share directions."],"vocabulary":["waggle"],"questions":[]}]}`
+	ep, err := ParseEpisodeJSON(raw)
+	if err != nil {
+		t.Fatalf("ParseEpisodeJSON: %v", err)
+	}
+	if len(ep.Sections) != 1 || len(ep.Sections[0].KeyFacts) != 1 || ep.Sections[0].Vocabulary[0] != "waggle" {
+		t.Fatalf("unexpected sections: %+v", ep.Sections)
+	}
+}
+
+func TestBuildStructuredEpisodePromptOrdersGameBeforeOutro(t *testing.T) {
+	specs, err := DefaultShow().Specs(DefaultPrompts(), "Volcanoes", time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Specs: %v", err)
+	}
+	prompt := BuildStructuredEpisodePrompt("Base prompt.", specs, "Game rules here.")
+	topic := strings.Index(prompt, "Section ID: topic")
+	game := strings.Index(prompt, "Section ID: game")
+	outro := strings.Index(prompt, "Section ID: outro")
+	if topic < 0 || game < 0 || outro < 0 {
+		t.Fatalf("missing sections in prompt: %q", prompt)
+	}
+	if !(topic < game && game < outro) {
+		t.Fatalf("expected game between topic and outro: %q", prompt)
+	}
+	if !strings.Contains(prompt, "Game rules here.") {
+		t.Fatalf("expected game prompt included")
+	}
+}
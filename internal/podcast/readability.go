@@ -0,0 +1,221 @@
+package podcast
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+//go:embed common_words.txt
+var commonWordsFile string
+
+var commonWords = func() map[string]bool {
+	words := map[string]bool{}
+	for _, line := range strings.Split(commonWordsFile, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, word := range strings.Fields(line) {
+			words[word] = true
+		}
+	}
+	return words
+}()
+
+var (
+	audioTagPattern = regexp.MustCompile(`\[[^\]]*\]`)
+	wordPattern     = regexp.MustCompile(`[A-Za-z]+(?:'[A-Za-z]+)?|[0-9]+`)
+)
+
+// Readability holds readability metrics for a block of text. Inflection and
+// pause tags are ignored.
+type Readability struct {
+	Words              int     `json:"words"`
+	Sentences          int     `json:"sentences"`
+	Syllables          int     `json:"syllables"`
+	FleschKincaidGrade float64 `json:"fleschKincaidGrade"`
+	AvgSentenceLength  float64 `json:"avgSentenceLength"`
+	RareWordRatio      float64 `json:"rareWordRatio"`
+}
+
+// SectionReadability pairs a section ID with its metrics.
+type SectionReadability struct {
+	SectionID string `json:"sectionId"`
+	Readability
+}
+
+// StripAudioTags removes bracketed inflection and pause tags from text.
+func StripAudioTags(text string) string {
+	return strings.Join(strings.Fields(audioTagPattern.ReplaceAllString(text, " ")), " ")
+}
+
+// AnalyzeReadability computes Flesch-Kincaid grade, average sentence length,
+// and the share of words outside the common-word list.
+func AnalyzeReadability(text string) Readability {
+	var r Readability
+	rare := 0
+	clean := StripAudioTags(text)
+	for _, sentence := range splitSentences(clean) {
+		words := wordPattern.FindAllString(sentence, -1)
+		if len(words) == 0 {
+			continue
+		}
+		r.Sentences++
+		for _, word := range words {
+			r.Words++
+			r.Syllables += countSyllables(word)
+			if isRareWord(word) {
+				rare++
+			}
+		}
+	}
+	if r.Words == 0 {
+		return Readability{}
+	}
+	words := float64(r.Words)
+	sentences := float64(r.Sentences)
+	r.AvgSentenceLength = round2(words / sentences)
+	r.FleschKincaidGrade = round2(0.39*(words/sentences) + 11.8*(float64(r.Syllables)/words) - 15.59)
+	r.RareWordRatio = round2(float64(rare) / words)
+	return r
+}
+
+// AnalyzeEpisodeReadability returns metrics for every section, in section order.
+func AnalyzeEpisodeReadability(episode Episode) []SectionReadability {
+	out := make([]SectionReadability, 0, len(episode.Sections))
+	for _, section := range episode.Sections {
+		out = append(out, SectionReadability{
+			SectionID:   section.SectionID,
+			Readability: AnalyzeReadability(section.Text),
+		})
+	}
+	return out
+}
+
+// BuildSimplifyPrompt asks the model to rewrite a section at or below the grade
+// ceiling without changing its tags or structure.
+func BuildSimplifyPrompt(audience, sectionID, text string, metrics Readability, gradeCeiling float64) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rewrite the %s section below so %s can follow it by ear. ", sectionID, audience)
+	fmt.Fprintf(&b, "It currently reads at about grade %.1f with %.1f words per sentence; aim for grade %.1f or lower. ", metrics.FleschKincaidGrade, metrics.AvgSentenceLength, gradeCeiling)
+	b.WriteString("Use shorter sentences and everyday words, and explain any science word you keep. ")
+	b.WriteString("Keep every fact, question, joke, and paragraph in the same order. ")
+	b.WriteString("Keep every bracketed tag such as [excited] or [short pause] exactly as written and in the same place relative to the sentence it belongs to. ")
+	b.WriteString("Return only the rewritten section text.\n\n")
+	b.WriteString("Section text:\n")
+	b.WriteString(strings.TrimSpace(text))
+	return b.String()
+}
+
+// TagsPreserved reports whether rewritten text keeps every bracketed tag of
+// the original, the same number of times, with the pause tags in the same
+// order.
+func TagsPreserved(original, rewritten string) bool {
+	if !slices.Equal(pauseTags(original), pauseTags(rewritten)) {
+		return false
+	}
+	counts := make(map[string]int)
+	for _, tag := range audioTagPattern.FindAllString(original, -1) {
+		counts[strings.ToLower(tag)]++
+	}
+	for _, tag := range audioTagPattern.FindAllString(rewritten, -1) {
+		counts[strings.ToLower(tag)]--
+	}
+	for _, n := range counts {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func pauseTags(text string) []string {
+	var tags []string
+	for _, tag := range audioTagPattern.FindAllString(text, -1) {
+		tag = strings.ToLower(tag)
+		if strings.Contains(tag, "pause") {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func isRareWord(word string) bool {
+	w := strings.ToLower(word)
+	if w[0] >= '0' && w[0] <= '9' {
+		return false
+	}
+	if i := strings.IndexByte(w, '\''); i >= 0 {
+		w = w[:i]
+	}
+	for _, candidate := range baseForms(w) {
+		if commonWords[candidate] {
+			return false
+		}
+	}
+	return true
+}
+
+// baseForms returns the word and plausible uninflected forms of it.
+func baseForms(w string) []string {
+	forms := []string{w}
+	for _, suffix := range []string{"ies", "ied", "ier", "iest", "ily"} {
+		if stem, ok := strings.CutSuffix(w, suffix); ok && len(stem) >= 2 {
+			forms = append(forms, stem+"y")
+		}
+	}
+	for _, suffix := range []string{"s", "es", "ed", "d", "ing", "er", "est", "ly"} {
+		stem, ok := strings.CutSuffix(w, suffix)
+		if !ok || len(stem) < 2 {
+			continue
+		}
+		forms = append(forms, stem, stem+"e")
+		if n := len(stem); n >= 3 && stem[n-1] == stem[n-2] {
+			forms = append(forms, stem[:n-1])
+		}
+	}
+	return forms
+}
+
+// countSyllables estimates syllables by counting vowel groups, with the usual
+// adjustments for a silent final e and "-ed" endings.
+func countSyllables(word string) int {
+	w := strings.ToLower(word)
+	if w[0] >= '0' && w[0] <= '9' {
+		return 1
+	}
+	if i := strings.IndexByte(w, '\''); i >= 0 {
+		w = w[:i]
+	}
+	if len(w) <= 3 {
+		return 1
+	}
+	count := 0
+	prevVowel := false
+	for _, r := range w {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+	switch {
+	case strings.HasSuffix(w, "le") && !strings.ContainsRune("aeiouy", rune(w[len(w)-3])):
+	case strings.HasSuffix(w, "e"):
+		count--
+	case strings.HasSuffix(w, "ed") && !strings.HasSuffix(w, "ted") && !strings.HasSuffix(w, "ded"):
+		count--
+	}
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,81 @@
+package podcast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeReadabilityIgnoresTags(t *testing.T) {
+	plain := AnalyzeReadability("The cat sat on the bed. Do you like cats?")
+	tagged := AnalyzeReadability("[excited] The cat sat on the bed. [curious] Do you like cats? [short pause]")
+	if plain != tagged {
+		t.Fatalf("expected tags to be ignored: %+v vs %+v", plain, tagged)
+	}
+	if plain.Words != 10 || plain.Sentences != 2 || plain.AvgSentenceLength != 5 {
+		t.Fatalf("unexpected counts: %+v", plain)
+	}
+	if plain.RareWordRatio != 0 {
+		t.Fatalf("expected no rare words, got %v", plain.RareWordRatio)
+	}
+}
+
+func TestAnalyzeReadabilityGradesHarderText(t *testing.T) {
+	easy := AnalyzeReadability("Bees make honey. They live in a hive. They work as a team.")
+	hard := AnalyzeReadability("Photosynthesis transforms electromagnetic radiation into chemical energy, sustaining terrestrial ecosystems through complicated biochemical pathways.")
+	if easy.FleschKincaidGrade >= hard.FleschKincaidGrade {
+		t.Fatalf("expected harder text to grade higher: easy %v, hard %v", easy.FleschKincaidGrade, hard.FleschKincaidGrade)
+	}
+	if hard.RareWordRatio < 0.5 {
+		t.Fatalf("expected most words to be rare, got %v", hard.RareWordRatio)
+	}
+}
+
+func TestCountSyllables(t *testing.T) {
+	cases := map[string]int{
+		"cat":       1,
+		"whale":     1,
+		"table":     2,
+		"jumped":    1,
+		"painted":   2,
+		"butterfly": 3,
+		"volcano":   3,
+	}
+	for word, want := range cases {
+		if got := countSyllables(word); got != want {
+			t.Fatalf("countSyllables(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
+
+func TestRareWordMatchesInflections(t *testing.T) {
+	for _, word := range []string{"jumping", "bigger", "happily", "stories", "hopped", "friends"} {
+		if isRareWord(word) {
+			t.Fatalf("expected %q to match a common base form", word)
+		}
+	}
+	if !isRareWord("chlorophyll") {
+		t.Fatalf("expected chlorophyll to be rare")
+	}
+}
+
+func TestSimplifyPromptAndPauseTags(t *testing.T) {
+	prompt := BuildSimplifyPrompt("advanced 7-year-olds", "topic", "Text here.", Readability{FleschKincaidGrade: 7.2, AvgSentenceLength: 18}, 4)
+	if !strings.Contains(prompt, "grade 7.2") || !strings.Contains(prompt, "grade 4.0") || !strings.HasSuffix(prompt, "Text here.") {
+		t.Fatalf("unexpected prompt: %q", prompt)
+	}
+	if !TagsPreserved("[happy] Ready? [short pause] Go! [long pause]", "[happy] Set? [short pause] Yes! [long pause]") {
+		t.Fatalf("expected tags to be preserved")
+	}
+	if TagsPreserved("Ready? [short pause]", "Ready?") {
+		t.Fatalf("expected dropped pause tag to be detected")
+	}
+	if TagsPreserved("[excited] Ready? [short pause]", "Ready? [short pause]") {
+		t.Fatalf("expected dropped emotion tag to be detected")
+	}
+	if TagsPreserved("Ready? [short pause]", "[happy] Ready? [short pause]") {
+		t.Fatalf("expected added emotion tag to be detected")
+	}
+	if TagsPreserved("[short pause] Go! [long pause]", "[long pause] Go! [short pause]") {
+		t.Fatalf("expected reordered pause tags to be detected")
+	}
+}
@@ -0,0 +1,89 @@
+package podcast
+
+import "regexp"
+
+// RecapEpisode is an earlier episode reviewed by the weekly recap.
+type RecapEpisode struct {
+	Date   string // YYYY-MM-DD
+	Topic  string
+	Title  string
+	Script string
+}
+
+// RecapTopic is the topic of the weekly recap episode.
+const RecapTopic = "This Week's Recap"
+
+// RecapQuizGame is the quiz played in the weekly recap. Its questions come
+// from the recap game section's prompt, which lists the week's episodes.
+var RecapQuizGame = GameRules{
+	Name:            "week-quiz",
+	DisplayName:     "Week Quiz",
+	MinItems:        5,
+	MaxItems:        5,
+	RequiredPhrases: []string{"The answer is"},
+	HasAnswers:      true,
+	ItemPattern:     regexp.MustCompile(`Question (one|two|three|four|five)\b`),
+	AnswerPattern:   regexp.MustCompile(`The answer is`),
+	Rules: "# Week Quiz\n\n" +
+		"Goal: Remember fun facts from this week's episodes.\n\n" +
+		"How it works:\n" +
+		"- The host asks 5 questions, one at a time, using explicit numbering.\n" +
+		"- Each question is about a fact taught in one of this week's episodes, and the host says which episode it comes from.\n" +
+		"- Cover as many different episodes as possible.\n" +
+		"- After each question, the host pauses for the listener to answer.\n" +
+		"- The host reveals the answer and adds one short reminder of why it is true.\n\n" +
+		"Guidelines:\n" +
+		"- Only ask about facts that were in the episodes; never invent new ones.\n" +
+		"- Questions should be answerable by a listener who heard the episode.\n" +
+		"- Keep each question to one short sentence.\n" +
+		"- Use this fixed item format:\n" +
+		"  - \"Question one, from our episode about ...: ...? [short pause]\"\n" +
+		"  - \"The answer is... <answer>! <short reminder>\"\n" +
+		"- Continue with question two through question five.",
+}
+
+// recapEpisodesTemplate lists the recap episodes and their scripts.
+const recapEpisodesTemplate = `{{range .Recap}}
+
+Episode from {{.Date}} about {{printf "%q" .Topic}}:
+{{.Script}}{{end}}`
+
+const recapIntroPrompt = `Write a warm, friendly podcast welcome for kids that sounds like welcoming a group of friends. ` +
+	`Greet listeners to the "{{.ShowName}}" and introduce the host, {{.HostName}}. ` +
+	`Mention today's date ({{.DateLabel}}) and say you hope everyone is having a wonderful {{.DayPhrase}}. ` +
+	`Explain that today is the weekly recap: instead of a new topic, you will look back at this week's episodes ` +
+	`({{range $i, $e := .Recap}}{{if $i}}, {{end}}{{$e.Topic}}{{end}}) and finish with a quiz. ` +
+	`Keep it 3-5 sentences, upbeat, and welcoming.` +
+	`{{with .Holiday}} Briefly recognize that today is {{.Name}}. ` +
+	`Add one short, kid-friendly sentence about what the holiday celebrates ({{.Description}}), ` +
+	`then include: "{{$.HolidayTodayLine}}"{{end}}`
+
+const recapHighlightsPrompt = `Revisit this week's episodes in order. For each one, name the topic and retell ` +
+	`one or two of its most surprising or memorable highlights in a fresh, lively way, ` +
+	`and connect the episodes where you can. Only use facts from the scripts below. ` +
+	`Keep it 4-6 short paragraphs total.` + recapEpisodesTemplate
+
+const recapHighlightsTransition = "Continue directly from the intro with no reset. Do not add another greeting or lead-in. Start with the first episode of the week."
+
+const recapGamePrompt = defaultGameSystemPrompt + "\n\n" +
+	`Base every question only on facts from this week's episodes:` + recapEpisodesTemplate
+
+const recapOutroPrompt = `Wrap up the weekly recap with a friendly thank-you for learning along all week ` +
+	`and ask listeners which episode was their favorite. ` +
+	`Use first-person voice as {{.HostName}}. ` +
+	`Instead of a mechanical date callout, weave it into a warm wish like: "{{.WarmWish}}" ` +
+	`Say you can't wait to explore new topics next week. Keep it 3-5 sentences.` +
+	`{{with .TomorrowHoliday}} Also mention that tomorrow is {{.Name}}. ` +
+	`Add one short, kid-friendly sentence about what the holiday celebrates ({{.Description}}), ` +
+	`then include: "{{$.HolidayTomorrowLine}}"{{end}}`
+
+// RecapShow returns the weekly recap structure: an intro, highlights from
+// the week's episodes, a quiz over their facts, and an outro.
+func RecapShow() ShowDefinition {
+	return ShowDefinition{Sections: []ShowSection{
+		{ID: "intro", Kind: SectionKindGenerated, Prompt: recapIntroPrompt, Transition: defaultTransitionPromptSuffix, WordBudget: DefaultIntroWordBudget},
+		{ID: "highlights", Kind: SectionKindGenerated, Prompt: recapHighlightsPrompt, Transition: recapHighlightsTransition, WordBudget: DefaultTopicWordBudget},
+		{ID: "game", Kind: SectionKindGame, Prompt: recapGamePrompt, Game: RecapQuizGame.Name, WordBudget: DefaultGameWordBudget},
+		{ID: "outro", Kind: SectionKindGenerated, Prompt: recapOutroPrompt, Transition: defaultTransitionPromptSuffix, WordBudget: DefaultOutroWordBudget},
+	}}
+}
@@ -0,0 +1,255 @@
+package podcast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"yodex/internal/ai"
+)
+
+// Severity ranks how serious a safety review finding is.
+type Severity string
+
+const (
+	SeverityNone   Severity = "none"
+	SeverityLow    Severity = "low"
+	SeverityMedium Severity = "medium"
+	SeverityHigh   Severity = "high"
+)
+
+var severityOrder = []Severity{SeverityNone, SeverityLow, SeverityMedium, SeverityHigh}
+
+// ParseSeverity parses a severity name (case-insensitive).
+func ParseSeverity(s string) (Severity, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, sev := range severityOrder {
+		if string(sev) == s {
+			return sev, nil
+		}
+	}
+	return "", fmt.Errorf("unknown severity: %q", s)
+}
+
+func (s Severity) rank() int {
+	for i, sev := range severityOrder {
+		if sev == s {
+			return i
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether s is as severe as or more severe than threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.rank() >= threshold.rank()
+}
+
+// Review categories rated for every section.
+const (
+	ReviewCategoryScariness          = "scariness"
+	ReviewCategoryAccuracy           = "accuracy"
+	ReviewCategoryUnsafeInstructions = "unsafe_instructions"
+	ReviewCategoryReadingLevel       = "reading_level"
+	ReviewCategoryModeration         = "moderation"
+)
+
+var reviewRubricCategories = []string{
+	ReviewCategoryScariness,
+	ReviewCategoryAccuracy,
+	ReviewCategoryUnsafeInstructions,
+	ReviewCategoryReadingLevel,
+}
+
+// ReviewFinding is a single rating from the safety review.
+type ReviewFinding struct {
+	SectionID string   `json:"section_id"`
+	Category  string   `json:"category"`
+	Severity  Severity `json:"severity"`
+	Note      string   `json:"note"`
+}
+
+// SafetyReview holds all findings for an episode.
+type SafetyReview struct {
+	Findings []ReviewFinding `json:"findings"`
+}
+
+// Blocking returns findings at or above the threshold.
+func (r SafetyReview) Blocking(threshold Severity) []ReviewFinding {
+	var out []ReviewFinding
+	for _, f := range r.Findings {
+		if f.Severity == SeverityNone {
+			continue
+		}
+		if f.Severity.AtLeast(threshold) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// BlockingSections returns the section IDs with findings at or above the threshold, in finding order.
+func (r SafetyReview) BlockingSections(threshold Severity) []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, f := range r.Blocking(threshold) {
+		if seen[f.SectionID] {
+			continue
+		}
+		seen[f.SectionID] = true
+		ids = append(ids, f.SectionID)
+	}
+	return ids
+}
+
+// RevisionNotes summarizes a section's blocking findings as instructions for regeneration.
+func (r SafetyReview) RevisionNotes(sectionID string, threshold Severity) string {
+	var b strings.Builder
+	for _, f := range r.Blocking(threshold) {
+		if f.SectionID != sectionID {
+			continue
+		}
+		fmt.Fprintf(&b, "- %s (%s): %s\n", f.Category, f.Severity, strings.TrimSpace(f.Note))
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "A safety reviewer flagged these problems; fix them while keeping the section's purpose:\n" + strings.TrimSpace(b.String())
+}
+
+// SafetyReviewSchema returns the JSON schema for structured review output.
+func SafetyReviewSchema() map[string]any {
+	severities := make([]string, 0, len(severityOrder))
+	for _, sev := range severityOrder {
+		severities = append(severities, string(sev))
+	}
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"findings": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":                 "object",
+					"additionalProperties": false,
+					"properties": map[string]any{
+						"section_id": map[string]any{
+							"type":        "string",
+							"description": "Section identifier being rated",
+						},
+						"category": map[string]any{
+							"type": "string",
+							"enum": reviewRubricCategories,
+						},
+						"severity": map[string]any{
+							"type": "string",
+							"enum": severities,
+						},
+						"note": map[string]any{
+							"type":        "string",
+							"description": "Short explanation quoting the problem text, or empty when severity is none",
+						},
+					},
+					"required": []string{"section_id", "category", "severity", "note"},
+				},
+			},
+		},
+		"required": []string{"findings"},
+	}
+}
+
+const safetyReviewSystemPrompt = "You are a careful reviewer for a kids' science podcast for %s. " +
+	"You rate scripts against a rubric and report problems precisely. " +
+	"Be strict about safety and accuracy, but do not flag harmless words used in a harmless context."
+
+// BuildSafetyReviewPrompt builds the system and user prompt for a rubric-driven
+// review. audience describes the listeners, e.g. "advanced 7-year-olds".
+func BuildSafetyReviewPrompt(audience, topic string, episode Episode) (string, string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Review this podcast episode about %q. Rate every section once for each category:\n", strings.TrimSpace(topic))
+	b.WriteString("- scariness: content that could frighten or upset a young child (graphic injury, death described vividly, menacing tone).\n")
+	b.WriteString("- accuracy: statements that are false, misleading, or presented with more certainty than science supports.\n")
+	b.WriteString("- unsafe_instructions: anything a child might try that could hurt them (heat, chemicals, heights, animals, electricity) without adult supervision.\n")
+	fmt.Fprintf(&b, "- reading_level: vocabulary or sentence structure well above what %s can follow by ear.\n", audience)
+	b.WriteString("Use severity none, low, medium, or high. Use none when there is no problem and leave the note empty. ")
+	b.WriteString("Ignore bracketed audio tags such as [short pause] or [excited].\n")
+	for _, section := range episode.Sections {
+		fmt.Fprintf(&b, "\nSection ID: %s\n%s\n", section.SectionID, strings.TrimSpace(section.Text))
+	}
+	return fmt.Sprintf(safetyReviewSystemPrompt, audience), strings.TrimSpace(b.String())
+}
+
+// JSONGenerator is the small interface required for structured reviews.
+type JSONGenerator interface {
+	GenerateJSONWithUsage(ctx context.Context, model, system, prompt, schemaName string, schema map[string]any) (string, ai.TokenUsage, error)
+}
+
+// ReviewEpisode runs the rubric-driven model review and returns its findings.
+func ReviewEpisode(ctx context.Context, gen JSONGenerator, model, audience, topic string, episode Episode) (SafetyReview, ai.TokenUsage, error) {
+	if gen == nil {
+		return SafetyReview{}, ai.TokenUsage{}, errors.New("ai client is required for safety review")
+	}
+	system, user := BuildSafetyReviewPrompt(audience, topic, episode)
+	raw, usage, err := gen.GenerateJSONWithUsage(ctx, model, system, user, "safety_review", SafetyReviewSchema())
+	if err != nil {
+		return SafetyReview{}, ai.TokenUsage{}, err
+	}
+	var review SafetyReview
+	if err := json.Unmarshal([]byte(raw), &review); err != nil {
+		return SafetyReview{}, usage, fmt.Errorf("parse safety review: %w", err)
+	}
+	known := make(map[string]bool, len(episode.Sections))
+	for _, section := range episode.Sections {
+		known[section.SectionID] = true
+	}
+	filtered := review.Findings[:0]
+	for _, f := range review.Findings {
+		if !known[f.SectionID] {
+			continue
+		}
+		sev, err := ParseSeverity(string(f.Severity))
+		if err != nil {
+			return SafetyReview{}, usage, err
+		}
+		f.Severity = sev
+		filtered = append(filtered, f)
+	}
+	review.Findings = filtered
+	return review, usage, nil
+}
+
+// Moderator classifies text with a moderation endpoint.
+type Moderator interface {
+	Moderate(ctx context.Context, inputs []string) ([]ai.ModerationResult, error)
+}
+
+// ModerateEpisode runs each section through the moderation endpoint.
+// Flagged sections are reported as high-severity findings.
+func ModerateEpisode(ctx context.Context, mod Moderator, episode Episode) ([]ReviewFinding, error) {
+	inputs := make([]string, 0, len(episode.Sections))
+	for _, section := range episode.Sections {
+		inputs = append(inputs, section.Text)
+	}
+	results, err := mod.Moderate(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != len(episode.Sections) {
+		return nil, fmt.Errorf("moderation returned %d results for %d sections", len(results), len(episode.Sections))
+	}
+	var findings []ReviewFinding
+	for i, res := range results {
+		if !res.Flagged {
+			continue
+		}
+		findings = append(findings, ReviewFinding{
+			SectionID: episode.Sections[i].SectionID,
+			Category:  ReviewCategoryModeration,
+			Severity:  SeverityHigh,
+			Note:      "flagged by moderation: " + strings.Join(res.Categories, ", "),
+		})
+	}
+	return findings, nil
+}
@@ -0,0 +1,105 @@
+package podcast
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"yodex/internal/ai"
+)
+
+type fakeJSONGen struct {
+	raw    string
+	prompt string
+}
+
+func (f *fakeJSONGen) GenerateJSONWithUsage(ctx context.Context, model, system, prompt, schemaName string, schema map[string]any) (string, ai.TokenUsage, error) {
+	f.prompt = prompt
+	return f.raw, ai.TokenUsage{TotalTokens: 7}, nil
+}
+
+type fakeModerator struct {
+	results []ai.ModerationResult
+}
+
+func (f *fakeModerator) Moderate(ctx context.Context, inputs []string) ([]ai.ModerationResult, error) {
+	return f.results, nil
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	sev, err := ParseSeverity(" Medium ")
+	if err != nil {
+		t.Fatalf("ParseSeverity: %v", err)
+	}
+	if !sev.AtLeast(SeverityLow) || !sev.AtLeast(SeverityMedium) || sev.AtLeast(SeverityHigh) {
+		t.Fatalf("unexpected severity ordering for %q", sev)
+	}
+	if _, err := ParseSeverity("extreme"); err == nil {
+		t.Fatalf("expected error for unknown severity")
+	}
+}
+
+func TestSafetyReviewBlockingSections(t *testing.T) {
+	review := SafetyReview{Findings: []ReviewFinding{
+		{SectionID: "intro", Category: ReviewCategoryScariness, Severity: SeverityNone},
+		{SectionID: "topic", Category: ReviewCategoryAccuracy, Severity: SeverityLow, Note: "minor rounding"},
+		{SectionID: "game", Category: ReviewCategoryUnsafeInstructions, Severity: SeverityHigh, Note: "suggests tasting berries"},
+		{SectionID: "game", Category: ReviewCategoryScariness, Severity: SeverityMedium, Note: "spooky"},
+	}}
+	got := review.BlockingSections(SeverityMedium)
+	if len(got) != 1 || got[0] != "game" {
+		t.Fatalf("unexpected blocking sections: %v", got)
+	}
+	if got := review.BlockingSections(SeverityLow); len(got) != 2 {
+		t.Fatalf("expected topic and game at low threshold, got %v", got)
+	}
+	notes := review.RevisionNotes("game", SeverityMedium)
+	if !strings.Contains(notes, "suggests tasting berries") || !strings.Contains(notes, "spooky") {
+		t.Fatalf("expected both findings in notes, got %q", notes)
+	}
+	if review.RevisionNotes("intro", SeverityMedium) != "" {
+		t.Fatalf("expected no notes for clean section")
+	}
+}
+
+func TestReviewEpisodeParsesFindings(t *testing.T) {
+	episode := Episode{Title: "Bees", Sections: []EpisodeSection{
+		{SectionID: "intro", Text: "Hello friends."},
+		{SectionID: "topic", Text: "Bees dance."},
+	}}
+	gen := &fakeJSONGen{raw: `{"findings":[
+		{"section_id":"topic","category":"accuracy","severity":"HIGH","note":"bees do not sing"},
+		{"section_id":"unknown","category":"scariness","severity":"high","note":"ignored"}
+	]}`}
+	review, usage, err := ReviewEpisode(context.Background(), gen, "m", "advanced 7-year-olds", "Bees", episode)
+	if err != nil {
+		t.Fatalf("ReviewEpisode: %v", err)
+	}
+	if usage.TotalTokens != 7 {
+		t.Fatalf("usage not returned: %+v", usage)
+	}
+	if len(review.Findings) != 1 || review.Findings[0].Severity != SeverityHigh {
+		t.Fatalf("unexpected findings: %+v", review.Findings)
+	}
+	if !strings.Contains(gen.prompt, "Section ID: topic\nBees dance.") {
+		t.Fatalf("expected section text in prompt, got %q", gen.prompt)
+	}
+}
+
+func TestModerateEpisodeReportsFlaggedSections(t *testing.T) {
+	episode := Episode{Title: "Bees", Sections: []EpisodeSection{
+		{SectionID: "intro", Text: "Hello."},
+		{SectionID: "topic", Text: "Bad."},
+	}}
+	mod := &fakeModerator{results: []ai.ModerationResult{
+		{Flagged: false},
+		{Flagged: true, Categories: []string{"violence"}},
+	}}
+	findings, err := ModerateEpisode(context.Background(), mod, episode)
+	if err != nil {
+		t.Fatalf("ModerateEpisode: %v", err)
+	}
+	if len(findings) != 1 || findings[0].SectionID != "topic" || findings[0].Severity != SeverityHigh {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+}
@@ -0,0 +1,338 @@
+package podcast
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// SafetyTerm is a single entry in the lexical safety list.
+//
+// Terms match on word boundaries, so "gun" does not match "begun". When Stem is
+// set, common inflections also match ("poison" matches "poisonous" and
+// "poisoned"). AllowTopics lists topic keywords for which the term is expected
+// (for example "frog" for "poison"), and AllowPhrases lists phrases in which a
+// match is harmless (for example "poison dart frog").
+type SafetyTerm struct {
+	Term         string   `json:"term"`
+	Severity     Severity `json:"severity"`
+	Stem         bool     `json:"stem,omitempty"`
+	AllowTopics  []string `json:"allowTopics,omitempty"`
+	AllowPhrases []string `json:"allowPhrases,omitempty"`
+}
+
+// SafetyTermsFile is the on-disk format for custom term lists. Terms are merged
+// over the defaults by term name unless Replace is set.
+type SafetyTermsFile struct {
+	Replace bool         `json:"replace,omitempty"`
+	Terms   []SafetyTerm `json:"terms"`
+}
+
+var defaultSafetyTerms = []SafetyTerm{
+	{Term: "suicide", Severity: SeverityHigh, Stem: true},
+	{Term: "self-harm", Severity: SeverityHigh},
+	{Term: "bomb", Severity: SeverityHigh, Stem: true},
+	{Term: "explosive", Severity: SeverityMedium, Stem: true, AllowTopics: []string{"volcano", "firework", "supernova", "rocket"}},
+	{Term: "gun", Severity: SeverityMedium, Stem: true},
+	{Term: "weapon", Severity: SeverityMedium, Stem: true},
+	{Term: "poison", Severity: SeverityMedium, Stem: true,
+		AllowTopics:  []string{"frog", "amphibian", "snake", "spider", "scorpion", "jellyfish", "mushroom", "fungi", "plant", "venom"},
+		AllowPhrases: []string{"poison dart frog", "poison ivy", "poison oak"}},
+	{Term: "cocaine", Severity: SeverityHigh},
+	{Term: "heroin", Severity: SeverityHigh},
+	{Term: "sexual", Severity: SeverityHigh},
+}
+
+// localizedSafetyTerms are added to the English list for episodes in other
+// languages, keyed by primary language subtag. Inflections are listed rather
+// than stemmed because stemming follows English suffixes.
+var localizedSafetyTerms = map[string][]SafetyTerm{
+	"es": {
+		{Term: "suicidio", Severity: SeverityHigh},
+		{Term: "suicidarse", Severity: SeverityHigh},
+		{Term: "autolesión", Severity: SeverityHigh},
+		{Term: "bomba", Severity: SeverityHigh},
+		{Term: "bombas", Severity: SeverityHigh},
+		{Term: "explosivo", Severity: SeverityMedium, AllowTopics: []string{"volcán", "volcan", "fuegos artificiales", "supernova", "cohete"}},
+		{Term: "explosivos", Severity: SeverityMedium, AllowTopics: []string{"volcán", "volcan", "fuegos artificiales", "supernova", "cohete"}},
+		{Term: "pistola", Severity: SeverityMedium},
+		{Term: "pistolas", Severity: SeverityMedium},
+		{Term: "arma", Severity: SeverityMedium},
+		{Term: "armas", Severity: SeverityMedium},
+		{Term: "veneno", Severity: SeverityMedium, AllowTopics: spanishVenomTopics},
+		{Term: "venenoso", Severity: SeverityMedium, AllowTopics: spanishVenomTopics},
+		{Term: "venenosa", Severity: SeverityMedium, AllowTopics: spanishVenomTopics, AllowPhrases: []string{"rana dardo venenosa"}},
+		{Term: "cocaína", Severity: SeverityHigh},
+		{Term: "sexual", Severity: SeverityHigh},
+	},
+}
+
+var spanishVenomTopics = []string{"rana", "anfibio", "serpiente", "araña", "escorpión", "alacrán", "medusa", "hongo", "seta", "planta"}
+
+// DefaultSafetyTerms returns a copy of the built-in term list.
+func DefaultSafetyTerms() []SafetyTerm {
+	terms := make([]SafetyTerm, len(defaultSafetyTerms))
+	copy(terms, defaultSafetyTerms)
+	return terms
+}
+
+// DefaultSafetyTermsFor returns the built-in English terms plus the built-in
+// terms for each listed language (BCP-47 tags such as "es" or "es-MX").
+func DefaultSafetyTermsFor(languages ...string) []SafetyTerm {
+	terms := DefaultSafetyTerms()
+	seen := map[string]bool{}
+	for _, lang := range languages {
+		base := strings.ToLower(strings.SplitN(strings.TrimSpace(lang), "-", 2)[0])
+		if seen[base] {
+			continue
+		}
+		seen[base] = true
+		terms = mergeSafetyTerms(terms, localizedSafetyTerms[base])
+	}
+	return terms
+}
+
+// LoadSafetyTerms reads a term list file and merges it over the built-in terms
+// for the given languages. An empty path returns the built-in terms.
+func LoadSafetyTerms(path string, languages ...string) ([]SafetyTerm, error) {
+	if strings.TrimSpace(path) == "" {
+		return DefaultSafetyTermsFor(languages...), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read safety terms: %w", err)
+	}
+	var file SafetyTermsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse safety terms %s: %w", path, err)
+	}
+	if file.Replace {
+		return file.Terms, nil
+	}
+	return mergeSafetyTerms(DefaultSafetyTermsFor(languages...), file.Terms), nil
+}
+
+func mergeSafetyTerms(base, overrides []SafetyTerm) []SafetyTerm {
+	merged := make([]SafetyTerm, 0, len(base)+len(overrides))
+	index := make(map[string]int, len(base))
+	for _, term := range base {
+		index[strings.ToLower(term.Term)] = len(merged)
+		merged = append(merged, term)
+	}
+	for _, term := range overrides {
+		key := strings.ToLower(term.Term)
+		if i, ok := index[key]; ok {
+			merged[i] = term
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, term)
+	}
+	return merged
+}
+
+// SafetyHit is a single lexical match.
+type SafetyHit struct {
+	SectionID string   `json:"section_id,omitempty"`
+	Term      string   `json:"term"`
+	Match     string   `json:"match"`
+	Severity  Severity `json:"severity"`
+	Offset    int      `json:"offset"`
+}
+
+func (h SafetyHit) String() string {
+	if h.SectionID == "" {
+		return fmt.Sprintf("%s (%s at offset %d, %s)", h.Term, h.Match, h.Offset, h.Severity)
+	}
+	return fmt.Sprintf("%s (%s in %s at offset %d, %s)", h.Term, h.Match, h.SectionID, h.Offset, h.Severity)
+}
+
+// SafetyHitsError reports every blocking hit from a lexical check.
+type SafetyHitsError struct {
+	Hits []SafetyHit
+}
+
+func (e *SafetyHitsError) Error() string {
+	parts := make([]string, 0, len(e.Hits))
+	for _, hit := range e.Hits {
+		parts = append(parts, hit.String())
+	}
+	return "unsafe terms detected: " + strings.Join(parts, "; ")
+}
+
+type compiledSafetyTerm struct {
+	SafetyTerm
+	pattern *regexp.Regexp
+	phrases []*regexp.Regexp
+}
+
+// SafetyChecker scans text for configured safety terms.
+type SafetyChecker struct {
+	terms []compiledSafetyTerm
+}
+
+// NewSafetyChecker compiles a term list into a checker.
+func NewSafetyChecker(terms []SafetyTerm) (*SafetyChecker, error) {
+	checker := &SafetyChecker{}
+	for _, term := range terms {
+		word := strings.TrimSpace(term.Term)
+		if word == "" {
+			return nil, errors.New("safety term is required")
+		}
+		sev := term.Severity
+		if sev == "" {
+			sev = SeverityMedium
+		}
+		parsed, err := ParseSeverity(string(sev))
+		if err != nil {
+			return nil, fmt.Errorf("safety term %q: %w", word, err)
+		}
+		term.Term = word
+		term.Severity = parsed
+		compiled := compiledSafetyTerm{
+			SafetyTerm: term,
+			pattern:    regexp.MustCompile(termPattern(word, term.Stem)),
+		}
+		for _, phrase := range term.AllowPhrases {
+			if strings.TrimSpace(phrase) == "" {
+				continue
+			}
+			compiled.phrases = append(compiled.phrases, regexp.MustCompile(termPattern(phrase, false)))
+		}
+		checker.terms = append(checker.terms, compiled)
+	}
+	return checker, nil
+}
+
+// termPattern builds a case-insensitive, word-bounded pattern whose first
+// group is the term. Boundaries are any character that is not a Unicode letter
+// or digit, so accented letters count as part of a word. Stemmed terms also
+// accept common English suffixes, including a doubled final consonant
+// ("gunned", "bombing") and a final y turned into i ("scarier").
+func termPattern(term string, stem bool) string {
+	term = strings.ToLower(term)
+	pattern := regexp.QuoteMeta(term)
+	if stem {
+		const suffixes = `(?:s|es|ed|d|ing|er|ers|ous)`
+		_, size := utf8.DecodeLastRuneInString(term)
+		last := term[len(term)-size:]
+		switch {
+		case last == "y":
+			pattern = regexp.QuoteMeta(term[:len(term)-1]) + `(?:y|ys|ying|yed|ies|ied|ier|iest)`
+		case strings.ContainsAny(last, "bcdfgklmnprstvz"):
+			pattern = regexp.QuoteMeta(term) + `(?:` + regexp.QuoteMeta(last) + `?` + suffixes + `)?`
+		default:
+			pattern = regexp.QuoteMeta(term) + suffixes + `?`
+		}
+	}
+	return `(?i)(?:^|[^\p{L}\p{N}])(` + pattern + `)(?:[^\p{L}\p{N}]|$)`
+}
+
+// findTerm returns the span of every match of a termPattern in text. The
+// boundary after one match may be the boundary before the next, so each
+// search resumes at the end of the previous term rather than of the match.
+func findTerm(pattern *regexp.Regexp, text string) [][]int {
+	var spans [][]int
+	for pos := 0; pos < len(text); {
+		m := pattern.FindStringSubmatchIndex(text[pos:])
+		if m == nil {
+			break
+		}
+		spans = append(spans, []int{pos + m[2], pos + m[3]})
+		pos += m[3]
+	}
+	return spans
+}
+
+// CheckText returns every hit in text. Terms allowed for the topic are skipped,
+// as are matches inside an allowed phrase.
+func (c *SafetyChecker) CheckText(topic, sectionID, text string) []SafetyHit {
+	lowerTopic := strings.ToLower(topic)
+	var hits []SafetyHit
+	for _, term := range c.terms {
+		if topicAllows(lowerTopic, term.AllowTopics) {
+			continue
+		}
+		var allowed [][]int
+		for _, phrase := range term.phrases {
+			allowed = append(allowed, findTerm(phrase, text)...)
+		}
+		for _, loc := range findTerm(term.pattern, text) {
+			if withinAny(loc, allowed) {
+				continue
+			}
+			hits = append(hits, SafetyHit{
+				SectionID: sectionID,
+				Term:      term.Term,
+				Match:     text[loc[0]:loc[1]],
+				Severity:  term.Severity,
+				Offset:    loc[0],
+			})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Offset < hits[j].Offset
+	})
+	return hits
+}
+
+// CheckEpisode returns every hit across all sections, in section order.
+func (c *SafetyChecker) CheckEpisode(topic string, episode Episode) []SafetyHit {
+	var hits []SafetyHit
+	for _, section := range episode.Sections {
+		hits = append(hits, c.CheckText(topic, section.SectionID, section.Text)...)
+	}
+	return hits
+}
+
+// BlockingHits returns hits at or above the threshold.
+func BlockingHits(hits []SafetyHit, threshold Severity) []SafetyHit {
+	var out []SafetyHit
+	for _, hit := range hits {
+		if hit.Severity.AtLeast(threshold) {
+			out = append(out, hit)
+		}
+	}
+	return out
+}
+
+func topicAllows(lowerTopic string, keywords []string) bool {
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" && strings.Contains(lowerTopic, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func withinAny(loc []int, spans [][]int) bool {
+	for _, span := range spans {
+		if loc[0] >= span[0] && loc[1] <= span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+var defaultSafetyChecker = func() *SafetyChecker {
+	checker, err := NewSafetyChecker(defaultSafetyTerms)
+	if err != nil {
+		panic(err)
+	}
+	return checker
+}()
+
+// BasicSafetyCheck scans text with the default term list and reports every hit.
+func BasicSafetyCheck(text string) error {
+	hits := defaultSafetyChecker.CheckText("", "", text)
+	if len(hits) > 0 {
+		return &SafetyHitsError{Hits: hits}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package podcast
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSafetyCheckerWordBoundaries(t *testing.T) {
+	checker, err := NewSafetyChecker(DefaultSafetyTerms())
+	if err != nil {
+		t.Fatalf("NewSafetyChecker: %v", err)
+	}
+	if hits := checker.CheckText("", "intro", "The adventure has begun!"); len(hits) != 0 {
+		t.Fatalf("expected no hits for begun, got %+v", hits)
+	}
+	if hits := checker.CheckText("", "topic", "Gunpowder was invented in ancient China."); len(hits) != 0 {
+		t.Fatalf("expected no hits for gunpowder, got %+v", hits)
+	}
+	hits := checker.CheckText("", "topic", "Some berries are poisonous. A toy gun goes pop.")
+	if len(hits) != 2 {
+		t.Fatalf("expected 2 hits, got %+v", hits)
+	}
+	if hits[0].Term != "poison" || hits[0].Match != "poisonous" || hits[0].Offset != 17 || hits[0].SectionID != "topic" {
+		t.Fatalf("unexpected first hit: %+v", hits[0])
+	}
+	if hits[1].Term != "gun" || hits[1].Severity != SeverityMedium {
+		t.Fatalf("unexpected second hit: %+v", hits[1])
+	}
+}
+
+func TestSafetyCheckerAllowlists(t *testing.T) {
+	checker, err := NewSafetyChecker(DefaultSafetyTerms())
+	if err != nil {
+		t.Fatalf("NewSafetyChecker: %v", err)
+	}
+	if hits := checker.CheckText("Amazing Amphibians", "topic", "Some frogs are poisonous."); len(hits) != 0 {
+		t.Fatalf("expected topic allowlist to skip poison, got %+v", hits)
+	}
+	if hits := checker.CheckText("Rainforest Life", "topic", "The poison dart frog is tiny."); len(hits) != 0 {
+		t.Fatalf("expected phrase allowlist to skip poison dart frog, got %+v", hits)
+	}
+	if hits := checker.CheckText("Rainforest Life", "topic", "Never drink poison."); len(hits) != 1 {
+		t.Fatalf("expected poison outside allowed phrase to hit, got %+v", hits)
+	}
+}
+
+func TestCheckEpisodeReturnsAllHits(t *testing.T) {
+	checker, err := NewSafetyChecker(DefaultSafetyTerms())
+	if err != nil {
+		t.Fatalf("NewSafetyChecker: %v", err)
+	}
+	ep := Episode{Title: "x", Sections: []EpisodeSection{
+		{SectionID: "intro", Text: "A bomb and a weapon."},
+		{SectionID: "outro", Text: "Bombs away."},
+	}}
+	hits := checker.CheckEpisode("", ep)
+	if len(hits) != 3 {
+		t.Fatalf("expected 3 hits, got %+v", hits)
+	}
+	if hits[2].SectionID != "outro" || hits[2].Match != "Bombs" {
+		t.Fatalf("unexpected outro hit: %+v", hits[2])
+	}
+	if blocking := BlockingHits(hits, SeverityHigh); len(blocking) != 2 {
+		t.Fatalf("expected 2 high-severity hits, got %+v", blocking)
+	}
+}
+
+func TestLoadSafetyTermsMergesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "terms.json")
+	data := `{"terms":[{"term":"gun","severity":"low"},{"term":"spooky","severity":"high","stem":true}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write terms: %v", err)
+	}
+	terms, err := LoadSafetyTerms(path)
+	if err != nil {
+		t.Fatalf("LoadSafetyTerms: %v", err)
+	}
+	if len(terms) != len(DefaultSafetyTerms())+1 {
+		t.Fatalf("expected defaults plus one new term, got %d", len(terms))
+	}
+	checker, err := NewSafetyChecker(terms)
+	if err != nil {
+		t.Fatalf("NewSafetyChecker: %v", err)
+	}
+	hits := checker.CheckText("", "", "A water gun is not spookier than a ghost.")
+	if len(hits) != 2 || hits[0].Severity != SeverityLow || hits[1].Term != "spooky" {
+		t.Fatalf("unexpected hits: %+v", hits)
+	}
+}
+
+func TestBasicSafetyCheckReportsEveryHit(t *testing.T) {
+	err := BasicSafetyCheck("A bomb. Another bomb.")
+	var hitsErr *SafetyHitsError
+	if !errors.As(err, &hitsErr) {
+		t.Fatalf("expected SafetyHitsError, got %v", err)
+	}
+	if len(hitsErr.Hits) != 2 {
+		t.Fatalf("expected 2 hits, got %+v", hitsErr.Hits)
+	}
+}
+
+func TestDefaultSafetyTermsForSpanish(t *testing.T) {
+	checker, err := NewSafetyChecker(DefaultSafetyTermsFor("es-MX"))
+	if err != nil {
+		t.Fatalf("NewSafetyChecker: %v", err)
+	}
+	if hits := checker.CheckText("Volcanes", "topic", "Nunca toques una bomba."); len(hits) != 1 || hits[0].Term != "bomba" {
+		t.Fatalf("expected bomba to hit, got %+v", hits)
+	}
+	if hits := checker.CheckText("Volcanes", "topic", "Los bomberos ayudan a todos."); len(hits) != 0 {
+		t.Fatalf("expected word boundaries to skip bomberos, got %+v", hits)
+	}
+	if hits := checker.CheckText("La selva", "topic", "La rana dardo venenosa es pequeña."); len(hits) != 0 {
+		t.Fatalf("expected phrase allowlist to skip rana dardo venenosa, got %+v", hits)
+	}
+	if hits := checker.CheckText("La selva", "topic", "Never drink poison."); len(hits) != 1 {
+		t.Fatalf("expected English terms to stay active, got %+v", hits)
+	}
+	if len(DefaultSafetyTermsFor("en")) != len(DefaultSafetyTerms()) {
+		t.Fatalf("expected English to add no terms")
+	}
+}
+
+func TestSafetyCheckerAccentedTerms(t *testing.T) {
+	checker, err := NewSafetyChecker([]SafetyTerm{
+		{Term: "éxtasis", Severity: SeverityHigh},
+		{Term: "autolesión", Severity: SeverityHigh},
+		{Term: "arma", Severity: SeverityMedium},
+	})
+	if err != nil {
+		t.Fatalf("NewSafetyChecker: %v", err)
+	}
+	hits := checker.CheckText("Salud", "topic", "Éxtasis, autolesión y autolesión: nunca.")
+	if len(hits) != 3 || hits[0].Match != "Éxtasis" || hits[1].Match != "autolesión" || hits[2].Offset != 24 {
+		t.Fatalf("expected accented terms to match as whole words, got %+v", hits)
+	}
+	for _, text := range []string{"Una autolesiónes rara.", "El armaño.", "La ñarma."} {
+		if hits := checker.CheckText("Salud", "topic", text); len(hits) != 0 {
+			t.Fatalf("expected no match inside a word in %q, got %+v", text, hits)
+		}
+	}
+}
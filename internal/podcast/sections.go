@@ -10,100 +10,53 @@ const defaultTransitionPromptSuffix = "Continue as if you are finishing the prev
 
 const topicTransitionPromptSuffix = "Continue directly from the intro with no reset. Do not add another greeting, teaser, or second lead-in. Start teaching the topic in the first sentence."
 
-var standardSectionIDs = []string{
-	"intro",
-	"topic",
-	"game",
-	"outro",
-}
-
-// StandardSectionIDs returns the ordered list of section IDs.
+// StandardSectionIDs returns the ordered list of section IDs in the default show.
 func StandardSectionIDs() []string {
-	ids := make([]string, 0, len(standardSectionIDs))
-	ids = append(ids, standardSectionIDs...)
-	return ids
+	return DefaultShow().SectionIDs()
 }
 
-// SectionSpec defines the schema for a generated episode section.
+// SectionSpec defines the schema for an episode section. Kind defaults to
+// generated; Text holds the rendered text of static sections. Language is the
+// BCP-47 tag the section is spoken in, and Game the game a game section
+// plays when it is not the weekday's game. EnforceWordBudget marks a budget
+// set in the show definition, which holds even without a target word count.
 type SectionSpec struct {
-	SectionID              string `json:"section_id"`
-	Prompt                 string `json:"prompt"`
-	ContinuityContext      string `json:"continuity_context"`
-	TransitionInstructions string `json:"transition_instructions"`
+	SectionID              string      `json:"section_id"`
+	Kind                   SectionKind `json:"kind,omitempty"`
+	Text                   string      `json:"text,omitempty"`
+	Prompt                 string      `json:"prompt"`
+	ContinuityContext      string      `json:"continuity_context"`
+	TransitionInstructions string      `json:"transition_instructions"`
+	RevisionInstructions   string      `json:"revision_instructions,omitempty"`
+	WordBudget             int         `json:"word_budget,omitempty"`
+	Language               string      `json:"language,omitempty"`
+	Game                   string      `json:"game,omitempty"`
+	EnforceWordBudget      bool        `json:"-"`
 }
 
 // EpisodeSection holds generated section text.
+// KeyFacts, Vocabulary, and Questions are only populated by structured generation.
 type EpisodeSection struct {
-	SectionID string `json:"section_id"`
-	Text      string `json:"text"`
+	SectionID  string   `json:"section_id"`
+	Text       string   `json:"text"`
+	KeyFacts   []string `json:"key_facts,omitempty"`
+	Vocabulary []string `json:"vocabulary,omitempty"`
+	Questions  []string `json:"questions,omitempty"`
 }
 
-// StandardSectionSchema returns the ordered section specs for an episode.
-func StandardSectionSchema(topic string, date time.Time) []SectionSpec {
-	return []SectionSpec{
-		{
-			SectionID:              "intro",
-			Prompt:                 buildIntroPrompt(topic, date),
-			TransitionInstructions: defaultTransitionPromptSuffix,
-		},
-		{
-			SectionID:              "topic",
-			Prompt:                 buildTopicPrompt(topic),
-			TransitionInstructions: topicTransitionPromptSuffix,
-		},
-		{
-			SectionID:              "outro",
-			Prompt:                 buildOutroPrompt(topic, date),
-			TransitionInstructions: defaultTransitionPromptSuffix,
-		},
+// StandardSectionSchema returns the generated section specs of the default show.
+func StandardSectionSchema(topic string, date time.Time) ([]SectionSpec, error) {
+	specs, err := DefaultShow().Specs(DefaultPrompts(), topic, date)
+	if err != nil {
+		return nil, err
 	}
-}
-
-func buildTopicPrompt(topic string) string {
-	return fmt.Sprintf("Explain the core idea about %q in a clear, curious voice, then add a deeper dive. Use relatable analogies and include one surprising fact. Keep it 4-6 short paragraphs total.", topic)
-}
-
-func buildIntroPrompt(topic string, date time.Time) string {
-	date = date.UTC()
-	dateLabel := date.Format("Monday, January 2, 2006")
-	dayPhrase := "day"
-	switch date.Weekday() {
-	case time.Friday:
-		dayPhrase = "Fri-YAY!"
-	case time.Saturday, time.Sunday:
-		dayPhrase = "weekend"
-	}
-	prompt := fmt.Sprintf(
-		"Write a warm, friendly podcast welcome for kids that sounds like welcoming a group of friends. Greet listeners to the \"Curious World Podcast\" and introduce the host, Jessica. Mention today's date (%s) and say you hope everyone is having a wonderful %s. Keep it 3-5 sentences, upbeat, and welcoming. End with exactly one short sentence that introduces %q. Do not add a second teaser or additional lead-in sentence after that.",
-		dateLabel,
-		dayPhrase,
-		topic,
-	)
-	if holiday, ok := holidayOnDate(date); ok {
-		prompt += fmt.Sprintf(" Before introducing %q, briefly recognize that today is %s. Add one short, kid-friendly sentence about what the holiday celebrates (%s), then include: \"If you're celebrating, I hope you have a wonderful holiday today.\"",
-			topic,
-			holiday.Name,
-			holiday.Description,
-		)
-	}
-	return prompt
-}
-
-func buildOutroPrompt(topic string, date time.Time) string {
-	date = date.UTC()
-	dateLabel := date.Format("Monday, January 2")
-	prompt := fmt.Sprintf(
-		"Wrap up the episode about %q with a friendly recap and a thoughtful question for listeners. Use first-person voice as Jessica. Instead of a mechanical date callout, weave it into a warm wish like: \"I hope everyone has an amazing %s.\" Keep it 3-5 sentences.",
-		topic,
-		dateLabel,
-	)
-	if holiday, ok := holidayOnDate(date.AddDate(0, 0, 1)); ok {
-		prompt += fmt.Sprintf(" Also mention that tomorrow is %s. Add one short, kid-friendly sentence about what the holiday celebrates (%s), then include: \"If you're celebrating, I hope you have a wonderful holiday tomorrow.\"",
-			holiday.Name,
-			holiday.Description,
-		)
-	}
-	return prompt
+	generated := make([]SectionSpec, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Kind == SectionKindGenerated {
+			generated = append(generated, spec)
+		}
+	}
+	return generated, nil
 }
 
 // BuildSectionPrompt builds the user prompt for a single section.
@@ -117,6 +70,9 @@ func BuildSectionPrompt(basePrompt string, spec SectionSpec) string {
 	b.WriteString("Section prompt: ")
 	b.WriteString(strings.TrimSpace(spec.Prompt))
 	b.WriteString("\n")
+	if spec.WordBudget > 0 {
+		fmt.Fprintf(&b, "Target length: about %d spoken words.\n", spec.WordBudget)
+	}
 	if strings.TrimSpace(spec.ContinuityContext) != "" {
 		b.WriteString("Continuity anchor:\n")
 		b.WriteString(strings.TrimSpace(spec.ContinuityContext))
@@ -125,6 +81,50 @@ func BuildSectionPrompt(basePrompt string, spec SectionSpec) string {
 	if strings.TrimSpace(spec.TransitionInstructions) != "" {
 		b.WriteString("Transition instructions: ")
 		b.WriteString(strings.TrimSpace(spec.TransitionInstructions))
+		b.WriteString("\n")
+	}
+	if strings.TrimSpace(spec.RevisionInstructions) != "" {
+		b.WriteString("Revision instructions:\n")
+		b.WriteString(strings.TrimSpace(spec.RevisionInstructions))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// BuildStructuredEpisodePrompt builds a single user prompt that asks for every
+// generated and game section in one structured response, in show order.
+// Static sections are skipped; their text is inserted after parsing.
+func BuildStructuredEpisodePrompt(basePrompt string, specs []SectionSpec, gamePrompt string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(basePrompt))
+	if b.Len() > 0 {
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Write the whole episode in one response. Each section continues directly from the one before it, so the episode reads as one continuous script. ")
+	b.WriteString("For each section, also list the key facts it teaches, any new vocabulary words it introduces, and the questions it asks the listener.\n")
+
+	for _, spec := range specs {
+		switch spec.Kind {
+		case SectionKindStatic:
+			continue
+		case SectionKindGame:
+			fmt.Fprintf(&b, "\nSection ID: %s\n", spec.SectionID)
+			b.WriteString("Section prompt: Play one round of the brain game described below.\n")
+			b.WriteString(strings.TrimSpace(gamePrompt))
+			b.WriteString("\n")
+			continue
+		}
+		fmt.Fprintf(&b, "\nSection ID: %s\n", spec.SectionID)
+		b.WriteString("Section prompt: ")
+		b.WriteString(strings.TrimSpace(spec.Prompt))
+		b.WriteString("\n")
+		if spec.WordBudget > 0 {
+			fmt.Fprintf(&b, "Target length: about %d spoken words.\n", spec.WordBudget)
+		}
+		if strings.TrimSpace(spec.TransitionInstructions) != "" {
+			b.WriteString("Transition instructions: ")
+			b.WriteString(strings.TrimSpace(spec.TransitionInstructions))
+			b.WriteString("\n")
+		}
 	}
 	return strings.TrimSpace(b.String())
 }
@@ -150,6 +150,20 @@ func BuildContinuityAnchor(text, sectionID string) string {
 	return last
 }
 
+// BuildLeadInAnchor returns the first 1-2 sentences of the section that
+// follows the one being written, so a rewritten section can end by flowing
+// into it. It is empty when that section has no text.
+func BuildLeadInAnchor(text, sectionID string) string {
+	sentences := splitSentences(text)
+	if len(sentences) == 0 {
+		return ""
+	}
+	if len(sentences) > 2 {
+		sentences = sentences[:2]
+	}
+	return fmt.Sprintf("The next section (%s) begins: %s\nEnd this section so it flows naturally into that opening.", sectionID, strings.Join(sentences, " "))
+}
+
 func splitSentences(text string) []string {
 	normalized := strings.ReplaceAll(strings.TrimSpace(text), "\n", " ")
 	var sentences []string
@@ -188,44 +202,3 @@ func sectionHeading(sectionID string) string {
 		return strings.Title(strings.ReplaceAll(sectionID, "-", " "))
 	}
 }
-
-type holiday struct {
-	Name        string
-	Description string
-}
-
-func holidayOnDate(date time.Time) (holiday, bool) {
-	date = date.UTC()
-	month := date.Month()
-	day := date.Day()
-	weekday := date.Weekday()
-
-	switch {
-	case month == time.January && day == 1:
-		return holiday{Name: "New Year's Day", Description: "celebrating a new year and fresh starts"}, true
-	case month == time.January && weekday == time.Monday && day >= 15 && day <= 21:
-		return holiday{Name: "Martin Luther King Jr. Day", Description: "honoring Dr. King and his work for equality and justice"}, true
-	case month == time.February && day == 14:
-		return holiday{Name: "Valentine's Day", Description: "showing appreciation for loved ones, friends, and family"}, true
-	case month == time.February && weekday == time.Monday && day >= 15 && day <= 21:
-		return holiday{Name: "Presidents Day", Description: "remembering U.S. presidents and leadership in history"}, true
-	case month == time.May && weekday == time.Monday && day+7 > 31:
-		return holiday{Name: "Memorial Day", Description: "remembering service members who gave their lives"}, true
-	case month == time.June && day == 19:
-		return holiday{Name: "Juneteenth", Description: "celebrating freedom and Black American history"}, true
-	case month == time.July && day == 4:
-		return holiday{Name: "Independence Day", Description: "celebrating U.S. independence"}, true
-	case month == time.September && weekday == time.Monday && day <= 7:
-		return holiday{Name: "Labor Day", Description: "recognizing workers and the work people do"}, true
-	case month == time.October && day == 31:
-		return holiday{Name: "Halloween", Description: "a day for costumes, creativity, and community fun"}, true
-	case month == time.November && day == 11:
-		return holiday{Name: "Veterans Day", Description: "honoring military veterans and their service"}, true
-	case month == time.November && weekday == time.Thursday && day >= 22 && day <= 28:
-		return holiday{Name: "Thanksgiving", Description: "sharing gratitude, family time, and thankfulness"}, true
-	case month == time.December && day == 25:
-		return holiday{Name: "Christmas Day", Description: "celebrating Christmas traditions, giving, and time with loved ones"}, true
-	default:
-		return holiday{}, false
-	}
-}
@@ -8,7 +8,10 @@ import (
 
 func TestIntroPromptIncludesDateAndWeekend(t *testing.T) {
 	date := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC) // Saturday
-	sections := StandardSectionSchema("Volcanoes", date)
+	sections, err := StandardSectionSchema("Volcanoes", date)
+	if err != nil {
+		t.Fatalf("StandardSectionSchema: %v", err)
+	}
 	if len(sections) == 0 {
 		t.Fatalf("expected sections")
 	}
@@ -23,7 +26,10 @@ func TestIntroPromptIncludesDateAndWeekend(t *testing.T) {
 
 func TestIntroPromptIncludesWeekday(t *testing.T) {
 	date := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC) // Monday
-	sections := StandardSectionSchema("Volcanoes", date)
+	sections, err := StandardSectionSchema("Volcanoes", date)
+	if err != nil {
+		t.Fatalf("StandardSectionSchema: %v", err)
+	}
 	prompt := sections[0].Prompt
 	if !strings.Contains(prompt, "Monday, January 19, 2026") {
 		t.Fatalf("expected date in prompt, got %q", prompt)
@@ -35,7 +41,10 @@ func TestIntroPromptIncludesWeekday(t *testing.T) {
 
 func TestIntroPromptIncludesFriYayOnFriday(t *testing.T) {
 	date := time.Date(2026, 1, 23, 0, 0, 0, 0, time.UTC) // Friday
-	sections := StandardSectionSchema("Volcanoes", date)
+	sections, err := StandardSectionSchema("Volcanoes", date)
+	if err != nil {
+		t.Fatalf("StandardSectionSchema: %v", err)
+	}
 	prompt := sections[0].Prompt
 	if !strings.Contains(prompt, "Fri-YAY!") {
 		t.Fatalf("expected Fri-YAY phrasing, got %q", prompt)
@@ -44,7 +53,10 @@ func TestIntroPromptIncludesFriYayOnFriday(t *testing.T) {
 
 func TestIntroPromptRequiresSingleLeadIn(t *testing.T) {
 	date := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)
-	sections := StandardSectionSchema("Volcanoes", date)
+	sections, err := StandardSectionSchema("Volcanoes", date)
+	if err != nil {
+		t.Fatalf("StandardSectionSchema: %v", err)
+	}
 	prompt := sections[0].Prompt
 	if !strings.Contains(prompt, "exactly one short sentence that introduces") {
 		t.Fatalf("expected single lead-in guidance, got %q", prompt)
@@ -56,7 +68,10 @@ func TestIntroPromptRequiresSingleLeadIn(t *testing.T) {
 
 func TestTopicSectionUsesDirectTransitionInstructions(t *testing.T) {
 	date := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)
-	sections := StandardSectionSchema("Volcanoes", date)
+	sections, err := StandardSectionSchema("Volcanoes", date)
+	if err != nil {
+		t.Fatalf("StandardSectionSchema: %v", err)
+	}
 	if len(sections) < 2 {
 		t.Fatalf("expected topic section")
 	}
@@ -71,7 +86,10 @@ func TestTopicSectionUsesDirectTransitionInstructions(t *testing.T) {
 
 func TestIntroPromptIncludesTodayHolidayGuidance(t *testing.T) {
 	date := time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC) // Valentine's Day
-	sections := StandardSectionSchema("Meteorites", date)
+	sections, err := StandardSectionSchema("Meteorites", date)
+	if err != nil {
+		t.Fatalf("StandardSectionSchema: %v", err)
+	}
 	prompt := sections[0].Prompt
 	if !strings.Contains(prompt, "today is Valentine's Day") {
 		t.Fatalf("expected holiday name in intro prompt, got %q", prompt)
@@ -86,7 +104,10 @@ func TestIntroPromptIncludesTodayHolidayGuidance(t *testing.T) {
 
 func TestOutroPromptIncludesTomorrowHolidayGuidance(t *testing.T) {
 	date := time.Date(2026, 2, 13, 0, 0, 0, 0, time.UTC) // Tomorrow is Valentine's Day
-	sections := StandardSectionSchema("Meteorites", date)
+	sections, err := StandardSectionSchema("Meteorites", date)
+	if err != nil {
+		t.Fatalf("StandardSectionSchema: %v", err)
+	}
 	prompt := sections[2].Prompt
 	if !strings.Contains(prompt, "tomorrow is Valentine's Day") {
 		t.Fatalf("expected holiday name in outro prompt, got %q", prompt)
@@ -98,7 +119,10 @@ func TestOutroPromptIncludesTomorrowHolidayGuidance(t *testing.T) {
 
 func TestPromptsDoNotMentionHolidayWhenNone(t *testing.T) {
 	date := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
-	sections := StandardSectionSchema("Meteorites", date)
+	sections, err := StandardSectionSchema("Meteorites", date)
+	if err != nil {
+		t.Fatalf("StandardSectionSchema: %v", err)
+	}
 	if strings.Contains(sections[0].Prompt, "If you're celebrating") {
 		t.Fatalf("did not expect holiday guidance in intro prompt, got %q", sections[0].Prompt)
 	}
@@ -106,3 +130,37 @@ func TestPromptsDoNotMentionHolidayWhenNone(t *testing.T) {
 		t.Fatalf("did not expect holiday guidance in outro prompt, got %q", sections[2].Prompt)
 	}
 }
+
+func TestIntroPromptIncludesSkyEvents(t *testing.T) {
+	date := time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC) // Mars at opposition
+	sections, err := StandardSectionSchema("Red Planets", date)
+	if err != nil {
+		t.Fatalf("StandardSectionSchema: %v", err)
+	}
+	if strings.Contains(sections[0].Prompt, "look up at the sky") {
+		t.Fatalf("expected sky events to be off by default, got %q", sections[0].Prompt)
+	}
+
+	prompts := DefaultPrompts()
+	prompts.SkyEvents = true
+	specs, err := DefaultShow().Specs(prompts, "Red Planets", date)
+	if err != nil {
+		t.Fatalf("Specs: %v", err)
+	}
+	if !strings.Contains(specs[0].Prompt, "look up at the sky") || !strings.Contains(specs[0].Prompt, "Mars is at opposition") {
+		t.Fatalf("expected sky event in intro prompt, got %q", specs[0].Prompt)
+	}
+	if strings.Contains(specs[3].Prompt, "look up at the sky") {
+		t.Fatalf("did not expect sky events in outro prompt, got %q", specs[3].Prompt)
+	}
+}
+
+func TestBuildLeadInAnchor(t *testing.T) {
+	anchor := BuildLeadInAnchor("[excited] Time for a game! Are you ready? Let's go.", "game")
+	if !strings.HasPrefix(anchor, "The next section (game) begins: [excited] Time for a game! Are you ready?\n") {
+		t.Fatalf("unexpected anchor: %q", anchor)
+	}
+	if BuildLeadInAnchor("  ", "game") != "" {
+		t.Fatalf("expected no anchor for an empty section")
+	}
+}
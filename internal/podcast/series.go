@@ -0,0 +1,159 @@
+package podcast
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"yodex/internal/ai"
+	"yodex/internal/config"
+)
+
+// SeriesPart is one planned episode of a multi-part series.
+type SeriesPart struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// TopicSeries places an episode in a multi-part series: Part (1-based) of
+// the arc planned when the series began.
+type TopicSeries struct {
+	Title string       `json:"title"`
+	Part  int          `json:"part"`
+	Parts []SeriesPart `json:"parts"`
+}
+
+// Total returns the number of parts in the series.
+func (s *TopicSeries) Total() int { return len(s.Parts) }
+
+// Current returns the episode's own part.
+func (s *TopicSeries) Current() SeriesPart {
+	if s.Part < 1 || s.Part > len(s.Parts) {
+		return SeriesPart{}
+	}
+	return s.Parts[s.Part-1]
+}
+
+// Earlier returns the parts before this episode's, for a recap.
+func (s *TopicSeries) Earlier() []SeriesPart {
+	if s.Part < 1 || s.Part > len(s.Parts) {
+		return nil
+	}
+	return s.Parts[:s.Part-1]
+}
+
+// Next returns the part after this episode's, or nil for the final part.
+func (s *TopicSeries) Next() *SeriesPart {
+	if s.Part < 1 || s.Part >= len(s.Parts) {
+		return nil
+	}
+	return &s.Parts[s.Part]
+}
+
+// topic is the episode topic for the series part.
+func (s *TopicSeries) topic() string {
+	return fmt.Sprintf("%s: %s", s.Title, s.Current().Title)
+}
+
+// EpisodeSeries returns the series part recorded in the topic history for
+// date, or nil if the episode with that topic is not part of a series.
+func EpisodeSeries(ctx context.Context, cfg config.Config, date time.Time, topic string) *TopicSeries {
+	history := loadTopicHistory(ctx, cfg)
+	entry, ok := history.Entries[date.Format("2006-01-02")]
+	if !ok || entry.Series == nil || entry.Topic != topic {
+		return nil
+	}
+	return entry.Series
+}
+
+// nextSeriesPart returns the series part for date: the part already recorded
+// for it, the next part of the latest series in progress, or the first part
+// of a configured series that is due, planning its arc. Planning failures are
+// logged and leave the date to regular topic selection.
+func nextSeriesPart(ctx context.Context, cfg config.Config, history TopicHistory, date time.Time, gen TextGenerator) (*TopicSeries, ai.TokenUsage) {
+	key := date.Format("2006-01-02")
+	if entry, ok := history.Entries[key]; ok && entry.Series != nil {
+		return entry.Series, ai.TokenUsage{}
+	}
+	started := map[string]bool{}
+	var latest *TopicSeries
+	for _, day := range history.Dates() {
+		if series := history.Entries[day].Series; series != nil {
+			started[series.Title] = true
+			if day < key {
+				latest = series
+			}
+		}
+	}
+	if latest != nil && latest.Part < latest.Total() {
+		next := *latest
+		next.Part++
+		return &next, ai.TokenUsage{}
+	}
+
+	for _, planned := range cfg.Series {
+		if planned.Start > key || started[planned.Title] {
+			continue
+		}
+		parts := make([]SeriesPart, 0, len(planned.Outline))
+		for _, title := range planned.Outline {
+			parts = append(parts, SeriesPart{Title: strings.TrimSpace(title)})
+		}
+		var usage ai.TokenUsage
+		if len(parts) == 0 {
+			if gen == nil {
+				slog.Warn("cannot plan series without an ai client", "series", planned.Title)
+				continue
+			}
+			var err error
+			parts, usage, err = planSeries(ctx, cfg, date, gen, planned)
+			if err != nil {
+				slog.Warn("failed to plan series", "series", planned.Title, "err", err)
+				continue
+			}
+		}
+		slog.Info("series planned", "series", planned.Title, "parts", len(parts))
+		return &TopicSeries{Title: planned.Title, Part: 1, Parts: parts}, usage
+	}
+	return nil, ai.TokenUsage{}
+}
+
+var seriesLinePrefix = regexp.MustCompile(`(?i)^(?:[-*•]|\d+[.):]|part \d+[.):]?)\s*`)
+
+// planSeries asks the text model for the series arc: a title and a
+// one-sentence summary for each part.
+func planSeries(ctx context.Context, cfg config.Config, date time.Time, gen TextGenerator, series config.Series) ([]SeriesPart, ai.TokenUsage, error) {
+	system, _, err := buildTopicPrompts(cfg, date, nil)
+	if err != nil {
+		return nil, ai.TokenUsage{}, err
+	}
+	n := series.PartCount()
+	prompt := fmt.Sprintf("Plan a %d-part series called %q for kids ages %s. "+
+		"Each part is one episode that builds on the parts before it, from the basics to the most exciting ideas. "+
+		"Reply with exactly %d lines, one per part in order, each formatted as: part title | one sentence on what the part covers.",
+		n, series.Title, IdentityFromConfig(cfg).AgeRange, n)
+	text, usage, err := generateTopicText(ctx, gen, cfg.TextModel, system, prompt)
+	if err != nil {
+		return nil, usage, err
+	}
+	var parts []SeriesPart
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(seriesLinePrefix.ReplaceAllString(strings.TrimSpace(line), ""))
+		if line == "" {
+			continue
+		}
+		title, summary, _ := strings.Cut(line, "|")
+		title = sanitizeTopic(title)
+		if title == "" {
+			continue
+		}
+		parts = append(parts, SeriesPart{Title: title, Summary: strings.TrimSpace(summary)})
+	}
+	if len(parts) != n {
+		return nil, usage, fmt.Errorf("series plan has %d parts, expected %d", len(parts), n)
+	}
+	return parts, usage, nil
+}
@@ -0,0 +1,128 @@
+package podcast
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/config"
+)
+
+func TestSelectTopicRunsSeries(t *testing.T) {
+	cfg := writeTestTopicHistory(t, TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-05-01": {Topic: "Rainbows"},
+	}})
+	cfg.TopicBacklogPath = ""
+	cfg.Series = []config.Series{{Title: "Journey Through the Solar System", Start: "2026-05-04", Outline: []string{"The Sun", "Rocky Planets", "Gas Giants"}}}
+	ctx := context.Background()
+	gen := &scriptedTopicGen{topics: []string{"Volcanoes"}}
+
+	// Before the start date topics are generated as usual.
+	if topic, err := SelectTopic(ctx, time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC), cfg, gen); err != nil || topic != "Volcanoes" {
+		t.Fatalf("expected a generated topic before the series, got %q, %v", topic, err)
+	}
+	want := []string{
+		"Journey Through the Solar System: The Sun",
+		"Journey Through the Solar System: Rocky Planets",
+		"Journey Through the Solar System: Gas Giants",
+	}
+	for i, day := range []int{4, 5, 6} {
+		date := time.Date(2026, 5, day, 0, 0, 0, 0, time.UTC)
+		topic, err := SelectTopic(ctx, date, cfg, gen)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if topic != want[i] {
+			t.Fatalf("day %d: expected %q, got %q", day, want[i], topic)
+		}
+		series := EpisodeSeries(ctx, cfg, date, topic)
+		if series == nil || series.Part != i+1 || series.Total() != 3 {
+			t.Fatalf("day %d: expected part %d of 3 in history, got %+v", day, i+1, series)
+		}
+	}
+	if len(gen.prompts) != 1 {
+		t.Fatalf("expected series parts without model calls, got %d prompts", len(gen.prompts))
+	}
+
+	// A rerun keeps its part, and the finished series does not start again.
+	if topic, _ := SelectTopic(ctx, time.Date(2026, 5, 5, 0, 0, 0, 0, time.UTC), cfg, gen); topic != want[1] {
+		t.Fatalf("expected the rerun to keep its part, got %q", topic)
+	}
+	if topic, _ := SelectTopic(ctx, time.Date(2026, 5, 7, 0, 0, 0, 0, time.UTC), cfg, gen); topic != "Volcanoes" {
+		t.Fatalf("expected a generated topic after the series, got %q", topic)
+	}
+}
+
+func TestPlanSeriesWithModel(t *testing.T) {
+	cfg := writeTestTopicHistory(t, TopicHistory{})
+	cfg.TopicBacklogPath = ""
+	cfg.Series = []config.Series{{Title: "Inside the Body", Start: "2026-05-04", Parts: 3}}
+	gen := &scriptedTopicGen{topics: []string{"Part 1: The Heart | How the heart pumps blood.\n2) Lungs | How we breathe.\n- Bones | What holds us up."}}
+	date := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	topic, err := SelectTopic(context.Background(), date, cfg, gen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if topic != "Inside the Body: The Heart" {
+		t.Fatalf("unexpected topic %q", topic)
+	}
+	if !strings.Contains(gen.prompts[0], "Reply with exactly 3 lines") {
+		t.Fatalf("expected a planning prompt, got %q", gen.prompts[0])
+	}
+	series := readTestTopicHistory(t, cfg).Entries["2026-05-04"].Series
+	if series == nil || len(series.Parts) != 3 || series.Parts[1] != (SeriesPart{Title: "Lungs", Summary: "How we breathe."}) {
+		t.Fatalf("expected the planned arc in history, got %+v", series)
+	}
+
+	// A plan with the wrong number of parts falls back to a generated topic.
+	cfg = writeTestTopicHistory(t, TopicHistory{})
+	cfg.TopicBacklogPath = ""
+	cfg.Series = []config.Series{{Title: "Inside the Body", Start: "2026-05-04", Parts: 3}}
+	gen = &scriptedTopicGen{topics: []string{"The Heart | Pumping.", "Volcanoes"}}
+	if topic, _ := SelectTopic(context.Background(), date, cfg, gen); topic != "Volcanoes" {
+		t.Fatalf("expected a generated topic after a bad plan, got %q", topic)
+	}
+}
+
+func TestSeriesPrompts(t *testing.T) {
+	series := &TopicSeries{Title: "Solar System", Part: 2, Parts: []SeriesPart{
+		{Title: "The Sun", Summary: "Our star."},
+		{Title: "Rocky Planets", Summary: "Mercury to Mars."},
+		{Title: "Gas Giants"},
+	}}
+	prompts := DefaultPrompts()
+	prompts.Series = series
+	date := time.Date(2026, 5, 5, 0, 0, 0, 0, time.UTC)
+	specs, err := DefaultShow().Specs(prompts, "Solar System: Rocky Planets", date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := map[string]string{}
+	for _, spec := range specs {
+		got[spec.SectionID] = spec.Prompt
+	}
+	if !strings.Contains(got["intro"], `part 2 of 3 in the series "Solar System"`) || !strings.Contains(got["intro"], "recap of the earlier parts: The Sun (Our star.).") {
+		t.Fatalf("expected part and recap in intro, got %q", got["intro"])
+	}
+	if !strings.Contains(got["topic"], "This part of the series covers: Mercury to Mars.") {
+		t.Fatalf("expected part summary in topic, got %q", got["topic"])
+	}
+	if !strings.Contains(got["outro"], `next part of the series: "Gas Giants"`) {
+		t.Fatalf("expected teaser in outro, got %q", got["outro"])
+	}
+
+	series.Part = 3
+	specs, _ = DefaultShow().Specs(prompts, "Solar System: Gas Giants", date)
+	if outro := specs[len(specs)-1].Prompt; !strings.Contains(outro, `final part of the series "Solar System"`) {
+		t.Fatalf("expected a finale in outro, got %q", outro)
+	}
+
+	prompts.Series = nil
+	specs, _ = DefaultShow().Specs(prompts, "Volcanoes", date)
+	for _, spec := range specs {
+		if strings.Contains(spec.Prompt, "series") {
+			t.Fatalf("expected no series text without a series, got %q", spec.Prompt)
+		}
+	}
+}
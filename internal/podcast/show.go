@@ -0,0 +1,327 @@
+package podcast
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"yodex/internal/astro"
+)
+
+// SectionKind says how a section's text is produced.
+type SectionKind string
+
+const (
+	// SectionKindGenerated sections are written by the text model from a prompt.
+	SectionKindGenerated SectionKind = "generated"
+	// SectionKindGame sections play the day's brain game.
+	SectionKindGame SectionKind = "game"
+	// SectionKindStatic sections use fixed text from the show definition.
+	SectionKindStatic SectionKind = "static"
+)
+
+// ShowSection describes one section of an episode. Prompt and Text are
+// text/template strings rendered with PromptData. A game section's Prompt, if
+// set, replaces the game-system prompt template, and Game, if set, names the
+// game it plays instead of the weekday's game. A WordBudget written in the
+// show definition is enforced on every run; budgets inherited from the
+// built-in sections are only enforced with a target word count.
+type ShowSection struct {
+	ID         string      `json:"id"`
+	Kind       SectionKind `json:"kind,omitempty"`
+	Prompt     string      `json:"prompt,omitempty"`
+	Transition string      `json:"transition,omitempty"`
+	WordBudget int         `json:"wordBudget,omitempty"`
+	Text       string      `json:"text,omitempty"`
+	Game       string      `json:"game,omitempty"`
+
+	budgetFromShow bool
+}
+
+// ShowDefinition lists an episode's sections in the order they are heard.
+type ShowDefinition struct {
+	Sections []ShowSection `json:"sections"`
+}
+
+// PromptData is the data available to prompt and section templates. The show
+// identity's fields are promoted, so templates can use {{.ShowName}}. Dates and
+// the quoted phrases are already localized to the episode language.
+type PromptData struct {
+	Identity
+	Topic           string
+	Date            time.Time
+	DateLabel       string // "Monday, January 2, 2006"
+	ShortDateLabel  string // "Monday, January 2"
+	DayPhrase       string // "day", "Fri-YAY!", or "weekend"
+	Holiday         *Holiday
+	TomorrowHoliday *Holiday
+	SkyEvents       []astro.Event  // moon phases, seasons, meteor showers, and planet events today
+	Series          *TopicSeries   // nil unless the episode is part of a series
+	Recap           []RecapEpisode // the week's earlier episodes, for the weekly recap
+
+	Language                string // English name of the episode language, e.g. "Spanish"
+	LanguageInstruction     string // empty for English
+	GameLanguageInstruction string // empty when the game is played in English
+	WarmWish                string // "I hope everyone has an amazing Monday, January 2."
+	HolidayTodayLine        string
+	HolidayTomorrowLine     string
+}
+
+// NewPromptData builds template data for a show identity, the episode and game
+// locales, a holiday calendar, a topic, and an episode date. Labels, holidays,
+// and sky events use the calendar day in the date's location.
+func NewPromptData(identity Identity, locale, gameLocale Locale, calendar Calendar, topic string, date time.Time) PromptData {
+	data := PromptData{
+		Identity:       identity,
+		Topic:          topic,
+		Date:           date,
+		DateLabel:      locale.LongDate(date),
+		ShortDateLabel: locale.ShortDate(date),
+		DayPhrase:      locale.DayPhrase(date),
+
+		Language:                locale.Name,
+		LanguageInstruction:     locale.LanguageInstruction(),
+		GameLanguageInstruction: GameLanguageInstruction(locale, gameLocale),
+		WarmWish:                fmt.Sprintf(locale.phrases.warmWish, locale.ShortDate(date)),
+		HolidayTodayLine:        locale.phrases.holidayToday,
+		HolidayTomorrowLine:     locale.phrases.holidayTomorrow,
+	}
+	if h, ok := calendar.On(date, locale); ok {
+		data.Holiday = &h
+	}
+	if h, ok := calendar.On(date.AddDate(0, 0, 1), locale); ok {
+		data.TomorrowHoliday = &h
+	}
+	return data
+}
+
+const defaultIntroPrompt = `Write a warm, friendly podcast welcome for kids that sounds like welcoming a group of friends. ` +
+	`Greet listeners to the "{{.ShowName}}" and introduce the host, {{.HostName}}. ` +
+	`Mention today's date ({{.DateLabel}}) and say you hope everyone is having a wonderful {{.DayPhrase}}. ` +
+	`Keep it 3-5 sentences, upbeat, and welcoming. ` +
+	`End with exactly one short sentence that introduces {{printf "%q" .Topic}}. ` +
+	`Do not add a second teaser or additional lead-in sentence after that.` +
+	`{{with .Holiday}} Before introducing {{printf "%q" $.Topic}}, briefly recognize that today is {{.Name}}. ` +
+	`Add one short, kid-friendly sentence about what the holiday celebrates ({{.Description}}), ` +
+	`then include: "{{$.HolidayTodayLine}}"{{end}}` +
+	`{{with .SkyEvents}} Before introducing {{printf "%q" $.Topic}}, add one short sentence inviting listeners to look up at the sky, ` +
+	`based on: {{range $i, $e := .}}{{if $i}} {{end}}{{$e.Description}}{{end}}{{end}}` +
+	`{{with .Series}} When introducing the topic, say this is part {{.Part}} of {{.Total}} in the series {{printf "%q" .Title}}.` +
+	`{{with .Earlier}} First give a quick recap of the earlier parts: ` +
+	`{{range $i, $p := .}}{{if $i}}; {{end}}{{$p.Title}}{{with $p.Summary}} ({{.}}){{end}}{{end}}.{{end}}{{end}}`
+
+const defaultTopicPrompt = `Explain the core idea about {{printf "%q" .Topic}} in a clear, curious voice, then add a deeper dive. ` +
+	`Use relatable analogies and include one surprising fact. Keep it 4-6 short paragraphs total.` +
+	`{{with .Series}}{{with .Current.Summary}} This part of the series covers: {{.}}{{end}}` +
+	`{{if .Earlier}} Build on what the earlier parts covered instead of repeating it.{{end}}{{end}}`
+
+const defaultOutroPrompt = `Wrap up the episode about {{printf "%q" .Topic}} with a friendly recap and a thoughtful question for listeners. ` +
+	`Use first-person voice as {{.HostName}}. ` +
+	`Instead of a mechanical date callout, weave it into a warm wish like: "{{.WarmWish}}" ` +
+	`Keep it 3-5 sentences.` +
+	`{{with .TomorrowHoliday}} Also mention that tomorrow is {{.Name}}. ` +
+	`Add one short, kid-friendly sentence about what the holiday celebrates ({{.Description}}), ` +
+	`then include: "{{$.HolidayTomorrowLine}}"{{end}}` +
+	`{{with .Series}}{{with .Next}} Tease the next episode, the next part of the series: {{printf "%q" .Title}}.` +
+	`{{else}} Celebrate that this was the final part of the series {{printf "%q" .Title}}.{{end}}{{end}}`
+
+var defaultShowSections = []ShowSection{
+	{ID: "intro", Kind: SectionKindGenerated, Prompt: defaultIntroPrompt, Transition: defaultTransitionPromptSuffix, WordBudget: DefaultIntroWordBudget},
+	{ID: "topic", Kind: SectionKindGenerated, Prompt: defaultTopicPrompt, Transition: topicTransitionPromptSuffix, WordBudget: DefaultTopicWordBudget},
+	{ID: "game", Kind: SectionKindGame, WordBudget: DefaultGameWordBudget},
+	{ID: "outro", Kind: SectionKindGenerated, Prompt: defaultOutroPrompt, Transition: defaultTransitionPromptSuffix, WordBudget: DefaultOutroWordBudget},
+}
+
+// DefaultShow returns the built-in intro/topic/game/outro structure.
+func DefaultShow() ShowDefinition {
+	sections := make([]ShowSection, len(defaultShowSections))
+	copy(sections, defaultShowSections)
+	return ShowDefinition{Sections: sections}
+}
+
+// LoadShowDefinition reads a JSON show definition. An empty path returns the
+// default show. Sections that reuse a default ID (intro, topic, game, outro)
+// inherit any field they leave empty from the default section.
+func LoadShowDefinition(path string) (ShowDefinition, error) {
+	if strings.TrimSpace(path) == "" {
+		return DefaultShow(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ShowDefinition{}, fmt.Errorf("read show definition: %w", err)
+	}
+	var show ShowDefinition
+	if err := json.Unmarshal(data, &show); err != nil {
+		return ShowDefinition{}, fmt.Errorf("parse show definition %s: %w", path, err)
+	}
+	defaults := make(map[string]ShowSection, len(defaultShowSections))
+	for _, section := range defaultShowSections {
+		defaults[section.ID] = section
+	}
+	for i, section := range show.Sections {
+		section.budgetFromShow = section.WordBudget > 0
+		show.Sections[i] = section
+		def, ok := defaults[section.ID]
+		if !ok {
+			continue
+		}
+		if section.Kind == "" {
+			section.Kind = def.Kind
+		}
+		if section.Kind == def.Kind {
+			if section.Prompt == "" {
+				section.Prompt = def.Prompt
+			}
+			if section.Transition == "" {
+				section.Transition = def.Transition
+			}
+			if section.WordBudget == 0 {
+				section.WordBudget = def.WordBudget
+			}
+		}
+		show.Sections[i] = section
+	}
+	if err := show.Validate(); err != nil {
+		return ShowDefinition{}, fmt.Errorf("show definition %s: %w", path, err)
+	}
+	return show, nil
+}
+
+// sectionIDPattern limits section IDs to names that are safe as file names.
+var sectionIDPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// reservedSectionIDs would collide with the episode's own output files.
+var reservedSectionIDs = map[string]bool{"episode": true, "meta": true, "review": true, "factcheck": true}
+
+// Validate checks section IDs, kinds, and templates. Section IDs name the
+// section's output files, so they use lowercase letters, digits, and hyphens
+// and must not reuse an episode file name.
+func (s ShowDefinition) Validate() error {
+	if len(s.Sections) == 0 {
+		return errors.New("at least one section is required")
+	}
+	seen := make(map[string]bool, len(s.Sections))
+	games := 0
+	generated := 0
+	for _, section := range s.Sections {
+		id := strings.TrimSpace(section.ID)
+		if id == "" {
+			return errors.New("section id is required")
+		}
+		if !sectionIDPattern.MatchString(section.ID) {
+			return fmt.Errorf("invalid section id %q (use lowercase letters, digits, and hyphens)", section.ID)
+		}
+		if reservedSectionIDs[id] {
+			return fmt.Errorf("section id %q is reserved for an episode file", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicate section id: %s", id)
+		}
+		seen[id] = true
+		switch section.kind() {
+		case SectionKindGenerated:
+			generated++
+			if strings.TrimSpace(section.Prompt) == "" {
+				return fmt.Errorf("section %s: prompt is required", id)
+			}
+		case SectionKindGame:
+			games++
+		case SectionKindStatic:
+			if strings.TrimSpace(section.Text) == "" {
+				return fmt.Errorf("section %s: text is required for static sections", id)
+			}
+		default:
+			return fmt.Errorf("section %s: unknown kind %q (expected generated, game, or static)", id, section.Kind)
+		}
+		if section.WordBudget < 0 {
+			return fmt.Errorf("section %s: word budget must not be negative", id)
+		}
+		for _, tmpl := range []string{section.Prompt, section.Transition, section.Text} {
+			if _, err := template.New(id).Parse(tmpl); err != nil {
+				return fmt.Errorf("section %s: %w", id, err)
+			}
+		}
+	}
+	if games > 1 {
+		return errors.New("at most one game section is allowed")
+	}
+	if generated == 0 {
+		return errors.New("at least one generated section is required")
+	}
+	return nil
+}
+
+// SectionIDs returns the section IDs in episode order.
+func (s ShowDefinition) SectionIDs() []string {
+	ids := make([]string, 0, len(s.Sections))
+	for _, section := range s.Sections {
+		ids = append(ids, section.ID)
+	}
+	return ids
+}
+
+// Specs renders every section's templates for a topic and date and returns
+// the specs in episode order. A game section's Prompt is its game system
+// prompt: the section's own prompt if set, otherwise the game-system template.
+func (s ShowDefinition) Specs(prompts Prompts, topic string, date time.Time) ([]SectionSpec, error) {
+	data := prompts.Data(topic, date)
+	specs := make([]SectionSpec, 0, len(s.Sections))
+	for _, section := range s.Sections {
+		spec := SectionSpec{
+			SectionID:  section.ID,
+			Kind:       section.kind(),
+			WordBudget: section.WordBudget,
+			Language:   prompts.Locale.Tag,
+
+			EnforceWordBudget: section.budgetFromShow,
+		}
+		if spec.Kind == SectionKindGame {
+			spec.Language = prompts.GameLocale.Tag
+			spec.Game = section.Game
+		}
+		var err error
+		if spec.Prompt, err = renderTemplate(section.ID, prompts.sectionPrompt(section), data); err != nil {
+			return nil, err
+		}
+		if spec.Kind == SectionKindGame && spec.Prompt == "" {
+			if spec.Prompt, err = prompts.Render(PromptGameSystem, data); err != nil {
+				return nil, err
+			}
+		}
+		if spec.TransitionInstructions, err = renderTemplate(section.ID, section.Transition, data); err != nil {
+			return nil, err
+		}
+		if spec.Text, err = renderTemplate(section.ID, section.Text, data); err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func (s ShowSection) kind() SectionKind {
+	if s.Kind == "" {
+		return SectionKindGenerated
+	}
+	return SectionKind(strings.ToLower(string(s.Kind)))
+}
+
+func renderTemplate(name, text string, data PromptData) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", name, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("template %s: %w", name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
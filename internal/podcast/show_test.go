@@ -0,0 +1,101 @@
+package podcast
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeShow(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "show.json")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write show: %v", err)
+	}
+	return path
+}
+
+func TestLoadShowDefinitionInheritsDefaults(t *testing.T) {
+	path := writeShow(t, `{"sections": [
+		{"id": "intro"},
+		{"id": "topic", "wordBudget": 300},
+		{"id": "joke", "prompt": "Tell one kid-friendly joke about {{.Topic}}.", "wordBudget": 40},
+		{"id": "game"},
+		{"id": "sponsor", "kind": "static", "text": "Thanks for listening on {{.ShortDateLabel}}!"},
+		{"id": "outro"}
+	]}`)
+	show, err := LoadShowDefinition(path)
+	if err != nil {
+		t.Fatalf("LoadShowDefinition: %v", err)
+	}
+	if got := strings.Join(show.SectionIDs(), ","); got != "intro,topic,joke,game,sponsor,outro" {
+		t.Fatalf("unexpected section order: %s", got)
+	}
+	specs, err := show.Specs(DefaultPrompts(), "Bats", time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Specs: %v", err)
+	}
+	if !strings.Contains(specs[0].Prompt, "Curious World Podcast") || specs[0].WordBudget != DefaultIntroWordBudget {
+		t.Fatalf("expected intro to inherit the default prompt and budget: %+v", specs[0])
+	}
+	if specs[1].WordBudget != 300 || !strings.Contains(specs[1].TransitionInstructions, "Start teaching the topic") {
+		t.Fatalf("expected topic override with inherited transition: %+v", specs[1])
+	}
+	if specs[2].Kind != SectionKindGenerated || specs[2].Prompt != "Tell one kid-friendly joke about Bats." {
+		t.Fatalf("unexpected joke spec: %+v", specs[2])
+	}
+	if specs[3].Kind != SectionKindGame {
+		t.Fatalf("expected game kind, got %+v", specs[3])
+	}
+	if specs[4].Kind != SectionKindStatic || specs[4].Text != "Thanks for listening on Monday, January 19!" {
+		t.Fatalf("unexpected static spec: %+v", specs[4])
+	}
+	if budgets := EnforcedWordBudgets(specs, false); len(budgets) != 2 || budgets["topic"] != 300 || budgets["joke"] != 40 {
+		t.Fatalf("expected only the show's own budgets enforced without a target, got %v", budgets)
+	}
+	if budgets := EnforcedWordBudgets(specs, true); len(budgets) != 5 || budgets["intro"] != DefaultIntroWordBudget {
+		t.Fatalf("expected every budget enforced with a target, got %v", budgets)
+	}
+}
+
+func TestShowDefinitionValidate(t *testing.T) {
+	cases := map[string]string{
+		"duplicate":    `{"sections": [{"id": "intro"}, {"id": "intro"}]}`,
+		"no prompt":    `{"sections": [{"id": "joke"}]}`,
+		"static text":  `{"sections": [{"id": "intro"}, {"id": "ad", "kind": "static"}]}`,
+		"two games":    `{"sections": [{"id": "intro"}, {"id": "game"}, {"id": "quiz", "kind": "game"}]}`,
+		"unknown kind": `{"sections": [{"id": "intro"}, {"id": "song", "kind": "music"}]}`,
+		"bad template": `{"sections": [{"id": "joke", "prompt": "{{.Topic"}]}`,
+		"path id":      `{"sections": [{"id": "../joke", "prompt": "Tell a joke."}]}`,
+		"uppercase id": `{"sections": [{"id": "Joke", "prompt": "Tell a joke."}]}`,
+		"reserved id":  `{"sections": [{"id": "episode", "prompt": "Tell a joke."}]}`,
+		"meta id":      `{"sections": [{"id": "intro"}, {"id": "meta", "prompt": "Tell a joke."}]}`,
+	}
+	for name, body := range cases {
+		if _, err := LoadShowDefinition(writeShow(t, body)); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+	if err := DefaultShow().Validate(); err != nil {
+		t.Fatalf("default show should be valid: %v", err)
+	}
+}
+
+func TestEpisodeValidateSections(t *testing.T) {
+	ep := Episode{Title: "Bats", Sections: []EpisodeSection{
+		{SectionID: "intro", Text: "Hi."},
+		{SectionID: "joke", Text: "Why did the bat...?"},
+	}}
+	if err := ep.ValidateSections([]string{"intro", "joke"}); err != nil {
+		t.Fatalf("ValidateSections: %v", err)
+	}
+	if err := ep.Validate(); err == nil {
+		t.Fatalf("expected default show validation to require topic, game, and outro")
+	}
+	ep.Sections = append(ep.Sections, EpisodeSection{SectionID: "joke", Text: "A second joke."})
+	if err := ep.ValidateSections([]string{"intro", "joke"}); err == nil || !strings.Contains(err.Error(), "duplicate section: joke") {
+		t.Fatalf("expected duplicate section error, got %v", err)
+	}
+}
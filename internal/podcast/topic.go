@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"strings"
 	"time"
 
@@ -21,94 +23,169 @@ type TextGeneratorWithUsage interface {
 	GenerateTextWithUsage(ctx context.Context, model, system, prompt string) (string, ai.TokenUsage, error)
 }
 
-const topicSystemPrompt = "You propose safe, accurate science topics for advanced 7-year-olds."
-
 // SelectTopic returns the configured topic or proposes one via the AI client.
 func SelectTopic(ctx context.Context, date time.Time, cfg config.Config, ai TextGenerator) (string, error) {
-	if strings.TrimSpace(cfg.Topic) != "" {
-		return strings.TrimSpace(cfg.Topic), nil
-	}
-	if ai == nil {
-		return "", errors.New("ai client is required to generate a topic")
-	}
+	topic, _, err := SelectTopicWithUsage(ctx, date, cfg, ai)
+	return topic, err
+}
 
-	history, err := loadTopicHistory(ctx, cfg)
-	if err != nil {
-		return "", err
-	}
-	prompt := buildTopicHistoryPrompt(recentTopics(history))
-	text, err := ai.GenerateText(ctx, cfg.TextModel, topicSystemPrompt, prompt)
-	if err != nil {
-		return "", err
-	}
-	topic := sanitizeTopic(text)
-	if topic == "" {
-		return "", fmt.Errorf("empty topic generated")
-	}
-	if err := appendTopicHistory(ctx, cfg, history, date, TopicHistoryEntry{
-		Topic: topic,
-	}); err != nil {
-		return "", err
-	}
-	return topic, nil
+// rejectedTopic is a generated topic that was too close to a past one or in
+// a category to avoid.
+type rejectedTopic struct {
+	Topic  string
+	Reason string
+	Match  topicMatch
 }
 
-// SelectTopicWithUsage returns the topic and token usage if available.
+// SelectTopicWithUsage returns the topic and token usage if available. The
+// configured topic comes first, then the topic already recorded for the date
+// by an earlier run, a topic pinned to the date in the backlog, the next part of a series (see config.Series), the next queued
+// backlog topic, and only then a generated topic.
+// Generated topics too similar to a past topic (see config.TopicSimilarity),
+// or in a category the balancing rules avoid, are rejected and regenerated;
+// if every attempt is rejected, the closest to acceptable is used. Weekly
+// recaps in the history are ignored.
 func SelectTopicWithUsage(ctx context.Context, date time.Time, cfg config.Config, gen TextGenerator) (string, ai.TokenUsage, error) {
 	if strings.TrimSpace(cfg.Topic) != "" {
 		return strings.TrimSpace(cfg.Topic), ai.TokenUsage{}, nil
 	}
+	history := loadTopicHistory(ctx, cfg).withoutRecaps()
+	key := date.Format("2006-01-02")
+	if entry := history.Entries[key]; strings.TrimSpace(entry.Topic) != "" {
+		slog.Info("reusing topic recorded for date", "topic", entry.Topic)
+		return entry.Topic, ai.TokenUsage{}, nil
+	}
+	if topic, ok := takeBacklogTopic(ctx, cfg, date, false); ok {
+		return recordChosenTopic(ctx, cfg, date, gen, history, TopicHistoryEntry{Topic: topic, Backlog: BacklogPin})
+	}
+	if series, usage := nextSeriesPart(ctx, cfg, history, date, gen); series != nil {
+		slog.Info("continuing series", "series", series.Title, "part", series.Part, "of", series.Total())
+		topic, classifyUsage, err := recordChosenTopic(ctx, cfg, date, gen, history, TopicHistoryEntry{Topic: series.topic(), Series: series})
+		return topic, usage.Add(classifyUsage), err
+	}
+	if topic, ok := takeBacklogTopic(ctx, cfg, date, true); ok {
+		return recordChosenTopic(ctx, cfg, date, gen, history, TopicHistoryEntry{Topic: topic, Backlog: BacklogQueue})
+	}
 	if gen == nil {
 		return "", ai.TokenUsage{}, errors.New("ai client is required to generate a topic")
 	}
 
-	history, err := loadTopicHistory(ctx, cfg)
-	if err != nil {
-		return "", ai.TokenUsage{}, err
-	}
-	prompt := buildTopicHistoryPrompt(recentTopics(history))
+	similarity := newTopicSimilarity(cfg, gen, history, key)
+	plan := planTopicCategories(cfg, history, date)
 
-	if withUsage, ok := gen.(TextGeneratorWithUsage); ok {
-		text, usage, err := withUsage.GenerateTextWithUsage(ctx, cfg.TextModel, topicSystemPrompt, prompt)
+	var usage ai.TokenUsage
+	var rejected []rejectedTopic
+	var topic, category string
+	var classified bool
+	var embedding []float32
+	// Candidates are ranked by similarity score; one too close to a past
+	// topic ranks below any that is only in an avoided category.
+	bestRank := math.Inf(1)
+	for attempt := 1; attempt <= topicAttempts; attempt++ {
+		system, prompt, err := buildTopicPrompts(cfg, date, topicPromptHistory(history, key, cfg.TopicHistoryPromptLimit, rejected))
 		if err != nil {
 			return "", ai.TokenUsage{}, err
 		}
-		topic := sanitizeTopic(text)
-		if topic == "" {
+		prompt = buildRejectedTopicsPrompt(plan.guide(prompt), rejected)
+		text, genUsage, err := generateTopicText(ctx, gen, cfg.TextModel, system, prompt)
+		if err != nil {
+			return "", ai.TokenUsage{}, err
+		}
+		usage = usage.Add(genUsage)
+		candidate := sanitizeTopic(text)
+		if candidate == "" {
 			return "", ai.TokenUsage{}, fmt.Errorf("empty topic generated")
 		}
-		if err := appendTopicHistory(ctx, cfg, history, date, TopicHistoryEntry{
-			Topic: topic,
-		}); err != nil {
-			return "", ai.TokenUsage{}, err
+		match, vector := similarity.closest(ctx, candidate)
+		rank := match.Score
+		var candidateCategory, reason string
+		candidateClassified := false
+		if similarity.tooSimilar(match) {
+			rank++
+			reason = fmt.Sprintf("too close to %q", match.Topic)
+		} else if plan.balancing() {
+			var classifyUsage ai.TokenUsage
+			candidateCategory, classifyUsage = classifyTopic(ctx, gen, cfg.TextModel, plan.categories, candidate)
+			usage = usage.Add(classifyUsage)
+			candidateClassified = true
+			if ok, why := plan.allowed(candidateCategory); !ok {
+				reason = fmt.Sprintf("%s was %s", candidateCategory, why)
+			}
+		}
+		if reason == "" || rank < bestRank {
+			topic, category, classified, embedding, bestRank = candidate, candidateCategory, candidateClassified, vector, rank
+		}
+		if reason == "" {
+			break
+		}
+		slog.Warn("generated topic rejected", "topic", candidate, "reason", reason, "score", fmt.Sprintf("%.2f", match.Score), "attempt", attempt)
+		rejected = append(rejected, rejectedTopic{Topic: candidate, Reason: reason, Match: match})
+		if attempt == topicAttempts {
+			slog.Warn("no acceptable topic generated; using the closest candidate", "topic", topic)
 		}
-		return topic, usage, nil
+	}
+	if plan.balancing() && !classified {
+		var classifyUsage ai.TokenUsage
+		category, classifyUsage = classifyTopic(ctx, gen, cfg.TextModel, plan.categories, topic)
+		usage = usage.Add(classifyUsage)
 	}
 
-	text, err := gen.GenerateText(ctx, cfg.TextModel, topicSystemPrompt, prompt)
-	if err != nil {
-		return "", ai.TokenUsage{}, err
+	entry := TopicHistoryEntry{Topic: topic, Category: category, Theme: plan.theme}
+	if len(embedding) > 0 {
+		entry.Embedding = embedding
+		entry.EmbeddingModel = similarity.model
 	}
-	topic := sanitizeTopic(text)
-	if topic == "" {
-		return "", ai.TokenUsage{}, fmt.Errorf("empty topic generated")
+	if err := appendTopicHistory(ctx, cfg, date, entry, similarity.model, similarity.computed); err != nil {
+		return "", ai.TokenUsage{}, err
 	}
-	if err := appendTopicHistory(ctx, cfg, history, date, TopicHistoryEntry{
-		Topic: topic,
-	}); err != nil {
+	return topic, usage, nil
+}
+
+// recordChosenTopic stores a topic that was not generated, from the backlog
+// or a series, with its theme and, when balancing, its category.
+func recordChosenTopic(ctx context.Context, cfg config.Config, date time.Time, gen TextGenerator, history TopicHistory, entry TopicHistoryEntry) (string, ai.TokenUsage, error) {
+	plan := planTopicCategories(cfg, history, date)
+	entry.Theme = plan.theme
+	var usage ai.TokenUsage
+	if plan.balancing() && gen != nil {
+		entry.Category, usage = classifyTopic(ctx, gen, cfg.TextModel, plan.categories, entry.Topic)
+	}
+	if err := appendTopicHistory(ctx, cfg, date, entry, "", nil); err != nil {
 		return "", ai.TokenUsage{}, err
 	}
-	return topic, ai.TokenUsage{}, nil
+	return entry.Topic, usage, nil
+}
+
+// generateTopicText asks for a topic, reporting token usage when the
+// generator supports it.
+func generateTopicText(ctx context.Context, gen TextGenerator, model, system, prompt string) (string, ai.TokenUsage, error) {
+	if withUsage, ok := gen.(TextGeneratorWithUsage); ok {
+		return withUsage.GenerateTextWithUsage(ctx, model, system, prompt)
+	}
+	text, err := gen.GenerateText(ctx, model, system, prompt)
+	return text, ai.TokenUsage{}, err
+}
+
+// buildTopicPrompts renders the configured topic prompts and appends the
+// recent topics to avoid.
+func buildTopicPrompts(cfg config.Config, date time.Time, recent []string) (string, string, error) {
+	prompts, err := PromptsFromConfig(cfg)
+	if err != nil {
+		return "", "", err
+	}
+	data := prompts.Data("", date)
+	system, err := prompts.Render(PromptTopicSystem, data)
+	if err != nil {
+		return "", "", err
+	}
+	prompt, err := prompts.Render(PromptTopicRequest, data)
+	if err != nil {
+		return "", "", err
+	}
+	return system, buildTopicHistoryPrompt(prompt, recent), nil
 }
 
-func buildTopicHistoryPrompt(recent []string) string {
-	prompt := "Propose a single science topic for an advanced 7-year-old. " +
-		"Examples of topics: animals, cultural celebrations, science, astronomy, history, geography, physics, chemistry, biology, or nature. " +
-		"The topic should be interesting and engaging for a 7-year-old. " +
-		"The topic should be safe and appropriate for a 7-year-old. " +
-		"You may focus on a specific animal, plant, planet, star, or some other specific thing to do a deep-dive, or you may focus on a general science topic. " +
-		"The topic should be accurate and up to date. " +
-		"Reply with a short title only."
+func buildTopicHistoryPrompt(prompt string, recent []string) string {
 	if len(recent) == 0 {
 		return prompt
 	}
@@ -125,6 +202,21 @@ func buildTopicHistoryPrompt(recent []string) string {
 	return strings.TrimSpace(b.String())
 }
 
+// buildRejectedTopicsPrompt lists earlier candidates and why they were
+// rejected so the next attempt moves further away.
+func buildRejectedTopicsPrompt(prompt string, rejected []rejectedTopic) string {
+	if len(rejected) == 0 {
+		return prompt
+	}
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\nThese suggestions were rejected; choose a clearly different subject:\n")
+	for _, r := range rejected {
+		fmt.Fprintf(&b, "- %s (%s)\n", r.Topic, r.Reason)
+	}
+	return strings.TrimSpace(b.String())
+}
+
 func sanitizeTopic(text string) string {
 	trimmed := strings.TrimSpace(text)
 	if trimmed == "" {
@@ -0,0 +1,162 @@
+package podcast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"yodex/internal/config"
+)
+
+// TopicBacklog holds topics chosen ahead of time. Pins maps a YYYY-MM-DD
+// episode date to its topic; Queue lists topics, in order, for the next
+// episodes that have no pin.
+type TopicBacklog struct {
+	Pins  map[string]string `json:"pins"`
+	Queue []string          `json:"queue"`
+}
+
+// Where a backlog topic was taken from, as recorded in TopicHistoryEntry.
+const (
+	BacklogPin   = "pin"
+	BacklogQueue = "queue"
+)
+
+// PinDates returns the pinned dates, oldest first.
+func (b TopicBacklog) PinDates() []string {
+	keys := make([]string, 0, len(b.Pins))
+	for key := range b.Pins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Return puts back a topic taken from the backlog for date: a pin on its
+// date unless the date has been pinned again, or a queued topic at the front
+// of the queue unless it is already queued.
+func (b *TopicBacklog) Return(date, topic, source string) {
+	switch source {
+	case BacklogPin:
+		if strings.TrimSpace(b.Pins[date]) == "" {
+			b.Pins[date] = topic
+		}
+	case BacklogQueue:
+		if !slices.Contains(b.Queue, topic) {
+			b.Queue = append([]string{topic}, b.Queue...)
+		}
+	}
+}
+
+// TopicBacklogStore loads and updates the topic backlog.
+type TopicBacklogStore interface {
+	// Load returns the stored backlog, or an empty one if none exists yet.
+	Load(ctx context.Context) (TopicBacklog, error)
+	// Update applies fn to the latest stored backlog and saves the result.
+	// fn may be called more than once; an error from fn aborts the update.
+	Update(ctx context.Context, fn func(*TopicBacklog) error) error
+	// Location describes where the backlog lives, for logs and messages.
+	Location() string
+}
+
+// OpenTopicBacklog returns topic-backlog.json in S3 when a bucket is
+// configured and the local topicBacklogPath file otherwise.
+func OpenTopicBacklog(ctx context.Context, cfg config.Config) (TopicBacklogStore, error) {
+	m, err := openManifest(ctx, cfg, "topic-backlog.json", cfg.TopicBacklogPath)
+	if err != nil {
+		return nil, err
+	}
+	return manifestTopicBacklog{m}, nil
+}
+
+type manifestTopicBacklog struct {
+	m manifest
+}
+
+func (b manifestTopicBacklog) Load(ctx context.Context) (TopicBacklog, error) {
+	data, err := b.m.read(ctx)
+	if err != nil {
+		return TopicBacklog{}, err
+	}
+	return ParseTopicBacklog(data)
+}
+
+func (b manifestTopicBacklog) Update(ctx context.Context, fn func(*TopicBacklog) error) error {
+	return b.m.update(ctx, func(data []byte) ([]byte, error) {
+		backlog, err := ParseTopicBacklog(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(&backlog); err != nil {
+			return nil, err
+		}
+		return json.MarshalIndent(backlog, "", "  ")
+	})
+}
+
+func (b manifestTopicBacklog) Location() string { return b.m.Location() }
+
+// ParseTopicBacklog parses a topic backlog; nil data is an empty backlog.
+func ParseTopicBacklog(data []byte) (TopicBacklog, error) {
+	var backlog TopicBacklog
+	if data != nil {
+		if err := json.Unmarshal(data, &backlog); err != nil {
+			return TopicBacklog{}, fmt.Errorf("parse topic backlog: %w", err)
+		}
+	}
+	if backlog.Pins == nil {
+		backlog.Pins = map[string]string{}
+	}
+	if backlog.Queue == nil {
+		backlog.Queue = []string{}
+	}
+	return backlog, nil
+}
+
+// takeBacklogTopic removes and returns the topic pinned to date, or with
+// queued set the first queued topic. Backlog failures are logged and reported
+// as no topic so generation can take over.
+func takeBacklogTopic(ctx context.Context, cfg config.Config, date time.Time, queued bool) (string, bool) {
+	store, err := OpenTopicBacklog(ctx, cfg)
+	if err != nil {
+		slog.Warn("failed to initialize topic backlog store", "err", err)
+		return "", false
+	}
+	key := date.Format("2006-01-02")
+	backlog, err := store.Load(ctx)
+	if err != nil {
+		slog.Warn("failed to load topic backlog", "location", store.Location(), "err", err)
+		return "", false
+	}
+	if (!queued && backlog.Pins[key] == "") || (queued && len(backlog.Queue) == 0) {
+		return "", false
+	}
+	var topic string
+	err = store.Update(ctx, func(backlog *TopicBacklog) error {
+		topic = ""
+		if !queued {
+			topic = strings.TrimSpace(backlog.Pins[key])
+			delete(backlog.Pins, key)
+			return nil
+		}
+		for len(backlog.Queue) > 0 && topic == "" {
+			topic = strings.TrimSpace(backlog.Queue[0])
+			backlog.Queue = backlog.Queue[1:]
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Warn("failed to take topic from backlog", "location", store.Location(), "err", err)
+		return "", false
+	}
+	if topic == "" {
+		return "", false
+	}
+	slog.Info("using topic from backlog", "topic", topic, "pinned", !queued, "location", store.Location())
+	return topic, true
+}
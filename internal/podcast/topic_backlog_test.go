@@ -0,0 +1,91 @@
+package podcast
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"yodex/internal/config"
+)
+
+func TestSelectTopicTakesBacklogFirst(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.TopicHistoryPath = filepath.Join(dir, "topic-history.json")
+	cfg.TopicBacklogPath = filepath.Join(dir, "topic-backlog.json")
+	store, err := OpenTopicBacklog(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("OpenTopicBacklog: %v", err)
+	}
+	err = store.Update(context.Background(), func(b *TopicBacklog) error {
+		b.Pins["2026-07-20"] = "The Moon Landing"
+		b.Queue = append(b.Queue, "Volcanoes")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("seed backlog: %v", err)
+	}
+
+	gen := &fakeTextGen{text: "Coral Reefs"}
+	for _, tc := range []struct {
+		date time.Time
+		want string
+	}{
+		{time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC), "The Moon Landing"},
+		{time.Date(2026, 7, 21, 0, 0, 0, 0, time.UTC), "Volcanoes"},
+	} {
+		topic, err := SelectTopic(context.Background(), tc.date, cfg, gen)
+		if err != nil {
+			t.Fatalf("SelectTopic: %v", err)
+		}
+		if topic != tc.want {
+			t.Fatalf("expected %q from the backlog, got %q", tc.want, topic)
+		}
+	}
+	if gen.called {
+		t.Fatalf("expected backlog topics without calling the generator")
+	}
+	topic, err := SelectTopic(context.Background(), time.Date(2026, 7, 22, 0, 0, 0, 0, time.UTC), cfg, gen)
+	if err != nil || topic != "Coral Reefs" {
+		t.Fatalf("expected generation once the backlog is empty, got %q, %v", topic, err)
+	}
+
+	backlog, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(backlog.Pins) != 0 || len(backlog.Queue) != 0 {
+		t.Fatalf("expected backlog consumed, got %+v", backlog)
+	}
+	history := readTestTopicHistory(t, cfg)
+	if history.Entries["2026-07-20"].Topic != "The Moon Landing" || history.Entries["2026-07-21"].Topic != "Volcanoes" {
+		t.Fatalf("expected backlog topics recorded in history, got %+v", history.Entries)
+	}
+	if history.Entries["2026-07-20"].Backlog != BacklogPin || history.Entries["2026-07-21"].Backlog != BacklogQueue {
+		t.Fatalf("expected the backlog source recorded, got %+v", history.Entries)
+	}
+
+	gen.called = false
+	topic, err = SelectTopic(context.Background(), time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC), cfg, gen)
+	if err != nil || topic != "The Moon Landing" || gen.called {
+		t.Fatalf("expected a rerun to reuse the pinned topic, got %q, %v (generated: %v)", topic, err, gen.called)
+	}
+}
+
+func TestTopicBacklogReturn(t *testing.T) {
+	backlog, err := ParseTopicBacklog([]byte(`{"pins": {"2026-07-21": "Owls"}, "queue": ["Volcanoes"]}`))
+	if err != nil {
+		t.Fatalf("ParseTopicBacklog: %v", err)
+	}
+	backlog.Return("2026-07-20", "The Moon Landing", BacklogPin)
+	backlog.Return("2026-07-21", "Bats", BacklogPin)
+	backlog.Return("2026-07-22", "Coral Reefs", BacklogQueue)
+	backlog.Return("2026-07-23", "Volcanoes", BacklogQueue)
+	if backlog.Pins["2026-07-20"] != "The Moon Landing" || backlog.Pins["2026-07-21"] != "Owls" {
+		t.Fatalf("unexpected pins: %v", backlog.Pins)
+	}
+	if len(backlog.Queue) != 2 || backlog.Queue[0] != "Coral Reefs" {
+		t.Fatalf("unexpected queue: %v", backlog.Queue)
+	}
+}
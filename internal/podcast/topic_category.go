@@ -0,0 +1,149 @@
+package podcast
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"yodex/internal/ai"
+	"yodex/internal/config"
+)
+
+// categoryPlan is the category guidance for one episode's topic: the theme
+// week it belongs to, or, with category balancing on, the categories to
+// avoid and the least used ones to prefer.
+type categoryPlan struct {
+	theme      string
+	categories []string
+	avoid      map[string]string // category -> why it is avoided
+	prefer     []string
+}
+
+// planTopicCategories works out the category guidance for date from the
+// categories of earlier episodes in history. A theme week replaces the
+// category rules.
+func planTopicCategories(cfg config.Config, history TopicHistory, date time.Time) categoryPlan {
+	plan := categoryPlan{avoid: map[string]string{}}
+	if week, ok := cfg.ThemeOn(date); ok {
+		plan.theme = strings.TrimSpace(week.Name)
+		return plan
+	}
+	if !cfg.CategoryBalance {
+		return plan
+	}
+	for _, category := range cfg.TopicCategories {
+		if category = normalizeCategory(category); category != "" {
+			plan.categories = append(plan.categories, category)
+		}
+	}
+
+	// Categories of earlier episodes, newest first; "" for unclassified ones.
+	key := date.Format("2006-01-02")
+	var recent []string
+	dates := history.Dates()
+	for i := len(dates) - 1; i >= 0; i-- {
+		if dates[i] < key {
+			recent = append(recent, normalizeCategory(history.Entries[dates[i]].Category))
+		}
+	}
+	counts := map[string]int{}
+	for i := 0; i < len(recent) && i < cfg.CategoryWindow; i++ {
+		counts[recent[i]]++
+	}
+	for i := 0; i < len(recent) && i < cfg.CategoryCooldown; i++ {
+		if category := recent[i]; category != "" {
+			plan.avoid[category] = fmt.Sprintf("used in the last %d episodes", cfg.CategoryCooldown)
+		}
+	}
+	for category, quota := range cfg.CategoryQuotas {
+		category = normalizeCategory(category)
+		if counts[category] >= quota {
+			plan.avoid[category] = fmt.Sprintf("used %d times in the last %d episodes", counts[category], cfg.CategoryWindow)
+		}
+	}
+
+	least := math.MaxInt
+	for _, category := range plan.categories {
+		if _, ok := plan.avoid[category]; ok {
+			continue
+		}
+		switch n := counts[category]; {
+		case n < least:
+			least = n
+			plan.prefer = []string{category}
+		case n == least:
+			plan.prefer = append(plan.prefer, category)
+		}
+	}
+	if len(plan.prefer) == 0 {
+		// Every category is ruled out; balancing cannot help this time.
+		plan.avoid = map[string]string{}
+	}
+	return plan
+}
+
+// balancing reports whether candidates are classified and checked.
+func (p categoryPlan) balancing() bool {
+	return len(p.categories) > 0
+}
+
+// allowed reports whether category may be used, and why not.
+func (p categoryPlan) allowed(category string) (bool, string) {
+	why, avoided := p.avoid[normalizeCategory(category)]
+	return !avoided, why
+}
+
+// guide adds the theme or category guidance to the topic prompt.
+func (p categoryPlan) guide(prompt string) string {
+	var b strings.Builder
+	b.WriteString(prompt)
+	if p.theme != "" {
+		fmt.Fprintf(&b, "\n\nThis episode is part of %s: the topic must fit that theme.", p.theme)
+	}
+	if len(p.avoid) > 0 {
+		avoid := make([]string, 0, len(p.avoid))
+		for category, why := range p.avoid {
+			avoid = append(avoid, fmt.Sprintf("%s (%s)", category, why))
+		}
+		sort.Strings(avoid)
+		fmt.Fprintf(&b, "\n\nDo not pick a topic in these categories for now: %s.", strings.Join(avoid, "; "))
+	}
+	if len(p.prefer) > 0 && len(p.prefer) < len(p.categories) {
+		fmt.Fprintf(&b, "\n\nThese categories have had the fewest recent episodes; prefer a topic in one of them: %s.", strings.Join(p.prefer, ", "))
+	}
+	return b.String()
+}
+
+// classifyTopic asks the model which category fits topic best. An answer
+// outside categories, or a failed call, leaves the topic unclassified.
+func classifyTopic(ctx context.Context, gen TextGenerator, model string, categories []string, topic string) (string, ai.TokenUsage) {
+	system := "You sort topics for a kids' podcast into categories."
+	prompt := fmt.Sprintf("Categories: %s.\nTopic: %q\nReply with the one category from the list that fits the topic best, exactly as written.", strings.Join(categories, ", "), topic)
+	text, usage, err := generateTopicText(ctx, gen, model, system, prompt)
+	if err != nil {
+		slog.Warn("topic classification failed", "topic", topic, "err", err)
+		return "", usage
+	}
+	answer := normalizeCategory(strings.Trim(strings.TrimSpace(text), `."'`))
+	best := ""
+	for _, category := range categories {
+		if answer == category {
+			return category, usage
+		}
+		if strings.Contains(answer, category) && len(category) > len(best) {
+			best = category
+		}
+	}
+	if best == "" {
+		slog.Warn("topic classification did not match a category", "topic", topic, "answer", text)
+	}
+	return best, usage
+}
+
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.Join(strings.Fields(category), " "))
+}
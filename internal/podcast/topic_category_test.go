@@ -0,0 +1,94 @@
+package podcast
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/config"
+)
+
+func TestPlanTopicCategories(t *testing.T) {
+	cfg := config.Default()
+	cfg.CategoryBalance = true
+	cfg.TopicCategories = []string{"Animals", "Astronomy", "Chemistry", "History"}
+	cfg.CategoryCooldown = 1
+	cfg.CategoryQuotas = map[string]int{"animals": 2}
+	history := TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-03-02": {Topic: "Sea Otters", Category: "animals"},
+		"2026-03-03": {Topic: "Comets", Category: "astronomy"},
+		"2026-03-04": {Topic: "Owls", Category: "animals"},
+		"2026-03-05": {Topic: "Moon Phases", Category: "astronomy"},
+		"2026-03-09": {Topic: "Later Episode", Category: "history"},
+	}}
+	plan := planTopicCategories(cfg, history, time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC))
+	if ok, _ := plan.allowed("astronomy"); ok {
+		t.Fatalf("expected the last episode's category to be on cooldown")
+	}
+	if ok, why := plan.allowed("Animals"); ok || !strings.Contains(why, "used 2 times") {
+		t.Fatalf("expected animals over quota, got %v %q", ok, why)
+	}
+	if strings.Join(plan.prefer, ",") != "chemistry,history" {
+		t.Fatalf("expected unused categories preferred, got %v", plan.prefer)
+	}
+	prompt := plan.guide("Propose a topic.")
+	if !strings.Contains(prompt, "Do not pick a topic in these categories for now: animals (used 2 times in the last 14 episodes); astronomy (used in the last 1 episodes).") {
+		t.Fatalf("unexpected guidance: %q", prompt)
+	}
+	if !strings.Contains(prompt, "prefer a topic in one of them: chemistry, history.") {
+		t.Fatalf("expected preferred categories in guidance: %q", prompt)
+	}
+
+	cfg.ThemeWeeks = []config.ThemeWeek{{Name: "Ocean Week", Start: "2026-03-02", End: "2026-03-08"}}
+	plan = planTopicCategories(cfg, history, time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC))
+	if plan.theme != "Ocean Week" || plan.balancing() {
+		t.Fatalf("expected the theme week to replace category rules, got %+v", plan)
+	}
+	if plan = planTopicCategories(cfg, history, time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)); plan.theme != "" {
+		t.Fatalf("expected no theme on a weekend, got %q", plan.theme)
+	}
+}
+
+func TestSelectTopicRejectsAvoidedCategory(t *testing.T) {
+	cfg := writeTestTopicHistory(t, TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-03-04": {Topic: "Sea Otters", Category: "animals"},
+		"2026-03-05": {Topic: "Owls", Category: "animals"},
+	}})
+	cfg.CategoryBalance = true
+	// Topic and classification replies alternate.
+	gen := &scriptedTopicGen{topics: []string{"Giant Pandas", "Animals.", "The Chemistry of Slime", "chemistry"}}
+	topic, err := SelectTopic(context.Background(), time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC), cfg, gen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if topic != "The Chemistry of Slime" {
+		t.Fatalf("expected the animal topic to be rejected, got %q", topic)
+	}
+	if !strings.Contains(gen.prompts[0], "Do not pick a topic in these categories for now: animals") {
+		t.Fatalf("expected category guidance in prompt, got %q", gen.prompts[0])
+	}
+	if !strings.Contains(gen.prompts[2], "- Giant Pandas (animals was used in the last 2 episodes)") {
+		t.Fatalf("expected rejected candidate in retry prompt, got %q", gen.prompts[2])
+	}
+	entry := readTestTopicHistory(t, cfg).Entries["2026-03-06"]
+	if entry.Category != "chemistry" {
+		t.Fatalf("expected category stored in history, got %+v", entry)
+	}
+}
+
+func TestSelectTopicThemeWeek(t *testing.T) {
+	cfg := writeTestTopicHistory(t, TopicHistory{})
+	cfg.CategoryBalance = true
+	cfg.ThemeWeeks = []config.ThemeWeek{{Name: "Ocean Week", Start: "2026-06-08", End: "2026-06-12"}}
+	gen := &scriptedTopicGen{topics: []string{"Tide Pools"}}
+	if _, err := SelectTopic(context.Background(), time.Date(2026, 6, 9, 0, 0, 0, 0, time.UTC), cfg, gen); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gen.prompts) != 1 || !strings.Contains(gen.prompts[0], "This episode is part of Ocean Week: the topic must fit that theme.") {
+		t.Fatalf("expected one themed prompt, got %q", gen.prompts)
+	}
+	if entry := readTestTopicHistory(t, cfg).Entries["2026-06-09"]; entry.Theme != "Ocean Week" || entry.Category != "" {
+		t.Fatalf("expected theme stored in history, got %+v", entry)
+	}
+}
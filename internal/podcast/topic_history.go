@@ -5,120 +5,208 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"os"
-	"path"
-	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"yodex/internal/config"
-	"yodex/internal/storage"
 )
 
-// TopicHistoryEntry tracks prior topics to avoid repetition.
+// TopicHistoryVersion is the current topic history format. Version 1 (or no
+// version) entries only carry a topic; MigrateTopicHistory upgrades them.
+const TopicHistoryVersion = 2
+
+// TopicHistoryEntry records one episode: its topic, used to avoid repetition,
+// and what was made from it.
 type TopicHistoryEntry struct {
-	Topic string `json:"topic"`
-}
+	Topic     string   `json:"topic"`
+	Title     string   `json:"title,omitempty"`
+	Game      string   `json:"game,omitempty"`
+	Model     string   `json:"model,omitempty"`
+	WordCount int      `json:"wordCount,omitempty"`
+	Keywords  []string `json:"keywords,omitempty"`
+	Published bool     `json:"published,omitempty"`
 
-// TopicHistory is the manifest stored alongside episodes.
-type TopicHistory struct {
-	Entries map[string]TopicHistoryEntry `json:"entries"`
+	// Category is the topic category assigned when category balancing is on,
+	// and Theme the theme week the episode belonged to.
+	Category string `json:"category,omitempty"`
+	Theme    string `json:"theme,omitempty"`
+
+	// Series places the episode in a multi-part series, with the arc planned
+	// when the series began.
+	Series *TopicSeries `json:"series,omitempty"`
+
+	// Backlog is BacklogPin or BacklogQueue when the topic was taken from the
+	// backlog, so an episode that fails can give it back.
+	Backlog string `json:"backlog,omitempty"`
+
+	// Embedding caches the topic's EmbeddingModel vector for the embeddings
+	// topic similarity mode.
+	Embedding      []float32 `json:"embedding,omitempty"`
+	EmbeddingModel string    `json:"embeddingModel,omitempty"`
 }
 
-type topicHistoryStore interface {
-	DownloadBytes(ctx context.Context, key string) ([]byte, error)
-	UploadBytes(ctx context.Context, key string, data []byte, contentType, cacheControl string) error
-	Prefix() string
+// UnmarshalJSON also accepts a bare topic string, the shape of hand-edited
+// histories that map a date straight to a topic.
+func (e *TopicHistoryEntry) UnmarshalJSON(data []byte) error {
+	var topic string
+	if err := json.Unmarshal(data, &topic); err == nil {
+		*e = TopicHistoryEntry{Topic: topic}
+		return nil
+	}
+	type plain TopicHistoryEntry
+	var entry plain
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return err
+	}
+	*e = TopicHistoryEntry(entry)
+	return nil
 }
 
-var newTopicHistoryStore = func(ctx context.Context, cfg config.Config) (topicHistoryStore, error) {
-	return storage.New(ctx, cfg.S3Bucket, cfg.S3Prefix, cfg.Region)
+// TopicHistory is the manifest stored alongside episodes, keyed by
+// YYYY-MM-DD episode date.
+type TopicHistory struct {
+	Version int                          `json:"version,omitempty"`
+	Entries map[string]TopicHistoryEntry `json:"entries"`
 }
 
-func loadTopicHistory(ctx context.Context, cfg config.Config) (TopicHistory, error) {
-	if cfg.S3Bucket != "" {
-		return loadTopicHistoryFromS3(ctx, cfg)
-	}
-	return loadTopicHistoryFromFile(cfg)
+// TopicHistoryStore loads and updates the topic history manifest.
+type TopicHistoryStore interface {
+	// Load returns the stored history, or an empty one if none exists yet.
+	Load(ctx context.Context) (TopicHistory, error)
+	// Update applies fn to the latest stored history and saves the result
+	// without losing writes from overlapping runs. fn may be called more than
+	// once; an error from fn aborts the update without saving.
+	Update(ctx context.Context, fn func(*TopicHistory) error) error
+	// Location describes where the history lives, for logs and messages.
+	Location() string
 }
 
-func saveTopicHistory(ctx context.Context, cfg config.Config, history TopicHistory) error {
-	if cfg.S3Bucket != "" {
-		return saveTopicHistoryToS3(ctx, cfg, history)
+// OpenTopicHistory returns the S3 history when a bucket is configured and the
+// local topicHistoryPath file otherwise. With neither, the history is empty
+// and saves are dropped.
+func OpenTopicHistory(ctx context.Context, cfg config.Config) (TopicHistoryStore, error) {
+	m, err := openManifest(ctx, cfg, "topic-history.json", cfg.TopicHistoryPath)
+	if err != nil {
+		return nil, err
 	}
-	return saveTopicHistoryToFile(cfg, history)
+	return manifestTopicHistory{m}, nil
 }
 
-func appendTopicHistory(ctx context.Context, cfg config.Config, history TopicHistory, date time.Time, entry TopicHistoryEntry) error {
-	if history.Entries == nil {
-		history.Entries = make(map[string]TopicHistoryEntry)
-	}
-	dateKey := date.UTC().Format("2006-01-02")
-	history.Entries[dateKey] = entry
-	return saveTopicHistory(ctx, cfg, history)
+// UpdateTopicHistoryEntry applies update to the stored entry for date (a zero
+// entry if there is none).
+func UpdateTopicHistoryEntry(ctx context.Context, store TopicHistoryStore, date time.Time, update func(*TopicHistoryEntry)) error {
+	key := date.Format("2006-01-02")
+	return store.Update(ctx, func(history *TopicHistory) error {
+		entry := history.Entries[key]
+		update(&entry)
+		history.Entries[key] = entry
+		return nil
+	})
 }
 
-func loadTopicHistoryFromS3(ctx context.Context, cfg config.Config) (TopicHistory, error) {
-	store, err := newTopicHistoryStore(ctx, cfg)
+// loadTopicHistory returns the stored history for topic selection. Failures
+// are logged and treated as an empty history so a missing or broken manifest
+// never blocks an episode.
+func loadTopicHistory(ctx context.Context, cfg config.Config) TopicHistory {
+	store, err := OpenTopicHistory(ctx, cfg)
 	if err != nil {
 		slog.Warn("failed to initialize topic history store", "err", err)
-		return TopicHistory{Entries: map[string]TopicHistoryEntry{}}, nil
+		return TopicHistory{Entries: map[string]TopicHistoryEntry{}}
 	}
-	key := topicHistoryKey(store)
-	data, err := store.DownloadBytes(ctx, key)
+	history, err := store.Load(ctx)
 	if err != nil {
-		slog.Warn("failed to load topic history from s3", "key", key, "err", err)
-		return TopicHistory{Entries: map[string]TopicHistoryEntry{}}, nil
+		slog.Warn("failed to load topic history", "location", store.Location(), "err", err)
+		return TopicHistory{Entries: map[string]TopicHistoryEntry{}}
 	}
-	var history TopicHistory
-	if err := json.Unmarshal(data, &history); err != nil {
-		slog.Warn("failed to parse topic history from s3", "key", key, "err", err)
-		return TopicHistory{Entries: map[string]TopicHistoryEntry{}}, nil
-	}
-	if history.Entries == nil {
-		history.Entries = map[string]TopicHistoryEntry{}
+	return history
+}
+
+// PreviousGame returns the game played in the latest episode before date, or
+// "" if the topic history has none, so ChooseGame can avoid repeating it.
+func PreviousGame(ctx context.Context, cfg config.Config, date time.Time) string {
+	history := loadTopicHistory(ctx, cfg)
+	key := date.Format("2006-01-02")
+	dates := history.Dates()
+	for i := len(dates) - 1; i >= 0; i-- {
+		if dates[i] < key {
+			return history.Entries[dates[i]].Game
+		}
 	}
-	return history, nil
+	return ""
 }
 
-func saveTopicHistoryToS3(ctx context.Context, cfg config.Config, history TopicHistory) error {
-	store, err := newTopicHistoryStore(ctx, cfg)
+// appendTopicHistory stores entry as the topic for date, along with
+// embeddings computed for other entries that had none for model.
+func appendTopicHistory(ctx context.Context, cfg config.Config, date time.Time, entry TopicHistoryEntry, model string, computed map[string]topicEmbedding) error {
+	store, err := OpenTopicHistory(ctx, cfg)
 	if err != nil {
 		return err
 	}
-	key := topicHistoryKey(store)
-	data, err := json.MarshalIndent(history, "", "  ")
+	key := date.Format("2006-01-02")
+	return store.Update(ctx, func(history *TopicHistory) error {
+		history.Entries[key] = entry
+		for k, c := range computed {
+			cached, ok := history.Entries[k]
+			if !ok || k == key || cached.Topic != c.Topic || (len(cached.Embedding) > 0 && cached.EmbeddingModel == model) {
+				continue
+			}
+			cached.Embedding = c.Vector
+			cached.EmbeddingModel = model
+			history.Entries[k] = cached
+		}
+		return nil
+	})
+}
+
+// manifestTopicHistory keeps the history in a manifest: S3 updates are
+// conditional on the ETag read and local ones hold a lock file.
+type manifestTopicHistory struct {
+	m manifest
+}
+
+func (h manifestTopicHistory) Load(ctx context.Context) (TopicHistory, error) {
+	data, err := h.m.read(ctx)
 	if err != nil {
-		return err
+		return TopicHistory{}, err
 	}
-	if err := store.UploadBytes(ctx, key, data, "application/json", "no-cache"); err != nil {
-		return fmt.Errorf("upload topic history: %w", err)
+	if data == nil {
+		return newTopicHistory(), nil
 	}
-	return nil
+	return ParseTopicHistory(data)
 }
 
-func topicHistoryKey(store topicHistoryStore) string {
-	prefix := store.Prefix()
-	if prefix == "" {
-		return "topic-history.json"
-	}
-	return path.Join(prefix, "topic-history.json")
+func (h manifestTopicHistory) Update(ctx context.Context, fn func(*TopicHistory) error) error {
+	return h.m.update(ctx, func(data []byte) ([]byte, error) {
+		history := newTopicHistory()
+		if data != nil {
+			var err error
+			if history, err = ParseTopicHistory(data); err != nil {
+				return nil, err
+			}
+		}
+		if err := fn(&history); err != nil {
+			return nil, err
+		}
+		return MarshalTopicHistory(history)
+	})
 }
 
-func loadTopicHistoryFromFile(cfg config.Config) (TopicHistory, error) {
-	if strings.TrimSpace(cfg.TopicHistoryPath) == "" {
-		return TopicHistory{Entries: map[string]TopicHistoryEntry{}}, nil
-	}
-	data, err := os.ReadFile(cfg.TopicHistoryPath)
-	if err != nil {
-		slog.Warn("failed to read topic history file", "path", cfg.TopicHistoryPath, "err", err)
-		return TopicHistory{Entries: map[string]TopicHistoryEntry{}}, nil
-	}
+func (h manifestTopicHistory) Location() string { return h.m.Location() }
+
+// newTopicHistory returns an empty history in the current format.
+func newTopicHistory() TopicHistory {
+	return TopicHistory{Version: TopicHistoryVersion, Entries: map[string]TopicHistoryEntry{}}
+}
+
+// ParseTopicHistory parses a topic history manifest in any supported version.
+func ParseTopicHistory(data []byte) (TopicHistory, error) {
 	var history TopicHistory
 	if err := json.Unmarshal(data, &history); err != nil {
-		slog.Warn("failed to parse topic history file", "path", cfg.TopicHistoryPath, "err", err)
-		return TopicHistory{Entries: map[string]TopicHistoryEntry{}}, nil
+		return TopicHistory{}, fmt.Errorf("parse topic history: %w", err)
 	}
 	if history.Entries == nil {
 		history.Entries = map[string]TopicHistoryEntry{}
@@ -126,41 +214,128 @@ func loadTopicHistoryFromFile(cfg config.Config) (TopicHistory, error) {
 	return history, nil
 }
 
-func saveTopicHistoryToFile(cfg config.Config, history TopicHistory) error {
-	if strings.TrimSpace(cfg.TopicHistoryPath) == "" {
-		return nil
+// MarshalTopicHistory encodes a topic history manifest as indented JSON.
+func MarshalTopicHistory(history TopicHistory) ([]byte, error) {
+	if history.Entries == nil {
+		history.Entries = map[string]TopicHistoryEntry{}
 	}
+	return json.MarshalIndent(history, "", "  ")
+}
+
+// MigrateTopicHistory upgrades history to TopicHistoryVersion in place: every
+// entry gets keywords from its topic and title, and details (when not nil)
+// fills in a title, game, model, and word count the entry is missing, for
+// example from the episode's meta.json. It returns the number of entries
+// changed.
+func MigrateTopicHistory(history *TopicHistory, details func(date string) (TopicHistoryEntry, bool)) int {
 	if history.Entries == nil {
 		history.Entries = map[string]TopicHistoryEntry{}
 	}
-	if err := os.MkdirAll(filepath.Dir(cfg.TopicHistoryPath), 0o755); err != nil {
-		return err
+	changed := 0
+	for _, key := range history.Dates() {
+		entry := history.Entries[key]
+		before := entry
+		if details != nil {
+			if found, ok := details(key); ok {
+				entry = fillTopicHistoryEntry(entry, found)
+			}
+		}
+		if len(entry.Keywords) == 0 {
+			entry.Keywords = TopicKeywords(entry.Topic, entry.Title)
+		}
+		if !reflect.DeepEqual(entry, before) {
+			history.Entries[key] = entry
+			changed++
+		}
 	}
-	data, err := json.MarshalIndent(history, "", "  ")
-	if err != nil {
-		return err
+	history.Version = TopicHistoryVersion
+	return changed
+}
+
+// fillTopicHistoryEntry copies into entry the fields it is missing.
+func fillTopicHistoryEntry(entry, from TopicHistoryEntry) TopicHistoryEntry {
+	if entry.Topic == "" {
+		entry.Topic = from.Topic
+	}
+	if entry.Title == "" {
+		entry.Title = from.Title
+	}
+	if entry.Game == "" {
+		entry.Game = from.Game
+	}
+	if entry.Model == "" {
+		entry.Model = from.Model
+	}
+	if entry.WordCount == 0 {
+		entry.WordCount = from.WordCount
+	}
+	if len(entry.Keywords) == 0 {
+		entry.Keywords = from.Keywords
+	}
+	if entry.Category == "" {
+		entry.Category = from.Category
+	}
+	if entry.Theme == "" {
+		entry.Theme = from.Theme
+	}
+	if entry.Series == nil {
+		entry.Series = from.Series
+	}
+	entry.Published = entry.Published || from.Published
+	return entry
+}
+
+// keywordStopWords are short function words left out of topic keywords, in
+// the languages yodex writes.
+var keywordStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true,
+	"how": true, "why": true, "what": true, "who": true, "when": true, "where": true,
+	"our": true, "your": true, "their": true, "its": true, "are": true, "was": true,
+	"can": true, "does": true, "this": true, "that": true, "they": true, "about": true,
+	"los": true, "las": true, "del": true, "una": true, "por": true, "para": true,
+	"con": true, "que": true, "cómo": true, "qué": true, "sus": true,
+}
+
+// TopicKeywords returns the distinct lowercase words of at least three
+// letters in texts, in order of first appearance, without common function
+// words.
+func TopicKeywords(texts ...string) []string {
+	seen := map[string]bool{}
+	var keywords []string
+	for _, text := range texts {
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			if utf8.RuneCountInString(word) < 3 || keywordStopWords[word] || seen[word] {
+				continue
+			}
+			seen[word] = true
+			keywords = append(keywords, word)
+		}
 	}
-	return os.WriteFile(cfg.TopicHistoryPath, data, 0o644)
+	return keywords
 }
 
-func sortedHistoryKeys(history TopicHistory) []string {
-	keys := make([]string, 0, len(history.Entries))
-	for key := range history.Entries {
+// Dates returns the history's entry dates, oldest first.
+func (h TopicHistory) Dates() []string {
+	keys := make([]string, 0, len(h.Entries))
+	for key := range h.Entries {
 		keys = append(keys, key)
 	}
 	sort.Strings(keys)
 	return keys
 }
 
-func recentTopics(history TopicHistory) []string {
-	keys := sortedHistoryKeys(history)
-	topics := make([]string, 0, len(keys))
-	for i := len(keys) - 1; i >= 0; i-- {
-		entry := history.Entries[keys[i]]
-		if strings.TrimSpace(entry.Topic) == "" {
-			continue
+// withoutRecaps returns the history without weekly recap episodes, whose
+// topic is not a subject and must not steer topic selection.
+func (h TopicHistory) withoutRecaps() TopicHistory {
+	entries := make(map[string]TopicHistoryEntry, len(h.Entries))
+	for key, entry := range h.Entries {
+		if entry.Topic != RecapTopic {
+			entries[key] = entry
 		}
-		topics = append(topics, strings.TrimSpace(entry.Topic))
 	}
-	return topics
+	h.Entries = entries
+	return h
 }
@@ -0,0 +1,378 @@
+package podcast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"yodex/internal/config"
+	"yodex/internal/storage"
+)
+
+func TestTopicHistoryAcceptsOldEntries(t *testing.T) {
+	data := []byte(`{"entries": {"2026-01-15": {"topic": "Ocean Life"}, "2026-01-16": "Volcanoes"}}`)
+	history, err := ParseTopicHistory(data)
+	if err != nil {
+		t.Fatalf("ParseTopicHistory: %v", err)
+	}
+	if history.Version != 0 {
+		t.Fatalf("expected unversioned history, got %d", history.Version)
+	}
+	if history.Entries["2026-01-15"].Topic != "Ocean Life" || history.Entries["2026-01-16"].Topic != "Volcanoes" {
+		t.Fatalf("unexpected entries: %+v", history.Entries)
+	}
+}
+
+func TestMigrateTopicHistory(t *testing.T) {
+	history := TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-01-15": {Topic: "How Volcanoes Erupt"},
+		"2026-01-16": {Topic: "Ocean Life", Title: "Kept Title", Keywords: []string{"kept"}, Published: true},
+	}}
+	details := func(date string) (TopicHistoryEntry, bool) {
+		if date != "2026-01-16" {
+			return TopicHistoryEntry{}, false
+		}
+		return TopicHistoryEntry{Title: "Meta Title", Game: "would-you-rather", Model: "gpt-test", WordCount: 700}, true
+	}
+	if changed := MigrateTopicHistory(&history, details); changed != 2 {
+		t.Fatalf("expected 2 changed entries, got %d", changed)
+	}
+	if history.Version != TopicHistoryVersion {
+		t.Fatalf("expected version %d, got %d", TopicHistoryVersion, history.Version)
+	}
+	if got := strings.Join(history.Entries["2026-01-15"].Keywords, ","); got != "volcanoes,erupt" {
+		t.Fatalf("unexpected keywords: %s", got)
+	}
+	ocean := history.Entries["2026-01-16"]
+	if ocean.Title != "Kept Title" || ocean.Game != "would-you-rather" || ocean.WordCount != 700 || !ocean.Published {
+		t.Fatalf("expected missing fields filled and existing ones kept, got %+v", ocean)
+	}
+	if changed := MigrateTopicHistory(&history, details); changed != 0 {
+		t.Fatalf("expected migration to be idempotent, changed %d", changed)
+	}
+}
+
+func TestTopicKeywords(t *testing.T) {
+	got := strings.Join(TopicKeywords("Why the Sky Is Blue", "The Blue Sky and Sunsets"), ",")
+	if got != "sky,blue,sunsets" {
+		t.Fatalf("unexpected keywords: %s", got)
+	}
+	if got := strings.Join(TopicKeywords("Los volcanes de México"), ","); got != "volcanes,méxico" {
+		t.Fatalf("unexpected Spanish keywords: %s", got)
+	}
+}
+
+func TestUpdateTopicHistoryEntryOnFileStore(t *testing.T) {
+	cfg := config.Default()
+	cfg.TopicHistoryPath = filepath.Join(t.TempDir(), "history", "topic-history.json")
+	store, err := OpenTopicHistory(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("OpenTopicHistory: %v", err)
+	}
+	date := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)
+	if err := UpdateTopicHistoryEntry(context.Background(), store, date, func(e *TopicHistoryEntry) {
+		e.Topic = "Coral Reefs"
+	}); err != nil {
+		t.Fatalf("UpdateTopicHistoryEntry: %v", err)
+	}
+	if err := UpdateTopicHistoryEntry(context.Background(), store, date, func(e *TopicHistoryEntry) {
+		e.Published = true
+	}); err != nil {
+		t.Fatalf("UpdateTopicHistoryEntry: %v", err)
+	}
+	history, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	entry := history.Entries["2026-01-17"]
+	if entry.Topic != "Coral Reefs" || !entry.Published || history.Version != TopicHistoryVersion {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+	data, err := MarshalTopicHistory(history)
+	if err != nil {
+		t.Fatalf("MarshalTopicHistory: %v", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || raw["version"] == nil {
+		t.Fatalf("expected version in saved history: %s", data)
+	}
+}
+
+// versionedObjectStore is an in-memory object store that honors conditional
+// writes the way S3 does.
+type versionedObjectStore struct {
+	mu      sync.Mutex
+	data    []byte
+	version int
+	// beforePut, if set, runs before each conditional put is checked.
+	beforePut func()
+}
+
+func (v *versionedObjectStore) DownloadBytesWithETag(ctx context.Context, key string) ([]byte, string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.version == 0 {
+		return nil, "", &types.NoSuchKey{}
+	}
+	return append([]byte(nil), v.data...), fmt.Sprintf(`"v%d"`, v.version), nil
+}
+
+func (v *versionedObjectStore) UploadBytesIf(ctx context.Context, key string, data []byte, contentType, cacheControl, etag string) error {
+	if v.beforePut != nil {
+		hook := v.beforePut
+		v.beforePut = nil
+		hook()
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	current := ""
+	if v.version > 0 {
+		current = fmt.Sprintf(`"v%d"`, v.version)
+	}
+	if etag != current {
+		return fmt.Errorf("put %s: %w", key, storage.ErrPreconditionFailed)
+	}
+	v.data = append([]byte(nil), data...)
+	v.version++
+	return nil
+}
+
+func (v *versionedObjectStore) Prefix() string { return "yodex" }
+
+func useVersionedObjectStore(t *testing.T) (*versionedObjectStore, TopicHistoryStore) {
+	t.Helper()
+	objects := &versionedObjectStore{}
+	origStore, origDelay := newManifestStore, manifestRetryDelay
+	t.Cleanup(func() { newManifestStore, manifestRetryDelay = origStore, origDelay })
+	newManifestStore = func(ctx context.Context, cfg config.Config) (manifestStore, error) {
+		return objects, nil
+	}
+	manifestRetryDelay = func(int) time.Duration { return 0 }
+	cfg := config.Default()
+	cfg.S3Bucket = "topic-history-bucket"
+	store, err := OpenTopicHistory(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("OpenTopicHistory: %v", err)
+	}
+	return objects, store
+}
+
+func setTopic(store TopicHistoryStore, day int, topic string) error {
+	date := time.Date(2026, 1, day, 0, 0, 0, 0, time.UTC)
+	return UpdateTopicHistoryEntry(context.Background(), store, date, func(e *TopicHistoryEntry) {
+		e.Topic = topic
+	})
+}
+
+func TestS3TopicHistoryRetriesInterleavedWriter(t *testing.T) {
+	objects, store := useVersionedObjectStore(t)
+	if err := setTopic(store, 1, "Seed"); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	// A second run writes its entry between this run's read and its put.
+	objects.beforePut = func() {
+		if err := setTopic(store, 3, "Backfill"); err != nil {
+			t.Errorf("interleaved writer: %v", err)
+		}
+	}
+	calls := 0
+	err := store.Update(context.Background(), func(h *TopicHistory) error {
+		calls++
+		h.Entries["2026-01-02"] = TopicHistoryEntry{Topic: "Scheduled"}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected the update to be retried once, ran %d times", calls)
+	}
+	history, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, key := range []string{"2026-01-01", "2026-01-02", "2026-01-03"} {
+		if history.Entries[key].Topic == "" {
+			t.Fatalf("lost entry %s: %+v", key, history.Entries)
+		}
+	}
+}
+
+func TestS3TopicHistoryGivesUpAfterRepeatedConflicts(t *testing.T) {
+	objects, store := useVersionedObjectStore(t)
+	origAttempts := manifestAttempts
+	t.Cleanup(func() { manifestAttempts = origAttempts })
+	manifestAttempts = 3
+	calls := 0
+	err := store.Update(context.Background(), func(h *TopicHistory) error {
+		calls++
+		// Another writer always gets in first.
+		objects.beforePut = func() { _ = setTopic(store, 20+calls, "Competing") }
+		h.Entries["2026-01-02"] = TopicHistoryEntry{Topic: "Never Saved"}
+		return nil
+	})
+	if !errors.Is(err, storage.ErrPreconditionFailed) || calls != 3 {
+		t.Fatalf("expected precondition failure after 3 attempts, got %v after %d", err, calls)
+	}
+}
+
+func TestConcurrentTopicHistoryWriters(t *testing.T) {
+	const writers = 8
+	fileCfg := config.Default()
+	fileCfg.TopicHistoryPath = filepath.Join(t.TempDir(), "topic-history.json")
+	fileStore, err := OpenTopicHistory(context.Background(), fileCfg)
+	if err != nil {
+		t.Fatalf("OpenTopicHistory: %v", err)
+	}
+	_, s3Store := useVersionedObjectStore(t)
+	origAttempts := manifestAttempts
+	t.Cleanup(func() { manifestAttempts = origAttempts })
+	manifestAttempts = 100
+
+	for name, store := range map[string]TopicHistoryStore{"file": fileStore, "s3": s3Store} {
+		var wg sync.WaitGroup
+		for i := 1; i <= writers; i++ {
+			wg.Add(1)
+			go func(day int) {
+				defer wg.Done()
+				if err := setTopic(store, day, fmt.Sprintf("Topic %d", day)); err != nil {
+					t.Errorf("%s writer %d: %v", name, day, err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		history, err := store.Load(context.Background())
+		if err != nil {
+			t.Fatalf("%s Load: %v", name, err)
+		}
+		if len(history.Entries) != writers {
+			t.Fatalf("%s: expected %d entries, got %d: %+v", name, writers, len(history.Entries), history.Entries)
+		}
+	}
+	if _, err := os.Stat(fileCfg.TopicHistoryPath + ".lock"); !os.IsNotExist(err) {
+		t.Fatalf("expected lock file released, stat err %v", err)
+	}
+}
+
+func TestFileTopicHistoryLock(t *testing.T) {
+	cfg := config.Default()
+	cfg.TopicHistoryPath = filepath.Join(t.TempDir(), "topic-history.json")
+	store, err := OpenTopicHistory(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("OpenTopicHistory: %v", err)
+	}
+	lockPath := cfg.TopicHistoryPath + ".lock"
+	if err := os.WriteFile(lockPath, []byte("1\n"), 0o644); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+	origTimeout, origStale := manifestLockTimeout, manifestLockStale
+	t.Cleanup(func() { manifestLockTimeout, manifestLockStale = origTimeout, origStale })
+	manifestLockTimeout = 50 * time.Millisecond
+	if err := setTopic(store, 1, "Blocked"); err == nil || !strings.Contains(err.Error(), "locked by another run") {
+		t.Fatalf("expected lock timeout, got %v", err)
+	}
+
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	manifestLockStale = time.Minute
+	if err := setTopic(store, 1, "After Stale Lock"); err != nil {
+		t.Fatalf("expected stale lock to be taken over: %v", err)
+	}
+	history, err := store.Load(context.Background())
+	if err != nil || history.Entries["2026-01-01"].Topic != "After Stale Lock" {
+		t.Fatalf("unexpected history %+v, err %v", history, err)
+	}
+}
+
+func TestManifestLockTakeover(t *testing.T) {
+	origStale := manifestLockStale
+	t.Cleanup(func() { manifestLockStale = origStale })
+	manifestLockStale = time.Minute
+	lockPath := filepath.Join(t.TempDir(), "topic-history.json.lock")
+	makeStale := func() {
+		t.Helper()
+		old := time.Now().Add(-time.Hour)
+		if err := os.Chtimes(lockPath, old, old); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	// A run whose lock was taken over as stale leaves the new holder's lock.
+	release, err := lockManifest(context.Background(), lockPath)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	makeStale()
+	releaseNew, err := lockManifest(context.Background(), lockPath)
+	if err != nil {
+		t.Fatalf("take over stale lock: %v", err)
+	}
+	release()
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Fatalf("expected the new holder's lock to remain: %v", err)
+	}
+	releaseNew()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("expected lock released, stat err %v", err)
+	}
+
+	// Runs waiting on the same stale lock hold it one at a time.
+	if err := os.WriteFile(lockPath, []byte("1\n"), 0o644); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+	makeStale()
+	const waiters = 8
+	var holders, most atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := lockManifest(context.Background(), lockPath)
+			if err != nil {
+				t.Errorf("lock: %v", err)
+				return
+			}
+			n := holders.Add(1)
+			for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+			}
+			time.Sleep(5 * time.Millisecond)
+			holders.Add(-1)
+			release()
+		}()
+	}
+	wg.Wait()
+	if most.Load() != 1 {
+		t.Fatalf("expected one lock holder at a time, got %d", most.Load())
+	}
+	entries, err := os.ReadDir(filepath.Dir(lockPath))
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected no lock files left, got %v, err %v", entries, err)
+	}
+}
+
+func TestPreviousGame(t *testing.T) {
+	cfg := writeTestTopicHistory(t, TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-03-02": {Topic: "Owls", Game: "fact-or-fib"},
+		"2026-03-04": {Topic: "Comets", Game: "would-you-rather"},
+		"2026-03-05": {Topic: "Rerun", Game: "build-it-brainstorm"},
+	}})
+	if game := PreviousGame(context.Background(), cfg, time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)); game != "would-you-rather" {
+		t.Fatalf("expected the latest earlier episode's game, got %q", game)
+	}
+	if game := PreviousGame(context.Background(), cfg, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)); game != "" {
+		t.Fatalf("expected no game before the first episode, got %q", game)
+	}
+}
@@ -0,0 +1,280 @@
+package podcast
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+
+	"yodex/internal/config"
+)
+
+// Embedder turns texts into embedding vectors, one per input.
+type Embedder interface {
+	Embed(ctx context.Context, model string, dimensions int, inputs []string) ([][]float64, error)
+}
+
+const (
+	// defaultNgramThreshold is the trigram Jaccard score at which a topic
+	// counts as a repeat. "Honey Bees" and "Busy Honeybees" score about 0.43,
+	// while "Solar System" and "Solar Power" score about 0.31.
+	defaultNgramThreshold = 0.35
+	// defaultEmbeddingThreshold is the cosine similarity at which a topic
+	// counts as a repeat.
+	defaultEmbeddingThreshold = 0.85
+	// topicEmbeddingDimensions keeps the vectors cached in the history small.
+	topicEmbeddingDimensions = 256
+	// topicAttempts caps topic generations when candidates are rejected.
+	topicAttempts = 3
+)
+
+// topicMatch is the past topic closest to a candidate.
+type topicMatch struct {
+	Date  string
+	Topic string
+	Score float64
+}
+
+// topicEmbedding is a vector computed for a history entry's topic.
+type topicEmbedding struct {
+	Topic  string
+	Vector []float32
+}
+
+// topicSimilarity compares candidate topics with the history, skipping the
+// entry for the date being generated so reruns can replace it.
+type topicSimilarity struct {
+	mode      string
+	threshold float64
+	model     string
+	embedder  Embedder
+	history   TopicHistory
+	skip      string
+
+	// computed holds embeddings made for history entries that had none, to be
+	// written back with the selected topic.
+	computed map[string]topicEmbedding
+}
+
+func newTopicSimilarity(cfg config.Config, gen TextGenerator, history TopicHistory, skip string) *topicSimilarity {
+	s := &topicSimilarity{
+		mode:      strings.ToLower(strings.TrimSpace(cfg.TopicSimilarity)),
+		threshold: cfg.TopicSimilarityThreshold,
+		model:     strings.TrimSpace(cfg.EmbeddingModel),
+		history:   history,
+		skip:      skip,
+		computed:  map[string]topicEmbedding{},
+	}
+	if s.mode == "" {
+		s.mode = config.TopicSimilarityNgram
+	}
+	if s.mode == config.TopicSimilarityEmbeddings {
+		embedder, ok := gen.(Embedder)
+		if !ok || s.model == "" {
+			slog.Warn("embeddings are not available for topic similarity; using n-gram matching")
+			s.useNgram()
+		} else {
+			s.embedder = embedder
+		}
+	}
+	if s.threshold <= 0 {
+		s.threshold = defaultNgramThreshold
+		if s.mode == config.TopicSimilarityEmbeddings {
+			s.threshold = defaultEmbeddingThreshold
+		}
+	}
+	return s
+}
+
+// useNgram switches to n-gram matching. A threshold set for embeddings does
+// not carry over.
+func (s *topicSimilarity) useNgram() {
+	if s.mode == config.TopicSimilarityEmbeddings {
+		s.threshold = 0
+	}
+	s.mode = config.TopicSimilarityNgram
+	if s.threshold <= 0 {
+		s.threshold = defaultNgramThreshold
+	}
+}
+
+// tooSimilar reports whether match is close enough to reject the candidate.
+func (s *topicSimilarity) tooSimilar(match topicMatch) bool {
+	return s.mode != config.TopicSimilarityOff && match.Topic != "" && match.Score >= s.threshold
+}
+
+// closest returns the past topic most similar to topic, and the topic's
+// embedding when embeddings are in use.
+func (s *topicSimilarity) closest(ctx context.Context, topic string) (topicMatch, []float32) {
+	switch s.mode {
+	case config.TopicSimilarityOff:
+		return topicMatch{}, nil
+	case config.TopicSimilarityEmbeddings:
+		match, vector, err := s.closestEmbedding(ctx, topic)
+		if err == nil {
+			return match, vector
+		}
+		slog.Warn("topic embedding failed; using n-gram matching", "err", err)
+		s.useNgram()
+	}
+	var best topicMatch
+	for _, key := range s.history.Dates() {
+		entry := s.history.Entries[key]
+		if key == s.skip || strings.TrimSpace(entry.Topic) == "" {
+			continue
+		}
+		if score := ngramSimilarity(topic, entry.Topic); score > best.Score {
+			best = topicMatch{Date: key, Topic: entry.Topic, Score: score}
+		}
+	}
+	return best, nil
+}
+
+// closestEmbedding embeds the candidate together with any history topics
+// that have no cached vector for the configured model.
+func (s *topicSimilarity) closestEmbedding(ctx context.Context, topic string) (topicMatch, []float32, error) {
+	var missing []string
+	inputs := []string{topic}
+	for _, key := range s.history.Dates() {
+		entry := s.history.Entries[key]
+		if key == s.skip || strings.TrimSpace(entry.Topic) == "" {
+			continue
+		}
+		if _, ok := s.vector(key, entry); ok {
+			continue
+		}
+		missing = append(missing, key)
+		inputs = append(inputs, entry.Topic)
+	}
+	vectors, err := s.embedder.Embed(ctx, s.model, topicEmbeddingDimensions, inputs)
+	if err != nil {
+		return topicMatch{}, nil, err
+	}
+	if len(vectors) != len(inputs) {
+		return topicMatch{}, nil, errors.New("embedding count does not match topics")
+	}
+	for i, key := range missing {
+		s.computed[key] = topicEmbedding{Topic: s.history.Entries[key].Topic, Vector: toFloat32(vectors[i+1])}
+	}
+	candidate := toFloat32(vectors[0])
+
+	var best topicMatch
+	for _, key := range s.history.Dates() {
+		entry := s.history.Entries[key]
+		if key == s.skip || strings.TrimSpace(entry.Topic) == "" {
+			continue
+		}
+		vector, _ := s.vector(key, entry)
+		if score := cosineSimilarity(candidate, vector); score > best.Score {
+			best = topicMatch{Date: key, Topic: entry.Topic, Score: score}
+		}
+	}
+	return best, candidate, nil
+}
+
+// vector returns the cached or newly computed embedding for a history entry.
+func (s *topicSimilarity) vector(key string, entry TopicHistoryEntry) ([]float32, bool) {
+	if len(entry.Embedding) > 0 && entry.EmbeddingModel == s.model {
+		return entry.Embedding, true
+	}
+	if computed, ok := s.computed[key]; ok && computed.Topic == entry.Topic {
+		return computed.Vector, true
+	}
+	return nil, false
+}
+
+// ngramSimilarity is the Jaccard similarity of the character trigrams of two
+// topics' content words, so plurals, compounds, and reordered words still
+// match ("Honey Bees" and "Honeybees").
+func ngramSimilarity(a, b string) float64 {
+	ga, gb := topicTrigrams(a), topicTrigrams(b)
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+	shared := 0
+	for gram := range ga {
+		if gb[gram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ga)+len(gb)-shared)
+}
+
+func topicTrigrams(topic string) map[string]bool {
+	grams := map[string]bool{}
+	for _, word := range TopicKeywords(topic) {
+		runes := []rune(" " + stemTopicWord(word) + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			grams[string(runes[i:i+3])] = true
+		}
+	}
+	return grams
+}
+
+// stemTopicWord strips common English plural endings.
+func stemTopicWord(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "ses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "zes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && len(word) > 3:
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+func toFloat32(v []float64) []float32 {
+	out := make([]float32, len(v))
+	for i, f := range v {
+		out[i] = float32(f)
+	}
+	return out
+}
+
+// topicPromptHistory returns the topics listed in the topic prompt, newest
+// first: at most limit recent topics (0 lists them all) plus the past topics
+// that rejected candidates were too close to.
+func topicPromptHistory(history TopicHistory, skip string, limit int, rejected []rejectedTopic) []string {
+	var topics []string
+	seen := map[string]bool{}
+	add := func(topic string) {
+		topic = strings.TrimSpace(topic)
+		if topic == "" || seen[strings.ToLower(topic)] {
+			return
+		}
+		seen[strings.ToLower(topic)] = true
+		topics = append(topics, topic)
+	}
+	keys := history.Dates()
+	for i := len(keys) - 1; i >= 0; i-- {
+		if keys[i] == skip || strings.TrimSpace(history.Entries[keys[i]].Topic) == "" {
+			continue
+		}
+		if limit > 0 && len(topics) >= limit {
+			break
+		}
+		add(history.Entries[keys[i]].Topic)
+	}
+	for _, r := range rejected {
+		add(r.Match.Topic)
+	}
+	return topics
+}
@@ -0,0 +1,218 @@
+package podcast
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"yodex/internal/config"
+)
+
+// scriptedTopicGen returns its topics in order and records each prompt.
+type scriptedTopicGen struct {
+	topics  []string
+	prompts []string
+}
+
+func (g *scriptedTopicGen) GenerateText(ctx context.Context, model, system, prompt string) (string, error) {
+	g.prompts = append(g.prompts, prompt)
+	topic := g.topics[0]
+	if len(g.topics) > 1 {
+		g.topics = g.topics[1:]
+	}
+	return topic, nil
+}
+
+// embeddingTopicGen embeds texts as counts of a few marker words.
+type embeddingTopicGen struct {
+	scriptedTopicGen
+	inputs [][]string
+	err    error
+}
+
+func (g *embeddingTopicGen) Embed(ctx context.Context, model string, dimensions int, inputs []string) ([][]float64, error) {
+	g.inputs = append(g.inputs, inputs)
+	if g.err != nil {
+		return nil, g.err
+	}
+	vectors := make([][]float64, len(inputs))
+	for i, text := range inputs {
+		text = strings.ToLower(text)
+		vectors[i] = []float64{
+			float64(strings.Count(text, "bee")),
+			float64(strings.Count(text, "ocean") + strings.Count(text, "sea")),
+			float64(strings.Count(text, "volcano")),
+			0.1,
+		}
+	}
+	return vectors, nil
+}
+
+func writeTestTopicHistory(t *testing.T, history TopicHistory) config.Config {
+	t.Helper()
+	cfg := config.Default()
+	cfg.TopicHistoryPath = filepath.Join(t.TempDir(), "topic-history.json")
+	data, err := MarshalTopicHistory(history)
+	if err != nil {
+		t.Fatalf("marshal history: %v", err)
+	}
+	if err := os.WriteFile(cfg.TopicHistoryPath, data, 0o644); err != nil {
+		t.Fatalf("write history: %v", err)
+	}
+	return cfg
+}
+
+func readTestTopicHistory(t *testing.T, cfg config.Config) TopicHistory {
+	t.Helper()
+	data, err := os.ReadFile(cfg.TopicHistoryPath)
+	if err != nil {
+		t.Fatalf("read history: %v", err)
+	}
+	history, err := ParseTopicHistory(data)
+	if err != nil {
+		t.Fatalf("parse history: %v", err)
+	}
+	return history
+}
+
+func TestNgramSimilarity(t *testing.T) {
+	if score := ngramSimilarity("Honey Bees", "Busy Honeybees"); score < defaultNgramThreshold {
+		t.Fatalf("expected honeybee topics to match, got %.2f", score)
+	}
+	if score := ngramSimilarity("Ocean Tides", "Why the Ocean Has Tides"); score < defaultNgramThreshold {
+		t.Fatalf("expected reworded topic to match, got %.2f", score)
+	}
+	if score := ngramSimilarity("Solar System", "Solar Power"); score >= defaultNgramThreshold {
+		t.Fatalf("expected different solar topics to pass, got %.2f", score)
+	}
+	if score := ngramSimilarity("Honey Bees", "Volcanoes"); score != 0 {
+		t.Fatalf("expected unrelated topics to score 0, got %.2f", score)
+	}
+}
+
+func TestSelectTopicRegeneratesNearDuplicate(t *testing.T) {
+	cfg := writeTestTopicHistory(t, TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-01-15": {Topic: "Honey Bees"},
+		"2026-01-16": {Topic: "Volcanoes"},
+	}})
+	gen := &scriptedTopicGen{topics: []string{"Busy Honeybees", "Ocean Tides"}}
+	topic, err := SelectTopic(context.Background(), time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC), cfg, gen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if topic != "Ocean Tides" || len(gen.prompts) != 2 {
+		t.Fatalf("expected near-duplicate to be regenerated, got %q after %d prompts", topic, len(gen.prompts))
+	}
+	if !strings.Contains(gen.prompts[1], `- Busy Honeybees (too close to "Honey Bees")`) {
+		t.Fatalf("expected rejected candidate in retry prompt, got %q", gen.prompts[1])
+	}
+	if got := readTestTopicHistory(t, cfg).Entries["2026-01-17"].Topic; got != "Ocean Tides" {
+		t.Fatalf("expected accepted topic saved, got %q", got)
+	}
+}
+
+func TestSelectTopicKeepsLeastSimilarAfterRetries(t *testing.T) {
+	cfg := writeTestTopicHistory(t, TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-01-15": {Topic: "Honey Bees"},
+	}})
+	gen := &scriptedTopicGen{topics: []string{"Honey Bees", "How Honeybees Make Honey", "Busy Honeybees"}}
+	topic, err := SelectTopic(context.Background(), time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC), cfg, gen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gen.prompts) != topicAttempts || topic != "Busy Honeybees" {
+		t.Fatalf("expected least similar candidate after %d attempts, got %q after %d", topicAttempts, topic, len(gen.prompts))
+	}
+}
+
+func TestSelectTopicSimilarityOff(t *testing.T) {
+	cfg := writeTestTopicHistory(t, TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-01-15": {Topic: "Honey Bees"},
+	}})
+	cfg.TopicSimilarity = config.TopicSimilarityOff
+	gen := &scriptedTopicGen{topics: []string{"Honey Bees"}}
+	if _, err := SelectTopic(context.Background(), time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC), cfg, gen); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gen.prompts) != 1 {
+		t.Fatalf("expected no regeneration with similarity off, got %d prompts", len(gen.prompts))
+	}
+}
+
+func TestSelectTopicEmbeddingsCachesVectors(t *testing.T) {
+	cfg := writeTestTopicHistory(t, TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-01-15": {Topic: "Bumblebee Buzz"},
+		"2026-01-16": {Topic: "Volcanoes"},
+	}})
+	cfg.TopicSimilarity = config.TopicSimilarityEmbeddings
+	gen := &embeddingTopicGen{scriptedTopicGen: scriptedTopicGen{topics: []string{"Honey Bees", "Deep Sea Creatures"}}}
+	topic, err := SelectTopic(context.Background(), time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC), cfg, gen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if topic != "Deep Sea Creatures" {
+		t.Fatalf("expected bee topic rejected by embeddings, got %q", topic)
+	}
+	if len(gen.inputs) != 2 || len(gen.inputs[0]) != 3 || len(gen.inputs[1]) != 1 {
+		t.Fatalf("expected history embedded once and then reused, got %v", gen.inputs)
+	}
+
+	history := readTestTopicHistory(t, cfg)
+	for _, key := range []string{"2026-01-15", "2026-01-16", "2026-01-17"} {
+		entry := history.Entries[key]
+		if len(entry.Embedding) != 4 || entry.EmbeddingModel != cfg.EmbeddingModel {
+			t.Fatalf("expected cached embedding for %s, got %+v", key, entry)
+		}
+	}
+
+	gen = &embeddingTopicGen{scriptedTopicGen: scriptedTopicGen{topics: []string{"Rainforest Frogs"}}}
+	if _, err := SelectTopic(context.Background(), time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC), cfg, gen); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gen.inputs) != 1 || len(gen.inputs[0]) != 1 {
+		t.Fatalf("expected cached history vectors to be reused, got %v", gen.inputs)
+	}
+}
+
+func TestSelectTopicEmbeddingsFallBackToNgram(t *testing.T) {
+	cfg := writeTestTopicHistory(t, TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-01-15": {Topic: "Honey Bees"},
+	}})
+	cfg.TopicSimilarity = config.TopicSimilarityEmbeddings
+	gen := &embeddingTopicGen{
+		scriptedTopicGen: scriptedTopicGen{topics: []string{"Busy Honeybees", "Ocean Tides"}},
+		err:              errors.New("embeddings unavailable"),
+	}
+	topic, err := SelectTopic(context.Background(), time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC), cfg, gen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if topic != "Ocean Tides" || len(gen.inputs) != 1 {
+		t.Fatalf("expected n-gram fallback after one failed embedding call, got %q with %d calls", topic, len(gen.inputs))
+	}
+	if entry := readTestTopicHistory(t, cfg).Entries["2026-01-17"]; len(entry.Embedding) != 0 {
+		t.Fatalf("expected no embedding cached after fallback, got %+v", entry)
+	}
+}
+
+func TestTopicPromptHistoryIsBounded(t *testing.T) {
+	history := TopicHistory{Entries: map[string]TopicHistoryEntry{
+		"2026-01-10": {Topic: "Honey Bees"},
+		"2026-01-11": {Topic: "Dinosaurs"},
+		"2026-01-12": {Topic: "Gravity"},
+		"2026-01-13": {Topic: "Rainforests"},
+		"2026-01-17": {Topic: "Today"},
+	}}
+	rejected := []rejectedTopic{{Topic: "Busy Honeybees", Match: topicMatch{Date: "2026-01-10", Topic: "Honey Bees"}}}
+	got := topicPromptHistory(history, "2026-01-17", 2, rejected)
+	if strings.Join(got, ",") != "Rainforests,Gravity,Honey Bees" {
+		t.Fatalf("unexpected prompt history: %v", got)
+	}
+	if got := topicPromptHistory(history, "2026-01-17", 0, nil); len(got) != 4 {
+		t.Fatalf("expected a zero limit to list all past topics, got %v", got)
+	}
+}
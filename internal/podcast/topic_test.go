@@ -3,6 +3,7 @@ package podcast
 import (
 	"context"
 	"encoding/json"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -34,14 +35,14 @@ type fakeTopicHistoryStore struct {
 	uploadedData []byte
 }
 
-func (f *fakeTopicHistoryStore) DownloadBytes(ctx context.Context, key string) ([]byte, error) {
+func (f *fakeTopicHistoryStore) DownloadBytesWithETag(ctx context.Context, key string) ([]byte, string, error) {
 	if f.downloadErr != nil {
-		return nil, f.downloadErr
+		return nil, "", f.downloadErr
 	}
-	return f.downloadData, nil
+	return f.downloadData, `"etag"`, nil
 }
 
-func (f *fakeTopicHistoryStore) UploadBytes(ctx context.Context, key string, data []byte, contentType, cacheControl string) error {
+func (f *fakeTopicHistoryStore) UploadBytesIf(ctx context.Context, key string, data []byte, contentType, cacheControl, etag string) error {
 	f.uploadedKey = key
 	f.uploadedData = data
 	return nil
@@ -70,6 +71,7 @@ func TestSelectTopicUsesConfig(t *testing.T) {
 
 func TestSelectTopicGenerates(t *testing.T) {
 	cfg := config.Default()
+	cfg.TopicHistoryPath = filepath.Join(t.TempDir(), "topic-history.json")
 	gen := &fakeTextGen{text: "Ocean Wonders\n"}
 	topic, err := SelectTopic(context.Background(), time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC), cfg, gen)
 	if err != nil {
@@ -102,11 +104,11 @@ func TestSelectTopicIncludesHistoryInPrompt(t *testing.T) {
 		prefix:       cfg.S3Prefix,
 		downloadData: data,
 	}
-	newTopicHistoryStore = func(ctx context.Context, cfg config.Config) (topicHistoryStore, error) {
+	newManifestStore = func(ctx context.Context, cfg config.Config) (manifestStore, error) {
 		return fakeStore, nil
 	}
 	t.Cleanup(func() {
-		newTopicHistoryStore = func(ctx context.Context, cfg config.Config) (topicHistoryStore, error) {
+		newManifestStore = func(ctx context.Context, cfg config.Config) (manifestStore, error) {
 			return storage.New(ctx, cfg.S3Bucket, cfg.S3Prefix, cfg.Region)
 		}
 	})
@@ -131,11 +133,11 @@ func TestSelectTopicEmptyHistory(t *testing.T) {
 		prefix:      cfg.S3Prefix,
 		downloadErr: &types.NoSuchKey{},
 	}
-	newTopicHistoryStore = func(ctx context.Context, cfg config.Config) (topicHistoryStore, error) {
+	newManifestStore = func(ctx context.Context, cfg config.Config) (manifestStore, error) {
 		return fakeStore, nil
 	}
 	t.Cleanup(func() {
-		newTopicHistoryStore = func(ctx context.Context, cfg config.Config) (topicHistoryStore, error) {
+		newManifestStore = func(ctx context.Context, cfg config.Config) (manifestStore, error) {
 			return storage.New(ctx, cfg.S3Bucket, cfg.S3Prefix, cfg.Region)
 		}
 	})
@@ -155,6 +157,7 @@ func TestSelectTopicUsesFullHistory(t *testing.T) {
 			"2026-01-10": {Topic: "Rainforests"},
 			"2026-01-11": {Topic: "Dinosaurs"},
 			"2026-01-12": {Topic: "Gravity"},
+			"2026-01-13": {Topic: RecapTopic, Game: RecapQuizGame.Name},
 		},
 	}
 	data, err := json.Marshal(history)
@@ -169,11 +172,11 @@ func TestSelectTopicUsesFullHistory(t *testing.T) {
 		prefix:       cfg.S3Prefix,
 		downloadData: data,
 	}
-	newTopicHistoryStore = func(ctx context.Context, cfg config.Config) (topicHistoryStore, error) {
+	newManifestStore = func(ctx context.Context, cfg config.Config) (manifestStore, error) {
 		return fakeStore, nil
 	}
 	t.Cleanup(func() {
-		newTopicHistoryStore = func(ctx context.Context, cfg config.Config) (topicHistoryStore, error) {
+		newManifestStore = func(ctx context.Context, cfg config.Config) (manifestStore, error) {
 			return storage.New(ctx, cfg.S3Bucket, cfg.S3Prefix, cfg.Region)
 		}
 	})
@@ -188,4 +191,37 @@ func TestSelectTopicUsesFullHistory(t *testing.T) {
 	if !strings.Contains(gen.prompt, "- Gravity") || !strings.Contains(gen.prompt, "- Dinosaurs") {
 		t.Fatalf("expected prompt to include latest history items, got %q", gen.prompt)
 	}
+	if strings.Contains(gen.prompt, RecapTopic) {
+		t.Fatalf("expected the weekly recap to stay out of the prompt history, got %q", gen.prompt)
+	}
+}
+
+func TestTopicPromptMentionsSkyEvents(t *testing.T) {
+	perseids := time.Date(2026, 8, 12, 0, 0, 0, 0, time.UTC)
+	cfg := config.Default()
+	_, prompt, err := buildTopicPrompts(cfg, perseids, nil)
+	if err != nil {
+		t.Fatalf("buildTopicPrompts: %v", err)
+	}
+	if strings.Contains(prompt, "Perseid") {
+		t.Fatalf("expected sky events to be off by default: %q", prompt)
+	}
+
+	cfg.SkyEvents = true
+	_, prompt, err = buildTopicPrompts(cfg, perseids, nil)
+	if err != nil {
+		t.Fatalf("buildTopicPrompts: %v", err)
+	}
+	if !strings.Contains(prompt, "The Perseid meteor shower peaks tonight") || !strings.Contains(prompt, "would be timely") {
+		t.Fatalf("expected sky events in topic prompt: %q", prompt)
+	}
+
+	cfg.SkyEvents = false
+	_, prompt, err = buildTopicPrompts(cfg, perseids, nil)
+	if err != nil {
+		t.Fatalf("buildTopicPrompts: %v", err)
+	}
+	if strings.Contains(prompt, "Perseid") {
+		t.Fatalf("expected no sky events when disabled: %q", prompt)
+	}
 }
@@ -62,8 +62,10 @@ func NewWithClient(bucket, prefix string, client s3API) *Uploader {
 func (u *Uploader) Bucket() string { return u.bucket }
 func (u *Uploader) Prefix() string { return u.prefix }
 
+// KeyForDate returns prefix/YYYY/MM/DD/filename, taking the calendar day in
+// t's location (the episode timezone).
 func (u *Uploader) KeyForDate(t time.Time, filename string) string {
-	y, m, d := t.UTC().Date()
+	y, m, d := t.Date()
 	return joinKey(u.prefix, fmt.Sprintf("%04d", y), fmt.Sprintf("%02d", int(m)), fmt.Sprintf("%02d", d), filename)
 }
 
@@ -111,6 +113,55 @@ func (u *Uploader) UploadBytes(ctx context.Context, key string, data []byte, con
 	return err
 }
 
+// ErrPreconditionFailed is returned by UploadBytesIf when the object changed
+// (or appeared) since it was read.
+var ErrPreconditionFailed = errors.New("object changed since it was read")
+
+// UploadBytesIf uploads data only if the object is unchanged: its ETag still
+// matches etag, or, when etag is empty, it does not exist yet. A lost race
+// returns an error wrapping ErrPreconditionFailed.
+func (u *Uploader) UploadBytesIf(ctx context.Context, key string, data []byte, contentType, cacheControl, etag string) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(u.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	}
+	if etag == "" {
+		input.IfNoneMatch = aws.String("*")
+	} else {
+		input.IfMatch = aws.String(etag)
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if cacheControl != "" {
+		input.CacheControl = aws.String(cacheControl)
+	}
+	_, err := u.client.PutObject(ctx, input)
+	if isConditionFailed(err) {
+		return fmt.Errorf("put %s: %w", key, ErrPreconditionFailed)
+	}
+	return err
+}
+
+// DownloadBytesWithETag downloads an object into memory along with its ETag,
+// for a later UploadBytesIf.
+func (u *Uploader) DownloadBytesWithETag(ctx context.Context, key string) ([]byte, string, error) {
+	out, err := u.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(u.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, aws.ToString(out.ETag), nil
+}
+
 // DownloadBytes downloads an object into memory.
 func (u *Uploader) DownloadBytes(ctx context.Context, key string) ([]byte, error) {
 	out, err := u.client.GetObject(ctx, &s3.GetObjectInput{
@@ -168,6 +219,18 @@ func encodeCopySource(bucket, key string) string {
 	return bucket + "/" + strings.Join(parts, "/")
 }
 
+// isConditionFailed reports whether S3 rejected a conditional write: 412 when
+// the precondition no longer holds, 409 when a concurrent conditional write
+// to the same key won.
+func isConditionFailed(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		code := apiErr.ErrorCode()
+		return code == "PreconditionFailed" || code == "ConditionalRequestConflict"
+	}
+	return false
+}
+
 // IsNotFound returns true when the error indicates the object does not exist.
 func IsNotFound(err error) bool {
 	var noSuchKey *types.NoSuchKey
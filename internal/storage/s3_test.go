@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
@@ -9,13 +10,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
 )
 
 type fakeS3 struct {
 	lastPut  *s3.PutObjectInput
 	lastCopy *s3.CopyObjectInput
 	lastGet  *s3.GetObjectInput
+	putErr   error
 }
 
 func (f *fakeS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
@@ -23,6 +27,9 @@ func (f *fakeS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFn
 	if params.Body != nil {
 		_, _ = io.ReadAll(params.Body)
 	}
+	if f.putErr != nil {
+		return nil, f.putErr
+	}
 	return &s3.PutObjectOutput{}, nil
 }
 
@@ -33,7 +40,7 @@ func (f *fakeS3) CopyObject(ctx context.Context, params *s3.CopyObjectInput, opt
 
 func (f *fakeS3) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
 	f.lastGet = params
-	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("data"))}, nil
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("data")), ETag: aws.String(`"v1"`)}, nil
 }
 
 func TestKeyConstruction(t *testing.T) {
@@ -45,6 +52,10 @@ func TestKeyConstruction(t *testing.T) {
 	if got := u.KeyForLatest("episode.mp3"); got != "yodex/latest/episode.mp3" {
 		t.Fatalf("KeyForLatest mismatch: %s", got)
 	}
+	pacific := time.FixedZone("PDT", -7*60*60)
+	if got := u.KeyForDate(time.Date(2025, 9, 30, 18, 0, 0, 0, pacific), "episode.mp3"); got != "yodex/2025/09/30/episode.mp3" {
+		t.Fatalf("expected the local day in the key, got %s", got)
+	}
 }
 
 func TestUploadAndCopy(t *testing.T) {
@@ -73,3 +84,38 @@ func TestUploadAndCopy(t *testing.T) {
 		t.Fatalf("expected CopyObject to latest key")
 	}
 }
+
+func TestConditionalUpload(t *testing.T) {
+	fake := &fakeS3{}
+	u := NewWithClient("bucket", "yodex", fake)
+	ctx := context.Background()
+
+	data, etag, err := u.DownloadBytesWithETag(ctx, "yodex/topic-history.json")
+	if err != nil || string(data) != "data" || etag != `"v1"` {
+		t.Fatalf("DownloadBytesWithETag = %q, %q, %v", data, etag, err)
+	}
+	if err := u.UploadBytesIf(ctx, "yodex/topic-history.json", []byte("{}"), "application/json", "no-cache", etag); err != nil {
+		t.Fatalf("UploadBytesIf error: %v", err)
+	}
+	if aws.ToString(fake.lastPut.IfMatch) != `"v1"` || fake.lastPut.IfNoneMatch != nil {
+		t.Fatalf("expected If-Match on update, got %+v", fake.lastPut)
+	}
+	if err := u.UploadBytesIf(ctx, "yodex/topic-history.json", []byte("{}"), "application/json", "no-cache", ""); err != nil {
+		t.Fatalf("UploadBytesIf error: %v", err)
+	}
+	if aws.ToString(fake.lastPut.IfNoneMatch) != "*" || fake.lastPut.IfMatch != nil {
+		t.Fatalf("expected If-None-Match on create, got %+v", fake.lastPut)
+	}
+
+	for _, code := range []string{"PreconditionFailed", "ConditionalRequestConflict"} {
+		fake.putErr = &smithy.GenericAPIError{Code: code}
+		err := u.UploadBytesIf(ctx, "yodex/topic-history.json", []byte("{}"), "application/json", "no-cache", etag)
+		if !errors.Is(err, ErrPreconditionFailed) {
+			t.Fatalf("expected ErrPreconditionFailed for %s, got %v", code, err)
+		}
+	}
+	fake.putErr = &smithy.GenericAPIError{Code: "AccessDenied"}
+	if err := u.UploadBytesIf(ctx, "yodex/topic-history.json", []byte("{}"), "", "", etag); err == nil || errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected other errors to pass through, got %v", err)
+	}
+}